@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bytes"
+	"time"
+)
+
+// -------------------------
+// Activity (TUI row sparklines)
+// -------------------------
+
+// The activity series counts transcript/rollout events per minute over the
+// last ten minutes.
+const (
+	activityBuckets = 10
+	activityBucket  = time.Minute
+	activityWindow  = activityBuckets * activityBucket
+)
+
+var timestampKey = []byte(`"timestamp":"`)
+
+// tailEventTimes returns the timestamp of each line in a transcript/rollout
+// tail that is after since, oldest first. It looks for the first "timestamp"
+// key per line instead of decoding every line.
+func tailEventTimes(b []byte, since time.Time) []time.Time {
+	var out []time.Time
+	for len(b) > 0 {
+		line := b
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			b = nil
+		}
+		i := bytes.Index(line, timestampKey)
+		if i < 0 {
+			continue
+		}
+		rest := line[i+len(timestampKey):]
+		end := bytes.IndexByte(rest, '"')
+		if end < 0 {
+			continue
+		}
+		t, err := parseRFC3339ish(string(rest[:end]))
+		if err != nil || !t.After(since) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// activitySeries buckets event times into per-minute counts ending at now,
+// oldest first. It returns nil when nothing happened in the window.
+func activitySeries(times []time.Time, now time.Time) []int {
+	var series []int
+	for _, t := range times {
+		age := now.Sub(t)
+		if age < 0 {
+			age = 0
+		}
+		if age >= activityWindow {
+			continue
+		}
+		if series == nil {
+			series = make([]int, activityBuckets)
+		}
+		series[activityBuckets-1-int(age/activityBucket)]++
+	}
+	return series
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestActivitySeriesFromTail(t *testing.T) {
+	tail := []byte(`{"type":"user","timestamp":"2026-01-02T09:40:00Z"}
+{"type":"assistant","timestamp":"2026-01-02T09:55:10Z","message":{"content":"x"}}
+{"type":"assistant","timestamp":"2026-01-02T09:55:40Z"}
+no timestamp here
+{"timestamp":"2026-01-02T09:59:30Z","type":"event_msg","payload":{"timestamp":"2026-01-02T09:00:00Z"}}
+`)
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	times := tailEventTimes(tail, now.Add(-activityWindow))
+	if len(times) != 3 {
+		t.Fatalf("expected 3 recent events, got %d: %v", len(times), times)
+	}
+	want := []int{0, 0, 0, 0, 0, 2, 0, 0, 0, 1}
+	if got := activitySeries(times, now); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected series: %v", got)
+	}
+	if got := activitySeries(times, now.Add(time.Hour)); got != nil {
+		t.Fatalf("expected no series for an idle session, got %v", got)
+	}
+}
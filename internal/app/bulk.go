@@ -0,0 +1,55 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// -------------------------
+// Bulk actions (TUI selections)
+// -------------------------
+
+// markSessionsEnded records sessions (by provider:id key) as ended. New
+// activity (a Claude hook, a Codex notify or a newer rollout) clears the mark
+// again.
+func markSessionsEnded(keys []string, now time.Time) error {
+	var failed []string
+	for _, key := range keys {
+		provider, id, ok := strings.Cut(key, ":")
+		if !ok || id == "" {
+			failed = append(failed, key)
+			continue
+		}
+		err := updateRecord(Provider(provider), id, func(r *SessionRecord) {
+			at := now
+			r.EndedAt = &at
+			r.Status = StatusEnded
+			r.StatusReason = "marked ended"
+			if r.LastSeen.IsZero() {
+				r.LastSeen = now
+			}
+			recordTransition(r, StatusEnded, "marked ended", now)
+		})
+		if err != nil {
+			failed = append(failed, key)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not mark %d session(s) ended: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// resumeCommand returns the shell command that resumes a session in its
+// working directory.
+func resumeCommand(r SessionRecord) string {
+	cmd := "claude --resume " + shellEscape(r.ID)
+	if r.Provider == ProviderCodex {
+		cmd = "codex resume " + shellEscape(r.ID)
+	}
+	if dir := normalizePlaceholder(r.CWD); dir != "" {
+		cmd = "cd " + shellEscape(dir) + " && " + cmd
+	}
+	return cmd
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMarkSessionsEnded(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+	if err := ensureAppDirs(); err != nil {
+		t.Fatalf("ensureAppDirs error: %v", err)
+	}
+
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	if err := updateRecord(ProviderClaude, "abc", func(r *SessionRecord) {
+		r.Status = StatusWaiting
+		r.LastSeen = now.Add(-time.Minute)
+	}); err != nil {
+		t.Fatalf("updateRecord error: %v", err)
+	}
+	if err := markSessionsEnded([]string{"claude:abc", "codex:def"}, now); err != nil {
+		t.Fatalf("markSessionsEnded error: %v", err)
+	}
+
+	recs, err := loadAllRecords()
+	if err != nil {
+		t.Fatalf("loadAllRecords error: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	for _, r := range recs {
+		if r.EndedAt == nil || !r.EndedAt.Equal(now) || r.Status != StatusEnded {
+			t.Fatalf("expected %s to be ended, got %+v", r.ID, r)
+		}
+		if st, _ := deriveStatus(r, now, defaultConfig()); st != StatusEnded {
+			t.Fatalf("expected derived status ended for %s, got %s", r.ID, st)
+		}
+	}
+
+	if err := markSessionsEnded([]string{"bogus"}, now); err == nil {
+		t.Fatalf("expected an error for a malformed key")
+	}
+}
+
+func TestMarkedEndedCodexSessionRevives(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+	if err := ensureAppDirs(); err != nil {
+		t.Fatalf("ensureAppDirs error: %v", err)
+	}
+
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	if err := markSessionsEnded([]string{"codex:def"}, now); err != nil {
+		t.Fatalf("markSessionsEnded error: %v", err)
+	}
+	p, err := recordPath(ProviderCodex, "def")
+	if err != nil {
+		t.Fatalf("recordPath error: %v", err)
+	}
+	rec, err := loadRecord(p)
+	if err != nil {
+		t.Fatalf("loadRecord error: %v", err)
+	}
+
+	// A newer rollout scan clears the mark; an older one does not.
+	merged := map[string]SessionRecord{keyFor(ProviderCodex, "def"): rec}
+	mergeInto(merged, SessionRecord{Provider: ProviderCodex, ID: "def", LastSeen: now.Add(-time.Minute)})
+	if merged[keyFor(ProviderCodex, "def")].EndedAt == nil {
+		t.Fatalf("expected an older rollout to keep the ended mark")
+	}
+	mergeInto(merged, SessionRecord{Provider: ProviderCodex, ID: "def", LastSeen: now.Add(time.Minute), Status: StatusRunning})
+	if r := merged[keyFor(ProviderCodex, "def")]; r.EndedAt != nil || r.Status != StatusRunning {
+		t.Fatalf("expected a newer rollout to revive the session, got %+v", r)
+	}
+
+	// So does a notify after the mark.
+	b, _ := json.Marshal(CodexNotifyPatch{SessionID: "def", At: now.Add(2 * time.Minute).Format(time.RFC3339Nano)})
+	if err := applyCodexNotifyPatch(b); err != nil {
+		t.Fatalf("applyCodexNotifyPatch error: %v", err)
+	}
+	rec, err = loadRecord(p)
+	if err != nil {
+		t.Fatalf("loadRecord error: %v", err)
+	}
+	if rec.EndedAt != nil {
+		t.Fatalf("expected notify to clear the ended mark, got %+v", rec.EndedAt)
+	}
+	if st, _ := deriveStatus(rec, now.Add(2*time.Minute), defaultConfig()); st == StatusEnded {
+		t.Fatalf("expected a revived session, got %s", st)
+	}
+}
+
+func TestResumeCommand(t *testing.T) {
+	cases := []struct {
+		rec  SessionRecord
+		want string
+	}{
+		{SessionRecord{Provider: ProviderClaude, ID: "abc", CWD: "/tmp/my proj"}, "cd '/tmp/my proj' && claude --resume abc"},
+		{SessionRecord{Provider: ProviderCodex, ID: "def"}, "codex resume def"},
+	}
+	for _, c := range cases {
+		if got := resumeCommand(c.rec); got != c.want {
+			t.Fatalf("resumeCommand(%s) = %q, want %q", c.rec.ID, got, c.want)
+		}
+	}
+}
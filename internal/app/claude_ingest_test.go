@@ -3,7 +3,9 @@ package app
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"testing"
+	"time"
 )
 
 func TestIngestClaudeStatusline(t *testing.T) {
@@ -69,3 +71,52 @@ func TestIngestClaudeStatusline(t *testing.T) {
 		t.Fatalf("unexpected cost: %v", rec.CostUSD)
 	}
 }
+
+func TestCollapsedToolEventsKeepFirstTransitionTime(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	hook := func(event string, extra map[string]any) time.Time {
+		payload := map[string]any{"hook_event_name": event, "session_id": "sess-collapse", "cwd": "/tmp/alpha"}
+		for k, v := range extra {
+			payload[k] = v
+		}
+		b, _ := json.Marshal(payload)
+		before := time.Now().UTC()
+		if err := ingestClaudeHook(bytes.NewReader(b), io.Discard); err != nil {
+			t.Fatalf("ingestClaudeHook(%s) error: %v", event, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		return before
+	}
+
+	hook("UserPromptSubmit", nil)
+	hook("Notification", map[string]any{"notification_type": "permission_prompt"})
+	firstTool := hook("PostToolUse", nil)
+	collapsedTool := hook("PostToolUse", nil)
+	hook("Notification", map[string]any{"notification_type": "permission_prompt"})
+	secondTool := hook("PreToolUse", nil)
+	lastTool := hook("PostToolUse", nil)
+
+	if err := drainClaudeSpool(); err != nil {
+		t.Fatalf("drainClaudeSpool error: %v", err)
+	}
+	p, _ := recordPath(ProviderClaude, "sess-collapse")
+	rec, err := loadRecord(p)
+	if err != nil {
+		t.Fatalf("loadRecord error: %v", err)
+	}
+	var got []Status
+	for _, tr := range rec.Transitions {
+		got = append(got, tr.Status)
+	}
+	if len(got) != 5 || got[2] != StatusRunning || got[3] != StatusApproval || got[4] != StatusRunning {
+		t.Fatalf("unexpected transitions: %+v", rec.Transitions)
+	}
+	if at := rec.Transitions[2].At; at.Before(firstTool) || !at.Before(collapsedTool) {
+		t.Fatalf("first resume at %s, want the first tool event (%s)", at, firstTool)
+	}
+	if at := rec.Transitions[4].At; at.Before(secondTool) || !at.Before(lastTool) {
+		t.Fatalf("second resume at %s, want the first tool event after the prompt (%s)", at, secondTool)
+	}
+}
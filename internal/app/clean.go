@@ -54,37 +54,46 @@ func newCleanCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without deleting")
-	cmd.Flags().BoolVar(&cleanSpool, "spool", true, "Clean spool files")
-	cmd.Flags().BoolVar(&cleanSessions, "sessions", true, "Clean invalid session records")
+	cmd.Flags().BoolVar(&cleanSpool, "spool", true, "Clean spool files (and quarantined ones)")
+	cmd.Flags().BoolVar(&cleanSessions, "sessions", true, "Clean invalid session records and stale lock files")
 	return cmd
 }
 
+// cleanSpoolData removes the spool and the quarantined spool files.
 func cleanSpoolData(dryRun bool) (int, error) {
 	sd, err := spoolDir()
 	if err != nil {
 		return 0, err
 	}
-	var files []string
-	_ = filepath.WalkDir(sd, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
+	qd, err := quarantineDir()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, dir := range []string{sd, qd} {
+		var files []string
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			files = append(files, path)
 			return nil
+		})
+		if len(files) == 0 {
+			continue
 		}
-		if d.IsDir() {
-			return nil
+		total += len(files)
+		if dryRun {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return total, err
 		}
-		files = append(files, path)
-		return nil
-	})
-	if len(files) == 0 {
-		return 0, nil
-	}
-	if dryRun {
-		return len(files), nil
-	}
-	if err := os.RemoveAll(sd); err != nil {
-		return 0, err
 	}
-	return len(files), nil
+	return total, nil
 }
 
 func cleanInvalidSessions(dryRun bool) (int, error) {
@@ -119,5 +128,12 @@ func cleanInvalidSessions(dryRun bool) (int, error) {
 			removed++
 		}
 	}
+	// Lock files of records that are gone
+	for _, p := range staleLockFiles() {
+		if !dryRun {
+			_ = os.Remove(p)
+		}
+		removed++
+	}
 	return removed, nil
 }
@@ -0,0 +1,425 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// -------------------------
+// Codex config.toml (notify chaining)
+// -------------------------
+
+// codexNotifySettings are the notify commands set in a Codex config.toml: the
+// top level and every [profiles.<name>] table that sets its own.
+type codexNotifySettings struct {
+	Top      []string            // nil when unset
+	Profiles map[string][]string // profile -> notify argv
+}
+
+// targets lists where aistat installs a notify wrapper: the top level ("")
+// plus each profile with its own notify, sorted.
+func (s codexNotifySettings) targets() []string {
+	out := []string{""}
+	for p := range s.Profiles {
+		out = append(out, p)
+	}
+	sort.Strings(out[1:])
+	return out
+}
+
+func (s codexNotifySettings) notify(profile string) []string {
+	if profile == "" {
+		return s.Top
+	}
+	return s.Profiles[profile]
+}
+
+// parseCodexNotify reads the notify settings from config.toml content.
+func parseCodexNotify(content string) (codexNotifySettings, error) {
+	var raw map[string]any
+	if _, err := toml.Decode(content, &raw); err != nil {
+		return codexNotifySettings{}, err
+	}
+	var s codexNotifySettings
+	if v, ok := raw["notify"]; ok {
+		argv, err := notifyArgv(v)
+		if err != nil {
+			return codexNotifySettings{}, err
+		}
+		s.Top = argv
+	}
+	profiles, _ := raw["profiles"].(map[string]any)
+	for name, p := range profiles {
+		table, _ := p.(map[string]any)
+		v, ok := table["notify"]
+		if !ok {
+			continue
+		}
+		argv, err := notifyArgv(v)
+		if err != nil {
+			return codexNotifySettings{}, fmt.Errorf("profiles.%s: %w", name, err)
+		}
+		if s.Profiles == nil {
+			s.Profiles = map[string][]string{}
+		}
+		s.Profiles[name] = argv
+	}
+	return s, nil
+}
+
+func notifyArgv(v any) ([]string, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, errors.New("notify must be an array of strings")
+	}
+	argv := make([]string, 0, len(items))
+	for _, it := range items {
+		s, ok := it.(string)
+		if !ok {
+			return nil, errors.New("notify must be an array of strings")
+		}
+		argv = append(argv, s)
+	}
+	return argv, nil
+}
+
+// classifyNotifyArgv tells aistat's wrapper for this target apart from a
+// direct (legacy) aistat call, another aistat wrapper and the user's own
+// program.
+func classifyNotifyArgv(argv []string, exe, wrapper string) codexNotifyState {
+	if len(argv) == 0 {
+		return codexNotifyMissing
+	}
+	if argv[0] == wrapper {
+		return codexNotifyWrapper
+	}
+	for _, a := range argv {
+		if strings.Contains(a, "aistat-codex-notify") || a == "codex-notify" && (strings.Contains(argv[0], "aistat") || argv[0] == exe) {
+			return codexNotifyUnsafe
+		}
+	}
+	return codexNotifyOther
+}
+
+var tomlHeaderRe = regexp.MustCompile(`^\s*\[([^\[\]]+)\]\s*(#.*)?$`)
+
+// tomlTablePath splits a table header name into its keys
+// (profiles."my x" -> [profiles, my x]).
+func tomlTablePath(name string) []string {
+	var (
+		parts []string
+		cur   strings.Builder
+		quote rune
+	)
+	for _, r := range name {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '.':
+			parts = append(parts, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		case r == ' ' || r == '\t':
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	return append(parts, strings.TrimSpace(cur.String()))
+}
+
+// tomlValueEnd returns the index of the line where the value starting on
+// lines[start] ends, following arrays across lines.
+func tomlValueEnd(lines []string, start int) int {
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if i == start {
+			line = line[strings.Index(line, "=")+1:]
+		}
+		var quote rune
+		escaped := false
+	scan:
+		for _, r := range line {
+			switch {
+			case escaped:
+				escaped = false
+			case quote == '"' && r == '\\':
+				escaped = true
+			case quote != 0:
+				if r == quote {
+					quote = 0
+				}
+			case r == '"' || r == '\'':
+				quote = r
+			case r == '#':
+				break scan
+			case r == '[':
+				depth++
+			case r == ']':
+				depth--
+			}
+		}
+		if depth <= 0 {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
+
+// codexNotifyLocation is where notify of the top level or a profile sits in
+// config.toml lines: the value's first and last line (-1 when unset), the
+// profile's table header and the file's first table header (-1 when absent).
+type codexNotifyLocation struct {
+	start, end, header, firstHeader int
+}
+
+func locateCodexNotify(lines []string, profile string) codexNotifyLocation {
+	var want []string
+	if profile != "" {
+		want = []string{"profiles", profile}
+	}
+	loc := codexNotifyLocation{start: -1, end: -1, header: -1, firstHeader: -1}
+	inTable := profile == ""
+	for i := 0; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "[[") {
+			// Array of tables
+			if loc.firstHeader < 0 {
+				loc.firstHeader = i
+			}
+			inTable = false
+			continue
+		}
+		if m := tomlHeaderRe.FindStringSubmatch(lines[i]); m != nil {
+			if loc.firstHeader < 0 {
+				loc.firstHeader = i
+			}
+			inTable = slices.Equal(tomlTablePath(m[1]), want)
+			if inTable {
+				loc.header = i
+			}
+			continue
+		}
+		if !inTable || !strings.HasPrefix(strings.TrimSpace(lines[i]), "notify") {
+			continue
+		}
+		key, _, ok := strings.Cut(strings.TrimSpace(lines[i]), "=")
+		if !ok || strings.TrimSpace(key) != "notify" {
+			continue
+		}
+		loc.start, loc.end = i, tomlValueEnd(lines, i)
+		return loc
+	}
+	return loc
+}
+
+// setCodexNotify points notify of the top level ("") or a profile at argv,
+// keeping the rest of the file (comments, order) as it is. The result is
+// parsed again to make sure the edit took.
+func setCodexNotify(content, profile string, argv []string) (string, error) {
+	quoted := make([]string, len(argv))
+	for i, a := range argv {
+		quoted[i] = fmt.Sprintf("%q", a)
+	}
+	line := "notify = [" + strings.Join(quoted, ", ") + "]"
+
+	lines := strings.Split(content, "\n")
+	loc := locateCodexNotify(lines, profile)
+	switch {
+	case loc.start >= 0:
+		lines = append(lines[:loc.start], append([]string{line}, lines[loc.end+1:]...)...)
+	// Not set yet: add it to the top of its table
+	case profile == "" && loc.firstHeader >= 0:
+		lines = slices.Insert(lines, loc.firstHeader, line)
+	case profile == "":
+		lines = append(lines, line)
+	case loc.header >= 0:
+		lines = slices.Insert(lines, loc.header+1, line)
+	default:
+		lines = append(lines, "", "[profiles."+fmt.Sprintf("%q", profile)+"]", line)
+	}
+	return checkCodexNotify(strings.Join(lines, "\n"), profile, argv)
+}
+
+// unsetCodexNotify removes notify from the top level ("") or a profile.
+func unsetCodexNotify(content, profile string) (string, error) {
+	lines := strings.Split(content, "\n")
+	loc := locateCodexNotify(lines, profile)
+	if loc.start < 0 {
+		return content, nil
+	}
+	lines = append(lines[:loc.start], lines[loc.end+1:]...)
+	return checkCodexNotify(strings.Join(lines, "\n"), profile, nil)
+}
+
+func checkCodexNotify(content, profile string, argv []string) (string, error) {
+	s, err := parseCodexNotify(content)
+	if err != nil || !slices.Equal(s.notify(profile), argv) {
+		where := "notify"
+		if profile != "" {
+			where = "profiles." + profile + ".notify"
+		}
+		if argv == nil {
+			return "", fmt.Errorf("could not remove %s; remove it by hand", where)
+		}
+		return "", fmt.Errorf("could not update %s; set it to [%q] by hand", where, strings.Join(argv, `", "`))
+	}
+	return content, nil
+}
+
+// codexNotifyWrapperName is the wrapper for the top level or a profile of a
+// config.toml. The name is tagged with a hash of the config path alone, so
+// it does not depend on CODEX_HOME at the time aistat runs and wrappers of
+// different configs don't collide.
+func codexNotifyWrapperName(cfgPath, profile string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(filepath.Clean(cfgPath)))
+	name := fmt.Sprintf("aistat-codex-notify-%08x", h.Sum32())
+	if profile != "" {
+		name += "-" + fileSafeRe.ReplaceAllString(profile, "_")
+	}
+	return name
+}
+
+// codexNotifyChain records the notify commands aistat's wrappers replaced and
+// call on, by profile ("" for the top level).
+type codexNotifyChain map[string][]string
+
+func codexNotifyChainPath() (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "codex_notify_chain.json"), nil
+}
+
+// loadCodexNotifyChains returns the notify chains by config.toml path.
+func loadCodexNotifyChains() (map[string]codexNotifyChain, error) {
+	chains := map[string]codexNotifyChain{}
+	p, err := codexNotifyChainPath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return chains, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &chains); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+	return chains, nil
+}
+
+// loadCodexNotifyChain returns the notify chain of a config.toml (never nil).
+func loadCodexNotifyChain(cfgPath string) (codexNotifyChain, error) {
+	chains, err := loadCodexNotifyChains()
+	if err != nil {
+		return nil, err
+	}
+	if chains[cfgPath] == nil {
+		return codexNotifyChain{}, nil
+	}
+	return chains[cfgPath], nil
+}
+
+// saveCodexNotifyChain records the notify chain of a config.toml; an empty
+// chain removes it.
+func saveCodexNotifyChain(cfgPath string, chain codexNotifyChain) error {
+	chains, err := loadCodexNotifyChains()
+	if err != nil {
+		return err
+	}
+	if len(chain) == 0 {
+		delete(chains, cfgPath)
+	} else {
+		chains[cfgPath] = chain
+	}
+	p, err := codexNotifyChainPath()
+	if err != nil {
+		return err
+	}
+	if len(chains) == 0 {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return writeJSONAtomic(p, chains)
+}
+
+// codexNotifyScript is the notify wrapper: it hands the payload to aistat in
+// the background and, when chained, runs the previous notify program with
+// the same payload.
+func codexNotifyScript(exe string, chained []string) string {
+	chainArgv := ""
+	if len(chained) > 0 {
+		quoted := make([]string, len(chained))
+		for i, a := range chained {
+			quoted[i] = shellQuote(a)
+		}
+		chainArgv = strings.Join(quoted, " ")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `#!/bin/sh
+# Generated by aistat. Safe Codex notify wrapper.
+run_detached() {
+  if command -v setsid >/dev/null 2>&1; then
+    setsid "$@"
+    return
+  fi
+  if command -v perl >/dev/null 2>&1; then
+    perl -MPOSIX -e 'POSIX::setsid(); exec @ARGV' -- "$@"
+    return
+  fi
+  "$@"
+}
+`)
+	if chainArgv != "" {
+		fmt.Fprintf(&b, "# Chained: the notify program set before aistat was installed (skipped by doctor --selftest).\n")
+	}
+	fmt.Fprintf(&b, `# Codex passes the payload as the last argument; older setups pipe it.
+if [ "$#" -gt 0 ]; then
+  (run_detached env TERM=dumb NO_COLOR=1 %s ingest codex-notify "$@" </dev/null >/dev/null 2>&1) >/dev/null 2>&1 &
+`, shellEscape(exe))
+	if chainArgv != "" {
+		fmt.Fprintf(&b, "  [ -n \"${%s:-}\" ] || %s \"$@\" </dev/null\n", selftestEnv, chainArgv)
+	}
+	fmt.Fprintf(&b, `  exit 0
+fi
+[ -t 0 ] && exit 0
+tmp="${TMPDIR:-/tmp}/aistat-codex-notify.$$"
+if ! cat >"$tmp"; then
+  rm -f "$tmp"
+  exit 0
+fi
+`)
+	if chainArgv != "" {
+		fmt.Fprintf(&b, "[ -n \"${%s:-}\" ] || %s <\"$tmp\"\n", selftestEnv, chainArgv)
+	}
+	fmt.Fprintf(&b, `(run_detached env TERM=dumb NO_COLOR=1 %s ingest codex-notify <"$tmp" >/dev/null 2>&1; rm -f "$tmp") >/dev/null 2>&1 &
+exit 0
+`, shellEscape(exe))
+	return b.String()
+}
+
+// shellQuote single-quotes an argument for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIngestCodexNotifyShapes(t *testing.T) {
+	cases := []struct {
+		name          string
+		stdin         string
+		args          []string
+		id            string
+		cwd           string
+		lastUser      string
+		lastAssistant string
+	}{
+		{
+			name:  "legacy stdin",
+			stdin: `{"type":"agent-turn-complete","timestamp":"2026-01-02T10:00:00Z","data":{"session_id":"sess-legacy","cwd":"/tmp/legacy","message":"done","turn_id":"t1"}}`,
+			id:    "sess-legacy",
+			cwd:   "/tmp/legacy",
+		},
+		{
+			name:          "hyphenated argv",
+			args:          []string{`{"type":"agent-turn-complete","thread-id":"sess-argv","turn-id":"t2","cwd":"/tmp/argv","input-messages":["fix the build","<environment_context><cwd>/tmp/argv</cwd></environment_context>"],"last-assistant-message":"Build fixed."}`},
+			id:            "sess-argv",
+			cwd:           "/tmp/argv",
+			lastUser:      "fix the build",
+			lastAssistant: "Build fixed.",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			root := t.TempDir()
+			t.Setenv("AISTAT_HOME", root)
+
+			if err := ingestCodexNotify(strings.NewReader(c.stdin), c.args); err != nil {
+				t.Fatalf("ingestCodexNotify error: %v", err)
+			}
+			if err := drainCodexSpool(); err != nil {
+				t.Fatalf("drainCodexSpool error: %v", err)
+			}
+			p, err := recordPath(ProviderCodex, c.id)
+			if err != nil {
+				t.Fatalf("recordPath error: %v", err)
+			}
+			rec, err := loadRecord(p)
+			if err != nil {
+				t.Fatalf("loadRecord error: %v", err)
+			}
+			if rec.CWD != c.cwd || rec.Status != StatusWaiting || rec.LastEventName != "notify:agent-turn-complete" {
+				t.Fatalf("unexpected record: %+v", rec)
+			}
+			if rec.LastUserText != c.lastUser || rec.LastAssistantText != c.lastAssistant {
+				t.Fatalf("unexpected messages: user %q assistant %q", rec.LastUserText, rec.LastAssistantText)
+			}
+		})
+	}
+}
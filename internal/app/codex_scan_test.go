@@ -60,6 +60,17 @@ func TestScanCodexHeaderAndTail(t *testing.T) {
 				},
 			},
 		},
+		{
+			"timestamp": "2024-01-02T03:04:30Z",
+			"type":      "event_msg",
+			"payload": map[string]any{
+				"type": "token_count",
+				"info": map[string]any{
+					"last_token_usage":     map[string]any{"input_tokens": 50000},
+					"model_context_window": 272000,
+				},
+			},
+		},
 		{
 			"timestamp": "2024-01-02T03:05:00Z",
 			"type":      "some_event",
@@ -119,6 +130,9 @@ func TestScanCodexHeaderAndTail(t *testing.T) {
 	if tail.LastAssistantText != "hi" {
 		t.Fatalf("unexpected last assistant text: %q", tail.LastAssistantText)
 	}
+	if tail.Usage.ContextTokens != 50000 || tail.Usage.ContextWindow != 272000 {
+		t.Fatalf("unexpected token usage: %+v", tail.Usage)
+	}
 
 	cfg := defaultConfig()
 	cfg.ActiveWindow = 2 * time.Hour
@@ -134,4 +148,10 @@ func TestScanCodexHeaderAndTail(t *testing.T) {
 	if recs[0].Status != StatusApproval {
 		t.Fatalf("expected approval status, got %q", recs[0].Status)
 	}
+	if recs[0].ContextWindowSize != 272000 || recs[0].CurrentInputTokens != 50000 {
+		t.Fatalf("unexpected context usage: %d/%d", recs[0].CurrentInputTokens, recs[0].ContextWindowSize)
+	}
+	if len(recs[0].Activity) != len(lines) {
+		t.Fatalf("expected %d recent events, got %d", len(lines), len(recs[0].Activity))
+	}
 }
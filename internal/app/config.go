@@ -3,13 +3,20 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
+	"github.com/vburojevic/aistat/internal/app/tui"
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
 )
 
 func defaultConfig() Config {
+	r, _ := newRedactor(RedactionConfigFile{})
 	return Config{
 		Redact:         true,
 		ActiveWindow:   defaultActiveWindow,
@@ -32,6 +39,13 @@ func defaultConfig() Config {
 		TailBytesClaude:    defaultTailBytesClaude,
 		HeaderScanLines:    defaultHeaderScanLines,
 		StatuslineMinWrite: defaultStatuslineMinWrite,
+
+		PolicyAudit:    true,
+		PermissionHold: defaultPermissionHold,
+
+		TUITheme: theme.Auto,
+
+		redactor: r,
 	}
 }
 
@@ -76,18 +90,57 @@ func loadConfig() Config {
 			cfg.AllScanWindow = d
 		}
 	}
+	if cf.StatuslineSegment != nil {
+		cfg.StatuslineSegment = *cf.StatuslineSegment
+	}
 	if cf.StatuslineMinWrite != "" {
 		if d, err := time.ParseDuration(cf.StatuslineMinWrite); err == nil && d > 0 {
 			cfg.StatuslineMinWrite = d
 		}
 	}
+	if cf.PermissionHold != "" {
+		// "0s" disables holding permission requests.
+		if d, err := time.ParseDuration(cf.PermissionHold); err == nil && d >= 0 {
+			cfg.PermissionHold = d
+		}
+	}
+	if cf.Policy != nil {
+		cfg.PolicyRules = normalizePolicyRules(cf.Policy.Rules)
+		if cf.Policy.Audit != nil {
+			cfg.PolicyAudit = *cf.Policy.Audit
+		}
+	}
+	if cf.TUI != nil {
+		if cf.TUI.Theme != "" {
+			cfg.TUITheme = cf.TUI.Theme
+		}
+		cfg.TUIKeys = cf.TUI.Keys
+		if cf.TUI.Accessible != nil {
+			cfg.TUIAccessible = *cf.TUI.Accessible
+		}
+	}
+	if cf.Redaction != nil {
+		cfg.Redaction = *cf.Redaction
+		cfg.redactor, _ = newRedactor(cfg.Redaction)
+	}
 	return cfg
 }
 
+// themesDir holds user theme files (<name>.json).
+func themesDir() (string, error) {
+	ad, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ad, "themes"), nil
+}
+
 func newConfigCmd() *cobra.Command {
 	var (
-		show bool
-		init bool
+		show   bool
+		init   bool
+		themes bool
+		keys   bool
 	)
 	cmd := &cobra.Command{
 		Use:   "config",
@@ -97,6 +150,12 @@ func newConfigCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if themes {
+				return listThemes(cmd.OutOrStdout(), loadConfig())
+			}
+			if keys {
+				return listKeyActions(cmd.OutOrStdout(), loadConfig())
+			}
 			if init {
 				if err := ensureAppDirs(); err != nil {
 					return err
@@ -113,6 +172,7 @@ func newConfigCmd() *cobra.Command {
 					MaxSessions:        ptrInt(defaultMaxSessions),
 					AllScanWindow:      defaultAllScanWindow.String(),
 					StatuslineMinWrite: defaultStatuslineMinWrite.String(),
+					PermissionHold:     defaultPermissionHold.String(),
 				}
 				b, _ := json.MarshalIndent(cf, "", "  ")
 				if err := os.WriteFile(p, b, 0o600); err != nil {
@@ -131,6 +191,22 @@ func newConfigCmd() *cobra.Command {
 				fmt.Printf("  max_sessions: %d\n", cfg.MaxSessions)
 				fmt.Printf("  all_scan_window: %s\n", cfg.AllScanWindow)
 				fmt.Printf("  statusline_min_write: %s\n", cfg.StatuslineMinWrite)
+				fmt.Printf("  statusline_segment: %v\n", cfg.StatuslineSegment)
+				fmt.Printf("  permission_hold: %s\n", cfg.PermissionHold)
+				fmt.Printf("  policy: %d rule(s), audit %v\n", len(cfg.PolicyRules), cfg.PolicyAudit)
+				for i, r := range cfg.PolicyRules {
+					if err := validatePolicyRule(r); err != nil {
+						fmt.Printf("    invalid %s, answers ask: %v\n", policyRuleLabel(i, r), err)
+					}
+				}
+				fmt.Printf("  tui.theme: %s\n", cfg.TUITheme)
+				fmt.Printf("  tui.keys: %d override(s)\n", len(cfg.TUIKeys))
+				fmt.Printf("  tui.accessible: %v\n", cfg.TUIAccessible)
+				rc := cfg.Redaction
+				fmt.Printf("  redaction: projects %s, ids %s, paths %s, messages %s\n",
+					redactMode(rc.Projects, redactKeep), redactMode(rc.IDs, redactShort), redactMode(rc.Paths, redactShort), redactMode(rc.Messages, redactScrub))
+				fmt.Printf("  redaction rules: %d alias(es), %d path rule(s), %d pattern(s), %d private host(s)\n",
+					len(rc.Aliases), len(rc.PathRules), len(rc.Patterns), len(rc.PrivateHosts))
 				return nil
 			}
 			_ = cmd.Help()
@@ -139,5 +215,55 @@ func newConfigCmd() *cobra.Command {
 	}
 	cmd.Flags().BoolVar(&show, "show", true, "Show config (default)")
 	cmd.Flags().BoolVar(&init, "init", false, "Write a default config file if missing")
+	cmd.Flags().BoolVar(&themes, "themes", false, "List available TUI themes")
+	cmd.Flags().BoolVar(&keys, "keys", false, "List TUI key actions and their bindings")
 	return cmd
 }
+
+// listThemes prints built-in and file themes, marking the configured one.
+func listThemes(w io.Writer, cfg Config) error {
+	dir, err := themesDir()
+	if err != nil {
+		return err
+	}
+	tw := prettytable.NewWriter()
+	tw.SetOutputMirror(w)
+	tw.SetStyle(prettytable.StyleLight)
+	tw.AppendHeader(prettytable.Row{"THEME", "SOURCE", ""})
+	tw.AppendRow(prettytable.Row{theme.Auto, "dark or light by terminal background", mark(cfg.TUITheme == theme.Auto)})
+	for _, t := range theme.List(dir) {
+		src := "built-in"
+		if t.Path != "" {
+			src = t.Path
+		}
+		tw.AppendRow(prettytable.Row{t.Name, src, mark(strings.EqualFold(cfg.TUITheme, t.Name))})
+	}
+	tw.Render()
+	fmt.Fprintf(w, "Theme files: %s/<name>.json (set \"tui\": {\"theme\": \"<name>\"} in config)\n", dir)
+	return nil
+}
+
+// listKeyActions prints every TUI action with its effective keys.
+func listKeyActions(w io.Writer, cfg Config) error {
+	km, err := tui.NewKeymap(cfg.TUIKeys)
+	if err != nil {
+		fmt.Fprintf(w, "warning: %v\n", err)
+	}
+	tw := prettytable.NewWriter()
+	tw.SetOutputMirror(w)
+	tw.SetStyle(prettytable.StyleLight)
+	tw.AppendHeader(prettytable.Row{"ACTION", "KEYS", "DESCRIPTION"})
+	for _, a := range tui.KeyActions {
+		tw.AppendRow(prettytable.Row{a.Name, km.Label(a.Name), a.Desc})
+	}
+	tw.Render()
+	fmt.Fprintln(w, `Rebind in config: "tui": {"keys": {"down": ["j", "ctrl+n"]}}`)
+	return nil
+}
+
+func mark(b bool) string {
+	if b {
+		return "*"
+	}
+	return ""
+}
@@ -1,6 +1,14 @@
 package app
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vburojevic/aistat/internal/app/tui"
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
+)
 
 func TestCfgFromFlags(t *testing.T) {
 	base := defaultConfig()
@@ -59,3 +67,63 @@ func TestCfgFromFlags(t *testing.T) {
 		t.Fatalf("expected error for invalid fields")
 	}
 }
+
+func TestLoadConfigTUIThemeAndKeys(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	writeFile(t, filepath.Join(root, "config.json"), `{"tui": {"theme": "mine", "keys": {"down": ["ctrl+n"], "quit": ["j"]}}}`)
+	writeFile(t, filepath.Join(root, "themes", "mine.json"), `{"base": "light", "text": "#000000"}`)
+
+	cfg := loadConfig()
+	if cfg.TUITheme != "mine" || len(cfg.TUIKeys["down"]) != 1 {
+		t.Fatalf("unexpected tui config: %q %v", cfg.TUITheme, cfg.TUIKeys)
+	}
+
+	dir, err := themesDir()
+	if err != nil {
+		t.Fatalf("themesDir error: %v", err)
+	}
+	th, err := theme.Load(cfg.TUITheme, dir)
+	if err != nil {
+		t.Fatalf("theme.Load error: %v", err)
+	}
+	if th.Text != "#000000" || th.Background != theme.Light.Background {
+		t.Fatalf("theme file should override text on the light base: %+v", th)
+	}
+	if _, err := theme.Load("nope", dir); err == nil {
+		t.Fatalf("expected error for unknown theme")
+	}
+
+	var out strings.Builder
+	if err := listThemes(&out, cfg); err != nil {
+		t.Fatalf("listThemes error: %v", err)
+	}
+	for _, want := range []string{"high-contrast", "light", "mine"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("theme list missing %q:\n%s", want, out.String())
+		}
+	}
+
+	km, err := tui.NewKeymap(cfg.TUIKeys)
+	if err != nil {
+		t.Fatalf("NewKeymap error: %v", err)
+	}
+	// "j" moved to quit, so down keeps only the override
+	if km.Action("ctrl+n") != "down" || km.Action("j") != "quit" || km.Action("down") != "" {
+		t.Fatalf("unexpected bindings: ctrl+n=%q j=%q down=%q", km.Action("ctrl+n"), km.Action("j"), km.Action("down"))
+	}
+	if _, err := tui.NewKeymap(map[string][]string{"fly": {"f"}}); err == nil {
+		t.Fatalf("expected error for unknown action")
+	}
+}
+
+func writeFile(t *testing.T, p, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		t.Fatalf("MkdirAll error: %v", err)
+	}
+	if err := os.WriteFile(p, []byte(body), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+}
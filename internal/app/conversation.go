@@ -0,0 +1,293 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// -------------------------
+// Conversation (detail pane)
+// -------------------------
+
+// maxConversationTurns bounds how many recent turns the detail pane keeps.
+const maxConversationTurns = 40
+
+// ConversationTurn is one message (or tool call) parsed from a transcript or
+// rollout.
+type ConversationTurn struct {
+	Role string // user | assistant | tool
+	Text string
+	At   time.Time
+}
+
+// TokenUsage is the latest token accounting seen in a Codex rollout.
+type TokenUsage struct {
+	InputTokens       int
+	CachedInputTokens int
+	OutputTokens      int
+	ContextTokens     int // input of the last request (what fills the window)
+	ContextWindow     int
+}
+
+// readConversation returns the most recent turns from a session's
+// transcript/rollout tail, oldest first. Codex rollouts also yield the last
+// token_count event.
+func readConversation(r SessionRecord, cfg Config) ([]ConversationTurn, TokenUsage, error) {
+	if r.Provider == ProviderCodex {
+		if r.RolloutPath == "" {
+			return nil, TokenUsage{}, nil
+		}
+		b, err := readTailBytes(r.RolloutPath, cfg.TailBytesCodex)
+		if err != nil {
+			return nil, TokenUsage{}, err
+		}
+		turns, usage := parseCodexConversation(b)
+		return lastTurns(turns), usage, nil
+	}
+	if r.TranscriptPath == "" {
+		return nil, TokenUsage{}, nil
+	}
+	b, err := readTailBytes(r.TranscriptPath, cfg.TailBytesClaude)
+	if err != nil {
+		return nil, TokenUsage{}, err
+	}
+	return lastTurns(parseClaudeConversation(b)), TokenUsage{}, nil
+}
+
+func lastTurns(turns []ConversationTurn) []ConversationTurn {
+	if len(turns) > maxConversationTurns {
+		return turns[len(turns)-maxConversationTurns:]
+	}
+	return turns
+}
+
+// parseClaudeConversation reads Claude transcript lines:
+// {"type":"user|assistant","timestamp":...,"message":{"content":string|[blocks]}}.
+// Tool results and meta lines are skipped; tool calls become "tool" turns.
+func parseClaudeConversation(b []byte) []ConversationTurn {
+	var out []ConversationTurn
+	for _, line := range splitLines(b) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var e struct {
+			Type      string `json:"type"`
+			Timestamp string `json:"timestamp"`
+			IsMeta    bool   `json:"isMeta"`
+			Message   struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(line), &e); err != nil || e.IsMeta {
+			continue
+		}
+		if e.Type != "user" && e.Type != "assistant" {
+			continue
+		}
+		at, _ := parseRFC3339ish(e.Timestamp)
+
+		var text string
+		if err := json.Unmarshal(e.Message.Content, &text); err == nil {
+			if text = strings.TrimSpace(text); text != "" && !strings.HasPrefix(text, "<command-") {
+				out = append(out, ConversationTurn{Role: e.Type, Text: text, At: at})
+			}
+			continue
+		}
+		var blocks []map[string]any
+		if err := json.Unmarshal(e.Message.Content, &blocks); err != nil {
+			continue
+		}
+		for _, blk := range blocks {
+			switch asString(blk["type"]) {
+			case "text":
+				if t := strings.TrimSpace(asString(blk["text"])); t != "" {
+					out = append(out, ConversationTurn{Role: e.Type, Text: t, At: at})
+				}
+			case "tool_use":
+				out = append(out, ConversationTurn{Role: "tool", Text: toolCallSummary(asString(blk["name"]), blk["input"]), At: at})
+			}
+		}
+	}
+	return out
+}
+
+// parseCodexConversation reads rollout lines for response_item messages and
+// function calls, plus the last token_count event.
+func parseCodexConversation(b []byte) ([]ConversationTurn, TokenUsage) {
+	var (
+		out   []ConversationTurn
+		usage TokenUsage
+	)
+	for _, line := range splitLines(b) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var e codexLogEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			continue
+		}
+		at, _ := parseRFC3339ish(e.Timestamp)
+
+		switch e.Type {
+		case "response_item":
+			switch asString(payload["type"]) {
+			case "message":
+				role := asString(payload["role"])
+				if role != "user" && role != "assistant" {
+					continue
+				}
+				content, _ := payload["content"].([]any)
+				text := strings.TrimSpace(extractCodexMessageText(role, content))
+				if text == "" || (role == "user" && looksLikeEnvironmentContext(text)) {
+					continue
+				}
+				out = append(out, ConversationTurn{Role: role, Text: text, At: at})
+			case "function_call", "custom_tool_call", "local_shell_call":
+				var input any
+				if args := asString(payload["arguments"]); args != "" {
+					_ = json.Unmarshal([]byte(args), &input)
+				}
+				out = append(out, ConversationTurn{Role: "tool", Text: toolCallSummary(asString(payload["name"]), input), At: at})
+			}
+		case "event_msg":
+			if u, ok := codexTokenUsage(payload); ok {
+				usage = u
+			}
+		}
+	}
+	return out, usage
+}
+
+// codexTokenUsage decodes a token_count event payload.
+func codexTokenUsage(payload map[string]any) (TokenUsage, bool) {
+	if asString(payload["type"]) != "token_count" {
+		return TokenUsage{}, false
+	}
+	info, _ := payload["info"].(map[string]any)
+	if info == nil {
+		return TokenUsage{}, false
+	}
+	total, _ := info["total_token_usage"].(map[string]any)
+	last, _ := info["last_token_usage"].(map[string]any)
+	return TokenUsage{
+		InputTokens:       asInt(total["input_tokens"]),
+		CachedInputTokens: asInt(total["cached_input_tokens"]),
+		OutputTokens:      asInt(total["output_tokens"]),
+		ContextTokens:     asInt(last["input_tokens"]),
+		ContextWindow:     asInt(info["model_context_window"]),
+	}, true
+}
+
+// toolCallSummary renders a tool call as "Name: <main argument>".
+func toolCallSummary(name string, input any) string {
+	if name == "" {
+		name = "tool"
+	}
+	m, _ := input.(map[string]any)
+	for _, k := range []string{"command", "cmd", "file_path", "path", "pattern", "url", "description"} {
+		v, ok := m[k]
+		if !ok {
+			continue
+		}
+		if parts, ok := v.([]any); ok {
+			strs := make([]string, 0, len(parts))
+			for _, p := range parts {
+				strs = append(strs, asString(p))
+			}
+			return name + ": " + strings.Join(strs, " ")
+		}
+		if s := asString(v); s != "" {
+			return name + ": " + s
+		}
+	}
+	return name
+}
+
+// -------------------------
+// Transcript follower (TUI split pane)
+// -------------------------
+
+// followMaxRead bounds one follower read so a burst of output can't stall
+// the UI; the rest is picked up on the next poll.
+const followMaxRead = 256 << 10
+
+// followTranscript returns the turns in complete lines appended to a
+// session's transcript/rollout since offset, and the offset to resume from.
+// A negative offset (or a file that shrank) starts again from the tail.
+func followTranscript(r SessionRecord, offset int64, cfg Config) ([]ConversationTurn, int64, error) {
+	path, tailBytes := r.TranscriptPath, cfg.TailBytesClaude
+	if r.Provider == ProviderCodex {
+		path, tailBytes = r.RolloutPath, cfg.TailBytesCodex
+	}
+	if path == "" {
+		return nil, offset, fmt.Errorf("no transcript for this session")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, offset, err
+	}
+
+	size := st.Size()
+	fromTail := offset < 0 || offset > size
+	start := offset
+	if fromTail {
+		start = 0
+		if size > int64(tailBytes) {
+			start = size - int64(tailBytes)
+		}
+	}
+	n := size - start
+	if n > followMaxRead {
+		n = followMaxRead
+	}
+	if n <= 0 {
+		return nil, start, nil
+	}
+	b := make([]byte, n)
+	if _, err := f.ReadAt(b, start); err != nil && err != io.EOF {
+		return nil, offset, err
+	}
+
+	// Skip a partial first line when starting mid-file, and leave a partial
+	// last line for the next read
+	skip := 0
+	if fromTail && start > 0 {
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			skip = i + 1
+		} else {
+			return nil, start, nil
+		}
+	}
+	end := bytes.LastIndexByte(b, '\n') + 1
+	if end <= skip {
+		return nil, start + int64(skip), nil
+	}
+	chunk := b[skip:end]
+
+	var turns []ConversationTurn
+	if r.Provider == ProviderCodex {
+		turns, _ = parseCodexConversation(chunk)
+	} else {
+		turns = parseClaudeConversation(chunk)
+	}
+	if fromTail {
+		turns = lastTurns(turns)
+	}
+	return turns, start + int64(end), nil
+}
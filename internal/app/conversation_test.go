@@ -0,0 +1,130 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseClaudeConversation(t *testing.T) {
+	lines := `{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"fix the tests"}}
+{"type":"user","isMeta":true,"message":{"role":"user","content":"meta"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"content":[{"type":"text","text":"Running them now."},{"type":"tool_use","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","timestamp":"2026-01-02T10:00:09Z","message":{"content":[{"type":"tool_result","content":"ok"}]}}
+{"type":"summary","summary":"x"}
+`
+	turns := parseClaudeConversation([]byte(lines))
+	if len(turns) != 3 {
+		t.Fatalf("expected 3 turns, got %d: %+v", len(turns), turns)
+	}
+	if turns[0].Role != "user" || turns[0].Text != "fix the tests" || !turns[0].At.Equal(time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected user turn: %+v", turns[0])
+	}
+	if turns[1].Role != "assistant" || turns[1].Text != "Running them now." {
+		t.Fatalf("unexpected assistant turn: %+v", turns[1])
+	}
+	if turns[2].Role != "tool" || turns[2].Text != "Bash: go test ./..." {
+		t.Fatalf("unexpected tool turn: %+v", turns[2])
+	}
+}
+
+func TestParseCodexConversation(t *testing.T) {
+	lines := `{"timestamp":"2026-01-02T10:00:00Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"<environment_context><cwd>/x</cwd></environment_context>"}]}}
+{"timestamp":"2026-01-02T10:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"add a flag"}]}}
+{"timestamp":"2026-01-02T10:00:03Z","type":"response_item","payload":{"type":"function_call","name":"shell","arguments":"{\"command\":[\"rg\",\"flag\"]}"}}
+{"timestamp":"2026-01-02T10:00:04Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1200,"cached_input_tokens":800,"output_tokens":300},"last_token_usage":{"input_tokens":900},"model_context_window":272000}}}
+{"timestamp":"2026-01-02T10:00:06Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Done."}]}}
+`
+	turns, usage := parseCodexConversation([]byte(lines))
+	if len(turns) != 3 {
+		t.Fatalf("expected 3 turns, got %d: %+v", len(turns), turns)
+	}
+	if turns[0].Text != "add a flag" || turns[1].Text != "shell: rg flag" || turns[2].Role != "assistant" {
+		t.Fatalf("unexpected turns: %+v", turns)
+	}
+	if usage.InputTokens != 1200 || usage.CachedInputTokens != 800 || usage.OutputTokens != 300 || usage.ContextTokens != 900 || usage.ContextWindow != 272000 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestLoadSessionDetailRedactsMessages(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AISTAT_HOME", dir)
+	p := filepath.Join(dir, "s.jsonl")
+	body := `{"type":"user","message":{"content":"deploy with api_key=abcd1234efgh"}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{"file_path":"main.go"}}]}}
+`
+	if err := os.WriteFile(p, []byte(body), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	at := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	rec := SessionRecord{
+		Provider:           ProviderClaude,
+		ID:                 "s",
+		TranscriptPath:     p,
+		CostUSD:            1.5,
+		ContextWindowSize:  200000,
+		CurrentInputTokens: 1000,
+		Transitions:        []StatusTransition{{At: at, Status: StatusRunning, Reason: "prompt submitted"}},
+	}
+	cfg := defaultConfig()
+	cfg.Redact = true
+
+	d, err := loadSessionDetail(rec, cfg)
+	if err != nil {
+		t.Fatalf("loadSessionDetail error: %v", err)
+	}
+	if len(d.Conversation) != 2 || d.Conversation[0].Text != "deploy with api_key=<secret>" || d.Conversation[1].Text != "Read" || !d.Redacted {
+		t.Fatalf("unexpected conversation: %+v", d.Conversation)
+	}
+	if d.Usage.ContextTokens != 1000 || d.Usage.ContextWindow != 200000 || d.Usage.Cost != 1.5 {
+		t.Fatalf("unexpected usage: %+v", d.Usage)
+	}
+	if len(d.Timeline) != 1 || d.Timeline[0].Reason != "prompt submitted" {
+		t.Fatalf("unexpected timeline: %+v", d.Timeline)
+	}
+}
+
+func TestFollowTranscriptReadsAppendedLines(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "s.jsonl")
+	first := `{"type":"user","message":{"content":"first"}}
+`
+	if err := os.WriteFile(p, []byte(first), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	rec := SessionRecord{Provider: ProviderClaude, ID: "s", TranscriptPath: p}
+	cfg := Config{TailBytesClaude: 1 << 20}
+
+	turns, off, err := followTranscript(rec, -1, cfg)
+	if err != nil || len(turns) != 1 || turns[0].Text != "first" || off != int64(len(first)) {
+		t.Fatalf("unexpected tail read: %+v off=%d err=%v", turns, off, err)
+	}
+
+	// A partial line is left for the next read
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	defer f.Close()
+	_, _ = f.WriteString(`{"type":"assistant","message":{"content":"second"}}` + "\n" + `{"type":"user","message":`)
+	turns, off, err = followTranscript(rec, off, cfg)
+	if err != nil || len(turns) != 1 || turns[0].Text != "second" {
+		t.Fatalf("unexpected appended read: %+v err=%v", turns, err)
+	}
+	_, _ = f.WriteString(`{"content":"third"}}` + "\n")
+	turns, _, err = followTranscript(rec, off, cfg)
+	if err != nil || len(turns) != 1 || turns[0].Text != "third" {
+		t.Fatalf("unexpected completed read: %+v err=%v", turns, err)
+	}
+
+	// A file that shrank is read again from its tail
+	if err := os.WriteFile(p, []byte(first), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	turns, _, err = followTranscript(rec, 1<<20, cfg)
+	if err != nil || len(turns) != 1 || turns[0].Text != "first" {
+		t.Fatalf("unexpected read after truncation: %+v err=%v", turns, err)
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -19,6 +20,8 @@ import (
 func newDoctorCmd() *cobra.Command {
 	var (
 		fix         bool
+		selftest    bool
+		jsonOut     bool
 		force       bool
 		dryRun      bool
 		skipClaude  bool
@@ -28,8 +31,11 @@ func newDoctorCmd() *cobra.Command {
 	)
 	cmd := &cobra.Command{
 		Use:   "doctor",
-		Short: "Check setup + show where we read sessions from",
+		Short: "Check setup: each check with a severity and a fix (table or --json)",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if fix && jsonOut {
+				return errors.New("--json cannot be combined with --fix; run `aistat doctor --fix` first")
+			}
 			if fix {
 				if term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) && !force && !dryRun {
 					if !confirm("Run doctor --fix? This will edit Claude/Codex configs. [y/N]: ") {
@@ -52,21 +58,28 @@ func newDoctorCmd() *cobra.Command {
 					callCmd = strings.TrimSpace(cmdOverride)
 				}
 
-				home, _ := os.UserHomeDir()
-				claudeSettings := filepath.Join(home, ".claude", "settings.json")
-				codexCfg := filepath.Join(home, ".codex", "config.toml")
+				claudeTargets, codexTargets, err := recordedTargets()
+				if err != nil {
+					return err
+				}
+				if skipClaude {
+					claudeTargets = nil
+				}
+				if skipCodex {
+					codexTargets = nil
+				}
 
-				claudeSnap := ""
-				codexSnap := ""
+				// Snapshot every config so a failed fix can be rolled back
+				snaps := map[string]string{}
 				if !dryRun {
-					if !skipClaude {
-						if snap, err := snapshotFile(claudeSettings); err == nil {
-							claudeSnap = snap
+					for _, t := range claudeTargets {
+						if snap, err := snapshotFile(t.Path); err == nil {
+							snaps[t.Path] = snap
 						}
 					}
-					if !skipCodex {
-						if snap, err := snapshotFile(codexCfg); err == nil {
-							codexSnap = snap
+					for _, t := range codexTargets {
+						if snap, err := snapshotFile(t.Path); err == nil {
+							snaps[t.Path] = snap
 						}
 					}
 				}
@@ -75,98 +88,59 @@ func newDoctorCmd() *cobra.Command {
 					fmt.Println("Nothing to install (both providers skipped).")
 				} else {
 					var errs []string
-					if !skipClaude {
-						if err := installClaude(callCmd, force, dryRun); err != nil {
+					for _, t := range claudeTargets {
+						if err := installClaude(t, callCmd, force, dryRun); err != nil {
 							errs = append(errs, "Claude: "+err.Error())
 						}
 					}
-					if !skipCodex {
-						if err := installCodex(callCmd, force, dryRun); err != nil {
+					for _, t := range codexTargets {
+						if err := installCodex(t, callCmd, dryRun); err != nil {
 							errs = append(errs, "Codex: "+err.Error())
 						}
 					}
 					if len(errs) > 0 {
 						if !dryRun {
-							_ = restoreSnapshot(claudeSnap, claudeSettings)
-							_ = restoreSnapshot(codexSnap, codexCfg)
+							for path, snap := range snaps {
+								_ = restoreSnapshot(snap, path)
+								_ = cleanupSnapshot(snap)
+							}
 						}
 						return errors.New(strings.Join(errs, "\n"))
 					}
 					if dryRun {
 						fmt.Println("Dry run complete.")
 					} else {
-						_ = cleanupSnapshot(claudeSnap)
-						_ = cleanupSnapshot(codexSnap)
+						for _, snap := range snaps {
+							_ = cleanupSnapshot(snap)
+						}
 					}
 				}
 			}
 
-			cfg := loadConfig()
-			ad, _ := appDir()
-			sd, _ := sessionsDir()
-			cp, _ := configFilePath()
-			sp, _ := spoolDir()
-
-			fmt.Printf("aistat\n")
-			fmt.Printf("  app dir: %s\n", ad)
-			fmt.Printf("  sessions dir: %s\n", sd)
-			if sp != "" {
-				if sz, n := spoolSummary(); n > 0 {
-					fmt.Printf("  spool dir: %s (%d files, %s)\n", sp, n, humanBytes(sz))
-				} else {
-					fmt.Printf("  spool dir: %s\n", sp)
-				}
-			}
-			fmt.Printf("  config: %s\n", cp)
-			fmt.Printf("  redact: %v\n", cfg.Redact)
-			fmt.Printf("  active window: %s\n", cfg.ActiveWindow)
-			fmt.Printf("  refresh: %s\n", cfg.RefreshEvery)
-			fmt.Println()
-
-			home, _ := os.UserHomeDir()
-			claudeSettings := filepath.Join(home, ".claude", "settings.json")
-			fmt.Printf("Claude Code\n")
-			fmt.Printf("  settings: %s (%s)\n", claudeSettings, existsStr(claudeSettings))
-			claudeProjects := filepath.Join(home, ".claude", "projects")
-			fmt.Printf("  projects: %s (%s)\n", claudeProjects, existsStr(claudeProjects))
-			fmt.Println()
-
-			codexCfg := codexConfigPath()
-			if codexCfg == "" {
-				codexCfg = filepath.Join(home, ".codex", "config.toml")
+			checks := runDoctorChecks()
+			if selftest {
+				checks = append(checks, runSelftest()...)
 			}
-			fmt.Printf("Codex\n")
-			fmt.Printf("  config: %s (%s)\n", codexCfg, existsStr(codexCfg))
-			codexSessions := codexSessionsPath()
-			if codexSessions == "" {
-				codexSessions = filepath.Join(home, ".codex", "sessions")
-			}
-			fmt.Printf("  sessions: %s (%s)\n", codexSessions, existsStr(codexSessions))
-			if b, err := os.ReadFile(codexCfg); err == nil {
-				exe, _ := os.Executable()
-				exe, _ = filepath.Abs(exe)
-				ad, _ := appDir()
-				wrapper := filepath.Join(ad, "bin", "aistat-codex-notify")
-				state := classifyCodexNotify(string(b), exe, wrapper)
-				switch state {
-				case codexNotifyWrapper:
-					fmt.Printf("  notify: safe wrapper\n")
-				case codexNotifyUnsafe:
-					fmt.Printf("  notify: unsafe (direct aistat). Run `aistat install --force` or `aistat doctor --fix --force`.\n")
-				case codexNotifyOther:
-					fmt.Printf("  notify: custom (unchanged)\n")
-				default:
-					fmt.Printf("  notify: not set\n")
+			report := newDoctorReport(checks)
+			if jsonOut {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
 				}
+			} else {
+				renderDoctorReport(cmd.OutOrStdout(), report)
+			}
+			if !report.OK {
+				return &exitError{code: exitDoctorFailed, err: fmt.Errorf("doctor: %d error-level check(s) failed", report.Errors)}
 			}
-			fmt.Println()
-
-			fmt.Println("Tip: run `aistat install` to wire up hooks/statusline/notify.")
 			return nil
 		},
 	}
 	cmd.Flags().BoolVar(&fix, "fix", false, "Attempt to auto-fix setup (runs install)")
-	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing statusLine/notify if present")
+	cmd.Flags().BoolVar(&selftest, "selftest", false, "Send synthetic events through the installed wrappers and check they reach a session record")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output the checks as JSON")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing Claude statusLine instead of chaining it")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show changes without writing files")
 	cmd.Flags().BoolVar(&skipClaude, "skip-claude", false, "Skip Claude Code setup")
 	cmd.Flags().BoolVar(&skipCodex, "skip-codex", false, "Skip Codex setup")
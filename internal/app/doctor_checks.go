@@ -0,0 +1,544 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/vburojevic/aistat/internal/app/tui"
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
+)
+
+// -------------------------
+// Doctor checks
+// -------------------------
+
+// Check severities, mildest first
+const (
+	severityOK    = "ok"
+	severityInfo  = "info"
+	severityWarn  = "warn"
+	severityError = "error"
+)
+
+// exitDoctorFailed is returned by `aistat doctor` when an error-level check
+// fails.
+const exitDoctorFailed = 4
+
+const (
+	spoolBacklogMaxFiles = 1000
+	spoolBacklogMaxBytes = 50 << 20
+	spoolBacklogMaxAge   = 24 * time.Hour // the spool drains whenever aistat lists sessions
+	staleLockAge         = 10 * time.Minute
+)
+
+// doctorCheck is one result of `aistat doctor`. IDs are stable; Target tells
+// apart the same check run against several configs.
+type doctorCheck struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"` // ok|info|warn|error
+	Target   string `json:"target,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+type doctorReport struct {
+	OK       bool          `json:"ok"` // no error-level checks
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Checks   []doctorCheck `json:"checks"`
+}
+
+func newDoctorReport(checks []doctorCheck) doctorReport {
+	r := doctorReport{Checks: checks}
+	for _, c := range checks {
+		switch c.Severity {
+		case severityError:
+			r.Errors++
+		case severityWarn:
+			r.Warnings++
+		}
+	}
+	r.OK = r.Errors == 0
+	return r
+}
+
+func renderDoctorReport(w io.Writer, r doctorReport) {
+	tw := prettytable.NewWriter()
+	tw.SetOutputMirror(w)
+	tw.SetStyle(prettytable.StyleLight)
+	tw.AppendHeader(prettytable.Row{"SEVERITY", "CHECK", "TARGET", "DETAIL", "FIX"})
+	for _, c := range r.Checks {
+		tw.AppendRow(prettytable.Row{c.Severity, c.ID, c.Target, c.Detail, c.Fix})
+	}
+	tw.Render()
+	fmt.Fprintf(w, "%d error(s), %d warning(s).\n", r.Errors, r.Warnings)
+}
+
+// notWired is the severity and fix of a config aistat is not wired into:
+// an error for a recorded install target, a warning for a default one.
+func notWired(recorded bool) (string, string) {
+	if recorded {
+		return severityError, "run `aistat doctor --fix`"
+	}
+	return severityWarn, "run `aistat install`"
+}
+
+// runDoctorChecks inspects aistat's own state and every install target.
+func runDoctorChecks() []doctorCheck {
+	var checks []doctorCheck
+	checks = append(checks, appDirChecks()...)
+	checks = append(checks, configChecks()...)
+
+	claudeTargets, codexTargets, err := recordedTargets()
+	if err != nil {
+		checks = append(checks, doctorCheck{ID: "install.targets", Severity: severityError, Detail: err.Error(),
+			Fix: "remove install_targets.json and run `aistat install`"})
+	}
+	recorded := map[string]bool{}
+	if targets, err := loadInstallTargets(); err == nil {
+		for _, t := range targets {
+			recorded[t.Path] = true
+		}
+	}
+
+	claudeWired := false
+	claudeProjects := filepath.Join(claudeConfigDir(), "projects")
+	checks = append(checks, dirExistsCheck("claude.projects", claudeProjects, "Claude Code has not saved a session yet"))
+	for _, t := range claudeTargets {
+		cs, wired := claudeChecks(t, recorded[t.Path])
+		checks = append(checks, cs...)
+		claudeWired = claudeWired || wired
+	}
+
+	codexWired := false
+	checks = append(checks, dirExistsCheck("codex.sessions", codexSessionsPath(), "Codex has not saved a session yet"))
+	for _, t := range codexTargets {
+		cs, wired := codexChecks(t, recorded[t.Path])
+		checks = append(checks, cs...)
+		codexWired = codexWired || wired
+	}
+
+	stats, err := loadIngestStats()
+	if err != nil {
+		checks = append(checks, doctorCheck{ID: "ingest.stats", Severity: severityWarn, Detail: err.Error(), Fix: "remove ingest_stats.json"})
+	}
+	checks = append(checks, spoolCheck(), quarantineCheck(), lockCheck())
+	for _, p := range []struct {
+		provider Provider
+		wired    bool
+	}{{ProviderClaude, claudeWired}, {ProviderCodex, codexWired}} {
+		var s providerIngestStats
+		if stats[p.provider] != nil {
+			s = *stats[p.provider]
+		}
+		checks = append(checks, ingestCheck(p.provider, s), lastEventCheck(p.provider, s, p.wired))
+	}
+	return checks
+}
+
+// appDirChecks checks that aistat's directories are private and writable by
+// the hooks.
+func appDirChecks() []doctorCheck {
+	ad, err := appDir()
+	if err != nil {
+		return []doctorCheck{{ID: "app.dir", Severity: severityError, Detail: err.Error(), Fix: "set AISTAT_HOME"}}
+	}
+	dirs := []struct {
+		id, path string
+		optional bool // created with the first event
+	}{
+		{"app.dir", ad, false},
+		{"app.sessions_dir", filepath.Join(ad, "sessions"), true},
+		{"app.spool_dir", filepath.Join(ad, "spool"), true},
+	}
+	var out []doctorCheck
+	for _, d := range dirs {
+		c := doctorCheck{ID: d.id, Severity: severityOK, Target: d.path}
+		st, err := os.Stat(d.path)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && d.optional:
+			c.Severity, c.Detail = severityInfo, "not created yet"
+		case errors.Is(err, os.ErrNotExist):
+			c.Severity, c.Detail, c.Fix = severityWarn, "missing", "run `aistat install`"
+		case err != nil:
+			c.Severity, c.Detail = severityError, err.Error()
+		case !st.IsDir():
+			c.Severity, c.Detail, c.Fix = severityError, "not a directory", "move it aside"
+		default:
+			if f, err := os.CreateTemp(d.path, ".doctor_*"); err != nil {
+				c.Severity, c.Detail, c.Fix = severityError, "not writable: "+err.Error(), "chown it to your user and chmod 700"
+			} else {
+				_ = f.Close()
+				_ = os.Remove(f.Name())
+				if perm := st.Mode().Perm(); perm&0o077 != 0 {
+					c.Severity, c.Detail, c.Fix = severityWarn, fmt.Sprintf("accessible to other users (%04o)", perm), "chmod 700 "+shellQuote(d.path)
+				} else {
+					c.Detail = fmt.Sprintf("writable (%04o)", perm)
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// configChecks validates config.json. loadConfig silently falls back to the
+// defaults for anything it cannot use.
+func configChecks() []doctorCheck {
+	p, err := configFilePath()
+	if err != nil {
+		return []doctorCheck{{ID: "config.file", Severity: severityError, Detail: err.Error()}}
+	}
+	c := doctorCheck{ID: "config.file", Severity: severityOK, Target: p}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		c.Detail = "not present; using defaults"
+		return []doctorCheck{c}
+	}
+	if err != nil {
+		c.Severity, c.Detail = severityError, err.Error()
+		return []doctorCheck{c}
+	}
+	var cf ConfigFile
+	if err := json.Unmarshal(b, &cf); err != nil {
+		c.Severity, c.Detail, c.Fix = severityError, "does not parse, every setting is at its default: "+err.Error(), "fix the JSON"
+		return []doctorCheck{c}
+	}
+
+	var bad []string
+	durations := []struct {
+		key, value string
+		allowZero  bool
+	}{
+		{"active_window", cf.ActiveWindow, false},
+		{"running_window", cf.RunningWindow, false},
+		{"refresh_every", cf.RefreshEvery, false},
+		{"all_scan_window", cf.AllScanWindow, false},
+		{"statusline_min_write", cf.StatuslineMinWrite, false},
+		{"permission_hold", cf.PermissionHold, true},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil || v < 0 || v == 0 && !d.allowZero {
+			bad = append(bad, fmt.Sprintf("%s %q", d.key, d.value))
+		}
+	}
+	if cf.MaxSessions != nil && *cf.MaxSessions <= 0 {
+		bad = append(bad, fmt.Sprintf("max_sessions %d", *cf.MaxSessions))
+	}
+	if len(bad) > 0 {
+		c.Severity, c.Detail, c.Fix = severityWarn, "ignored, using the default: "+strings.Join(bad, ", "), "use Go durations such as 30m or 2s"
+	} else {
+		c.Detail = "valid"
+	}
+	out := []doctorCheck{c}
+
+	if cf.Policy != nil && len(cf.Policy.Rules) > 0 {
+		pc := doctorCheck{ID: "config.policy", Severity: severityOK, Target: p, Detail: fmt.Sprintf("%d rule(s)", len(cf.Policy.Rules))}
+		var invalid []string
+		for i, r := range cf.Policy.Rules {
+			if err := validatePolicyRule(r); err != nil {
+				invalid = append(invalid, policyRuleLabel(i, r)+": "+err.Error())
+			}
+		}
+		if len(invalid) > 0 {
+			pc.Severity, pc.Detail, pc.Fix = severityError, "answered with ask: "+strings.Join(invalid, "; "), "see `aistat policy list`"
+		}
+		out = append(out, pc)
+	}
+
+	if cf.Redaction != nil {
+		rc := doctorCheck{ID: "config.redaction", Severity: severityOK, Target: p, Detail: "valid"}
+		if _, problems := newRedactor(*cf.Redaction); len(problems) > 0 {
+			rc.Severity, rc.Detail, rc.Fix = severityWarn, "ignored: "+strings.Join(problems, "; "), "see `aistat help` for the redaction keys"
+		}
+		out = append(out, rc)
+	}
+
+	if cf.TUI != nil {
+		tc := doctorCheck{ID: "config.tui", Severity: severityOK, Target: p, Detail: "valid"}
+		var problems []string
+		if name := strings.TrimSpace(cf.TUI.Theme); name != "" && !strings.EqualFold(name, theme.Auto) {
+			dir, _ := themesDir()
+			if _, err := theme.Load(name, dir); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+		if _, err := tui.NewKeymap(cf.TUI.Keys); err != nil {
+			problems = append(problems, err.Error())
+		}
+		if len(problems) > 0 {
+			tc.Severity, tc.Detail, tc.Fix = severityWarn, strings.Join(problems, "; "), "see `aistat config --themes` and `aistat config --keys`"
+		}
+		out = append(out, tc)
+	}
+	return out
+}
+
+func dirExistsCheck(id, path, missing string) doctorCheck {
+	c := doctorCheck{ID: id, Severity: severityOK, Target: path, Detail: "found"}
+	if _, err := os.Stat(path); err != nil {
+		c.Severity, c.Detail = severityInfo, missing
+	}
+	return c
+}
+
+// wrapperProblem says what is wrong with an installed wrapper script, or "".
+func wrapperProblem(path string) string {
+	st, err := os.Stat(path)
+	switch {
+	case err != nil:
+		return "missing"
+	case st.Mode()&0o111 == 0:
+		return "not executable"
+	}
+	return ""
+}
+
+// claudeChecks checks the hooks (per event), statusLine and wrappers of a
+// Claude settings file. wired reports whether any aistat hook is set.
+func claudeChecks(t claudeTarget, recorded bool) (checks []doctorCheck, wired bool) {
+	sev, fix := notWired(recorded)
+	target := t.Path
+	if t.Scope != claudeScopeUser {
+		target += " (" + t.Scope + ")"
+	}
+	b, err := os.ReadFile(t.Path)
+	if err != nil {
+		return []doctorCheck{{ID: "claude.settings", Severity: sev, Target: target, Detail: "missing", Fix: fix}}, false
+	}
+	var settings map[string]any
+	if err := json.Unmarshal(b, &settings); err != nil {
+		return []doctorCheck{{ID: "claude.settings", Severity: severityError, Target: target,
+			Detail: "does not parse: " + err.Error(), Fix: "fix the JSON; Claude Code ignores the file"}}, false
+	}
+
+	events := aistatHookEvents(settings)
+	rules := len(loadConfig().PolicyRules) > 0
+	for _, e := range claudeHookEvents {
+		c := doctorCheck{ID: "claude.hook." + e, Severity: severityOK, Target: target, Detail: "wired"}
+		switch {
+		case !slices.Contains(events, e):
+			c.Severity, c.Detail, c.Fix = sev, "not wired", fix
+		case e == "PreToolUse" && rules && !strings.HasSuffix(aistatHookCommand(settings, e), " --sync"):
+			c.Severity, c.Detail, c.Fix = severityWarn, "runs detached, policy rules are not enforced", "run `aistat install`"
+		case e == "PreToolUse" && rules:
+			c.Detail = "wired, enforcing policy rules"
+		}
+		checks = append(checks, c)
+	}
+
+	sl, _ := settings["statusLine"].(map[string]any)
+	command := strings.TrimSpace(asString(sl["command"]))
+	chain, _ := loadClaudeStatuslineChain(t.Path)
+	c := doctorCheck{ID: "claude.statusline", Severity: severityOK, Target: target, Detail: "aistat"}
+	switch {
+	case command == "":
+		c.Severity, c.Detail, c.Fix = sev, "not set", fix
+	case !isAistatStatusline(command):
+		c.Severity, c.Detail, c.Fix = severityWarn, "custom, aistat gets no metrics", "run `aistat install` to chain it"
+	case chain.Command != "":
+		c.Detail = "aistat, chaining " + chain.Command
+	}
+	checks = append(checks, c)
+
+	ad, _ := appDir()
+	for _, name := range []string{"aistat-claude-hook", "aistat-claude-statusline"} {
+		if !bytes.Contains(b, []byte(name)) {
+			continue
+		}
+		path := filepath.Join(ad, "bin", name)
+		c := doctorCheck{ID: "claude.wrapper", Severity: severityOK, Target: path, Detail: "executable"}
+		if p := wrapperProblem(path); p != "" {
+			c.Severity, c.Detail, c.Fix = severityError, p+"; "+filepath.Base(t.Path)+" calls it", "run `aistat doctor --fix`"
+		}
+		checks = append(checks, c)
+	}
+	return checks, len(events) > 0
+}
+
+// codexChecks checks notify (top level and profiles) of a Codex config.toml.
+// wired reports whether any of them calls aistat.
+func codexChecks(t codexTarget, recorded bool) (checks []doctorCheck, wired bool) {
+	sev, fix := notWired(recorded)
+	b, err := os.ReadFile(t.Path)
+	if err != nil {
+		return []doctorCheck{{ID: "codex.config", Severity: sev, Target: t.Path, Detail: "missing", Fix: fix}}, false
+	}
+	settings, err := parseCodexNotify(string(b))
+	if err != nil {
+		return []doctorCheck{{ID: "codex.config", Severity: severityError, Target: t.Path,
+			Detail: "does not parse: " + err.Error(), Fix: "fix the TOML; Codex will not start"}}, false
+	}
+	exe, _ := os.Executable()
+	exe, _ = filepath.Abs(exe)
+	ad, _ := appDir()
+	chain, _ := loadCodexNotifyChain(t.Path)
+	profiles := settings.targets()
+	if len(t.Profiles) > 0 {
+		profiles = t.Profiles
+	}
+	for _, profile := range profiles {
+		target := t.Path
+		if profile != "" {
+			target += " (profile " + profile + ")"
+		}
+		wrapper := filepath.Join(ad, "bin", codexNotifyWrapperName(t.Path, profile))
+		c := doctorCheck{ID: "codex.notify", Severity: severityOK, Target: target}
+		switch classifyNotifyArgv(settings.notify(profile), exe, wrapper) {
+		case codexNotifyWrapper:
+			wired = true
+			c.Detail = "safe wrapper"
+			if prev := chain[profile]; len(prev) > 0 {
+				c.Detail += ", chaining " + strings.Join(prev, " ")
+			}
+			if p := wrapperProblem(wrapper); p != "" {
+				c.Severity, c.Detail, c.Fix = severityError, "wrapper "+p, "run `aistat doctor --fix`"
+			}
+		case codexNotifyUnsafe:
+			wired = true
+			c.Severity, c.Detail, c.Fix = severityError, "calls aistat directly, which can block Codex", "run `aistat doctor --fix`"
+		case codexNotifyOther:
+			c.Severity, c.Detail, c.Fix = severityWarn, "custom, aistat gets no events", "run `aistat install` to chain it"
+		default:
+			c.Severity, c.Detail, c.Fix = sev, "not set", fix
+		}
+		checks = append(checks, c)
+	}
+	return checks, wired
+}
+
+// spoolCheck reports events waiting in the spool. It drains whenever aistat
+// lists sessions, so a large or old backlog means nothing is draining it or
+// files keep failing to apply.
+func spoolCheck() doctorCheck {
+	sd, _ := spoolDir()
+	size, n, oldest := spoolSummary()
+	c := doctorCheck{ID: "spool.backlog", Severity: severityOK, Target: sd, Detail: "empty"}
+	if n == 0 {
+		return c
+	}
+	age := time.Since(oldest)
+	c.Detail = fmt.Sprintf("%d file(s), %s, oldest %s ago", n, humanBytes(size), fmtAgo(age))
+	switch {
+	case n >= spoolBacklogMaxFiles || size >= spoolBacklogMaxBytes:
+		c.Severity, c.Fix = severityWarn, "run `aistat` to drain it, or `aistat clean --sessions=false` to drop it"
+	case age >= spoolBacklogMaxAge:
+		c.Severity, c.Fix = severityWarn, "run `aistat`; files that still stay failed to apply (`aistat clean --sessions=false` drops them)"
+	}
+	return c
+}
+
+// lockCheck looks for record lock files left behind by records that are
+// gone. Locks are flock()ed, so a crashed writer never holds one.
+func lockCheck() doctorCheck {
+	sd, _ := sessionsDir()
+	stale := staleLockFiles()
+	c := doctorCheck{ID: "sessions.locks", Severity: severityOK, Target: sd, Detail: "no stale lock files"}
+	if len(stale) > 0 {
+		c.Severity, c.Detail, c.Fix = severityWarn, fmt.Sprintf("%d lock file(s) without a session record", len(stale)), "run `aistat clean --spool=false`"
+	}
+	return c
+}
+
+// staleLockFiles lists session lock files whose record no longer exists,
+// skipping recent ones a writer may be about to use.
+func staleLockFiles() []string {
+	sd, err := sessionsDir()
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(sd)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".lock") {
+			continue
+		}
+		p := filepath.Join(sd, e.Name())
+		if _, err := os.Stat(strings.TrimSuffix(p, ".lock")); err == nil {
+			continue
+		}
+		if info, err := e.Info(); err != nil || time.Since(info.ModTime()) < staleLockAge {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// quarantineCheck reports spool files that failed to apply too often.
+func quarantineCheck() doctorCheck {
+	qd, _ := quarantineDir()
+	n := len(quarantinedFiles(ProviderClaude)) + len(quarantinedFiles(ProviderCodex))
+	c := doctorCheck{ID: "spool.quarantine", Severity: severityOK, Target: qd, Detail: "empty"}
+	if n > 0 {
+		c.Severity, c.Detail, c.Fix = severityWarn, fmt.Sprintf("%d spool file(s) that never applied", n), "look at them, then `aistat clean --sessions=false`"
+	}
+	return c
+}
+
+// ingestCheck reports a provider's ingest counters.
+func ingestCheck(provider Provider, s providerIngestStats) doctorCheck {
+	sp, _ := ingestStatsPath()
+	c := doctorCheck{ID: "ingest." + string(provider), Severity: severityOK, Target: sp, Detail: s.summary()}
+	if p := s.problem(); p != "" {
+		c.Severity = severityWarn
+		c.Detail = p + "; " + c.Detail
+		switch {
+		case len(s.Failing) > 0:
+			c.Detail += "; last drain error: " + s.LastDrainError
+			c.Fix = "check the session records dir is writable; files are quarantined after " + fmt.Sprint(spoolMaxAttempts) + " failures"
+		case s.LastRejectReason == rejectSpoolWrite:
+			c.Fix = "check the spool dir is writable"
+		default:
+			c.Fix = "check the hook command gets the event JSON; run `aistat doctor --selftest`"
+		}
+	}
+	return c
+}
+
+// lastEventCheck reports when a hook, statusline or notify event of a
+// provider last arrived, drained or still spooled.
+func lastEventCheck(provider Provider, s providerIngestStats, wired bool) doctorCheck {
+	last := s.LastEvent
+	if records, err := loadAllRecords(); err == nil {
+		for _, r := range records {
+			if r.Provider == provider {
+				last = maxTime(last, r.LastEvent)
+			}
+		}
+	}
+	if sd, err := spoolDir(); err == nil {
+		matches, _ := filepath.Glob(filepath.Join(sd, string(provider), "*", "*.json"))
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil {
+				last = maxTime(last, info.ModTime())
+			}
+		}
+	}
+	c := doctorCheck{ID: "events." + string(provider), Severity: severityOK}
+	switch {
+	case !last.IsZero():
+		c.Detail = fmt.Sprintf("last event %s ago (%s)", fmtAgo(time.Since(last)), last.Local().Format(time.RFC3339))
+	case wired:
+		c.Severity, c.Detail, c.Fix = severityWarn, "no event received yet", "start a session, or run `aistat doctor --selftest`"
+	default:
+		c.Severity, c.Detail = severityInfo, "no event received; not wired"
+	}
+	return c
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func findCheck(checks []doctorCheck, id string) (doctorCheck, bool) {
+	for _, c := range checks {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return doctorCheck{}, false
+}
+
+func TestDoctorChecks(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+	t.Setenv("CODEX_HOME", filepath.Join(root, "codex"))
+
+	// Nothing installed: warnings only
+	report := newDoctorReport(runDoctorChecks())
+	if !report.OK {
+		t.Fatalf("fresh setup should have no errors: %+v", report.Checks)
+	}
+	if c, _ := findCheck(report.Checks, "claude.settings"); c.Severity != severityWarn {
+		t.Fatalf("claude.settings = %+v, want warn", c)
+	}
+
+	settingsPath := userClaudeSettingsPath()
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{}`), 0o600); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+	if err := installClaude(claudeTarget{Scope: claudeScopeUser, Path: settingsPath}, "/bin/true", false, false); err != nil {
+		t.Fatalf("installClaude error: %v", err)
+	}
+	checks := runDoctorChecks()
+	for _, e := range claudeHookEvents {
+		if c, ok := findCheck(checks, "claude.hook."+e); !ok || c.Severity != severityOK {
+			t.Fatalf("claude.hook.%s = %+v", e, c)
+		}
+	}
+	if c, _ := findCheck(checks, "events.claude"); c.Severity != severityWarn {
+		t.Fatalf("events.claude = %+v, want warn (wired, nothing received)", c)
+	}
+
+	// A recorded target losing its hooks is an error
+	if err := os.WriteFile(settingsPath, []byte(`{}`), 0o600); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+	ad, _ := appDir()
+	if err := os.WriteFile(filepath.Join(ad, "config.json"), []byte(`{"active_window":"soon","policy":{"rules":[{"decision":"maybe","tool":"Bash"}]}}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	report = newDoctorReport(runDoctorChecks())
+	if report.OK {
+		t.Fatalf("expected errors: %+v", report.Checks)
+	}
+	for id, want := range map[string]string{
+		"claude.hook.Stop": severityError,
+		"config.file":      severityWarn,
+		"config.policy":    severityError,
+	} {
+		if c, _ := findCheck(report.Checks, id); c.Severity != want || c.Fix == "" {
+			t.Fatalf("%s = %+v, want %s with a fix", id, c, want)
+		}
+	}
+
+	// A lock file without its record is stale once it is old
+	if err := ensureAppDirs(); err != nil {
+		t.Fatalf("ensureAppDirs: %v", err)
+	}
+	lock := filepath.Join(ad, "sessions", "claude_gone.json.lock")
+	if err := os.WriteFile(lock, nil, 0o600); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	if c, _ := findCheck(runDoctorChecks(), "sessions.locks"); c.Severity != severityOK {
+		t.Fatalf("fresh lock reported as stale: %+v", c)
+	}
+	old := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(lock, old, old)
+	if c, _ := findCheck(runDoctorChecks(), "sessions.locks"); c.Severity != severityWarn {
+		t.Fatalf("sessions.locks = %+v, want warn", c)
+	}
+	if _, err := cleanInvalidSessions(false); err != nil {
+		t.Fatalf("clean error: %v", err)
+	}
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Fatalf("clean left the stale lock")
+	}
+}
@@ -0,0 +1,245 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -------------------------
+// Doctor self-test
+// -------------------------
+
+// selftestTimeout bounds each wrapper run and each wait for its event
+const selftestTimeout = 5 * time.Second
+
+// selftestEnv tells aistat's wrappers not to run chained user programs with
+// the synthetic payloads, and ingest not to count them.
+const selftestEnv = "AISTAT_SELFTEST"
+
+// selftestSessionPrefix starts the session ID of every selftest event, so
+// draining them does not count them as received either.
+const selftestSessionPrefix = "aistat-selftest-"
+
+// claudeHookEvents are the events installClaude registers a hook for
+var claudeHookEvents = []string{
+	"Notification", "PermissionRequest", "PostToolUse", "PreToolUse",
+	"SessionEnd", "SessionStart", "Stop", "UserPromptSubmit",
+}
+
+// aistatHookEvents lists the events of Claude settings with an aistat hook
+func aistatHookEvents(settings map[string]any) []string {
+	var events []string
+	hooks, _ := settings["hooks"].(map[string]any)
+	for event, v := range hooks {
+		arr, _ := v.([]any)
+	scan:
+		for _, item := range arr {
+			mm, _ := item.(map[string]any)
+			hs, _ := mm["hooks"].([]any)
+			for _, h := range hs {
+				hm, _ := h.(map[string]any)
+				if isAistatHookCommand(asString(hm["command"])) {
+					events = append(events, event)
+					break scan
+				}
+			}
+		}
+	}
+	sort.Strings(events)
+	return events
+}
+
+// aistatHookCommand is the command of event's first aistat hook, or "".
+func aistatHookCommand(settings map[string]any, event string) string {
+	hooks, _ := settings["hooks"].(map[string]any)
+	arr, _ := hooks[event].([]any)
+	for _, item := range arr {
+		mm, _ := item.(map[string]any)
+		hs, _ := mm["hooks"].([]any)
+		for _, h := range hs {
+			hm, _ := h.(map[string]any)
+			if c := asString(hm["command"]); isAistatHookCommand(c) {
+				return c
+			}
+		}
+	}
+	return ""
+}
+
+// runSelftest feeds synthetic hook, statusline and notify payloads through
+// the installed wrappers under a throwaway session ID, checks that each one
+// is spooled and drains into a session record, and removes what it created.
+// Registration and wrapper problems are doctor's other checks; a flow that
+// cannot run for one of them is reported as info.
+func runSelftest() []doctorCheck {
+	ad, err := appDir()
+	if err == nil {
+		err = ensureAppDirs()
+	}
+	if err != nil {
+		return []doctorCheck{{ID: "selftest", Severity: severityError, Detail: err.Error()}}
+	}
+	claudeTargets, codexTargets, _ := recordedTargets()
+	sid := fmt.Sprintf("%s%d", selftestSessionPrefix, time.Now().UnixNano())
+	defer cleanupSelftest(sid)
+
+	hooksOK, statusOK := false, false
+	for _, t := range claudeTargets {
+		b, err := os.ReadFile(t.Path)
+		if err != nil {
+			continue
+		}
+		var settings map[string]any
+		if json.Unmarshal(b, &settings) != nil {
+			continue
+		}
+		if slices.Contains(aistatHookEvents(settings), "UserPromptSubmit") {
+			hooksOK = true
+		}
+		sl, _ := settings["statusLine"].(map[string]any)
+		if isAistatStatusline(asString(sl["command"])) {
+			statusOK = true
+		}
+	}
+	hookWrapper := filepath.Join(ad, "bin", "aistat-claude-hook")
+	statusWrapper := filepath.Join(ad, "bin", "aistat-claude-statusline")
+	exe, _ := os.Executable()
+	exe, _ = filepath.Abs(exe)
+	var notifyWrapper string
+	for _, t := range codexTargets {
+		b, err := os.ReadFile(t.Path)
+		if err != nil {
+			continue
+		}
+		settings, err := parseCodexNotify(string(b))
+		if err != nil {
+			continue
+		}
+		for _, profile := range settings.targets() {
+			wrapper := filepath.Join(ad, "bin", codexNotifyWrapperName(t.Path, profile))
+			if classifyNotifyArgv(settings.notify(profile), exe, wrapper) == codexNotifyWrapper && notifyWrapper == "" && wrapperProblem(wrapper) == "" {
+				notifyWrapper = wrapper
+			}
+		}
+	}
+
+	var checks []doctorCheck
+	cwd := os.TempDir()
+	if hooksOK && wrapperProblem(hookWrapper) == "" {
+		payload := fmt.Sprintf(`{"hook_event_name":"UserPromptSubmit","session_id":%q,"cwd":%q,"prompt":"aistat selftest"}`, sid, cwd)
+		checks = append(checks, selftestEvent("selftest.claude_hook", hookWrapper, nil, payload, ProviderClaude, "hook", sid, drainClaudeSpool,
+			func(rec SessionRecord) bool { return rec.LastEventName == "UserPromptSubmit" }))
+	} else {
+		checks = append(checks, doctorCheck{ID: "selftest.claude_hook", Severity: severityInfo, Detail: "skipped: hooks are not installed"})
+	}
+	if statusOK && wrapperProblem(statusWrapper) == "" {
+		payload := fmt.Sprintf(`{"session_id":%q,"cwd":%q,"model":{"id":"selftest","display_name":"aistat-selftest"},"workspace":{"current_dir":%q}}`, sid, cwd, cwd)
+		checks = append(checks, selftestEvent("selftest.claude_statusline", statusWrapper, nil, payload, ProviderClaude, "statusline", sid, drainClaudeSpool,
+			func(rec SessionRecord) bool { return rec.ModelDisplay == "aistat-selftest" }))
+	} else {
+		checks = append(checks, doctorCheck{ID: "selftest.claude_statusline", Severity: severityInfo, Detail: "skipped: statusLine is not installed"})
+	}
+	if notifyWrapper != "" {
+		payload := fmt.Sprintf(`{"type":"agent-turn-complete","thread-id":%q,"cwd":%q,"last-assistant-message":"aistat selftest"}`, sid, cwd)
+		checks = append(checks, selftestEvent("selftest.codex_notify", notifyWrapper, []string{payload}, "", ProviderCodex, "notify", sid, drainCodexSpool,
+			func(rec SessionRecord) bool { return rec.LastAssistantText == "aistat selftest" }))
+	} else {
+		checks = append(checks, doctorCheck{ID: "selftest.codex_notify", Severity: severityInfo, Detail: "skipped: Codex notify is not wired"})
+	}
+	return checks
+}
+
+// selftestEvent runs a wrapper with a payload (as args or on stdin), waits
+// for the event to be spooled, drains it and checks the session record.
+func selftestEvent(id, wrapper string, args []string, stdin string, provider Provider, kind, sid string, drain func() error, check func(SessionRecord) bool) doctorCheck {
+	c := doctorCheck{ID: id, Severity: severityError, Target: wrapper}
+	ctx, cancel := context.WithTimeout(context.Background(), selftestTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, wrapper, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(os.Environ(), selftestEnv+"=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	start := time.Now()
+	err := cmd.Run()
+	latency := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		c.Detail = "wrapper failed: " + strings.TrimSpace(err.Error()+" "+stderr.String())
+		c.Fix = "run `aistat doctor --fix`"
+		return c
+	}
+
+	rp, err := recordPath(provider, sid)
+	if err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	sd, _ := spoolDir()
+	pattern := filepath.Join(sd, string(provider), kind, fileSafeRe.ReplaceAllString(sid, "_")+"*.json")
+	deadline := time.Now().Add(selftestTimeout)
+	spooled := false
+	for time.Now().Before(deadline) {
+		if m, _ := filepath.Glob(pattern); len(m) > 0 {
+			spooled = true
+			break
+		}
+		if rec, err := loadRecord(rp); err == nil && check(rec) {
+			// A running aistat drained it already
+			c.Severity, c.Detail = severityOK, fmt.Sprintf("wrapper %s, drained by a running aistat", latency)
+			return c
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !spooled {
+		c.Detail = fmt.Sprintf("wrapper %s, but no spool file after %s", latency, selftestTimeout)
+		c.Fix = "check that the wrapper calls this aistat (`aistat doctor --fix`)"
+		return c
+	}
+	spoolAfter := time.Since(start).Round(time.Millisecond)
+
+	if err := drain(); err != nil {
+		c.Detail = "drain failed: " + err.Error()
+		return c
+	}
+	rec, err := loadRecord(rp)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		c.Detail = "spooled, but the spool did not drain into a session record"
+	case err != nil:
+		c.Detail = "session record: " + err.Error()
+	case !check(rec):
+		c.Detail = "session record is missing the event"
+	default:
+		c.Severity = severityOK
+		c.Detail = fmt.Sprintf("wrapper %s, spooled after %s", latency, spoolAfter)
+	}
+	return c
+}
+
+// cleanupSelftest removes the records and spool files of the self-test
+// session.
+func cleanupSelftest(sid string) {
+	safe := fileSafeRe.ReplaceAllString(sid, "_")
+	for _, p := range []Provider{ProviderClaude, ProviderCodex} {
+		if rp, err := recordPath(p, sid); err == nil {
+			_ = os.Remove(rp)
+			_ = os.Remove(rp + ".lock")
+		}
+	}
+	if sd, err := spoolDir(); err == nil {
+		matches, _ := filepath.Glob(filepath.Join(sd, "*", "*", safe+"*.json"))
+		for _, m := range matches {
+			_ = os.Remove(m)
+		}
+	}
+}
@@ -86,6 +86,8 @@ func buildHelpDoc() helpDoc {
 		{Name: "--sort", Type: "string", Default: "last_seen", Description: "Sort by: last_seen|status|provider|cost|project"},
 		{Name: "--group-by", Type: "string", Default: "", Description: "Group by: provider|project|status|day|hour (non-TUI)"},
 		{Name: "--include-last-msg", Type: "bool", Default: "false", Description: "Include last message snippets when available"},
+		{Name: "--reset-ui", Type: "bool", Default: "false", Description: "Forget saved TUI state (pins, filters, layout)"},
+		{Name: "--accessible", Type: "bool", Default: "false", Description: "Plain-text TUI for screen readers (on when TERM=dumb)"},
 		{Name: "--all", Type: "bool", Default: "false", Description: "Include ended/stale sessions (wider scan window)"},
 		{Name: "--redact", Type: "bool", Default: "true", Description: "Redact paths/IDs"},
 		{Name: "--active-window", Type: "duration", Default: "30m", Description: "Active session window"},
@@ -100,11 +102,16 @@ func buildHelpDoc() helpDoc {
 		{Name: "projects", Usage: "aistat projects [--json] [--all] [--sort count|name|last_seen]", Description: "List active projects with counts and last activity"},
 		{Name: "show", Usage: "aistat show <id> [--json]", Description: "Show details for a single session"},
 		{Name: "summary", Usage: "aistat summary [--group-by project] [--json]", Description: "Summarize sessions by group"},
+		{Name: "latency", Usage: "aistat latency [--since 168h] [--by project|provider|hour|state] [--top 10] [--json]", Description: "Report how long sessions wait on a person, with percentiles and the worst idle periods"},
+		{Name: "wait", Usage: "aistat wait <id> [--for waiting|approval|ended|idle] [--timeout 30m] | aistat wait --project <name> --all-idle", Description: "Block until a session (or every session in a project) reaches a state"},
+		{Name: "policy", Usage: "aistat policy list | test --tool <name> [--command <cmd>] [--path <file>] | audit [-n 50] [--json]", Description: "Inspect/test Claude PreToolUse policy rules and the decision audit log"},
+		{Name: "mcp", Usage: "aistat mcp [--no-redact]", Description: "Run an MCP stdio server with list_sessions, get_session, list_projects and summary tools (redacted by default)"},
 		{Name: "tail", Usage: "aistat tail <id> [--follow]", Description: "Tail a session transcript/log"},
-		{Name: "install", Usage: "aistat install [flags]", Description: "Install Claude/Codex integrations"},
-		{Name: "doctor", Usage: "aistat doctor [--fix]", Description: "Check setup and optionally auto-fix"},
-		{Name: "config", Usage: "aistat config --show|--init", Description: "Show or initialize config"},
-		{Name: "clean", Usage: "aistat clean [--dry-run] [--spool] [--sessions]", Description: "Remove spool files and invalid session records"},
+		{Name: "install", Usage: "aistat install [--scope user|project|local] [--project DIR] [--claude-dir DIR]... [--codex-home DIR]... [--codex-profile NAME]...", Description: "Install Claude/Codex integrations into user, project or local Claude settings and any Codex homes/profiles"},
+		{Name: "uninstall", Usage: "aistat uninstall [--dry-run] [--backups|--restore <backup|latest>|--prune [--keep N]]", Description: "Remove Claude/Codex integrations (restoring chained originals); list, restore or prune config backups"},
+		{Name: "doctor", Usage: "aistat doctor [--fix] [--selftest] [--json]", Description: "Check setup (each check with an ID, severity and fix; exit 4 on errors), optionally auto-fix, and self-test the hook pipeline end to end"},
+		{Name: "config", Usage: "aistat config --show|--init|--themes|--keys", Description: "Show or initialize config; list TUI themes and key actions"},
+		{Name: "clean", Usage: "aistat clean [--dry-run] [--spool] [--sessions]", Description: "Remove spool files, invalid session records and stale lock files"},
 		{Name: "help", Usage: "aistat help [--format json]", Description: "Extended help for humans/agents"},
 	}
 
@@ -116,10 +123,15 @@ func buildHelpDoc() helpDoc {
 			"aistat projects [flags]",
 			"aistat show <id> [flags]",
 			"aistat summary [flags]",
+			"aistat latency [flags]",
+			"aistat wait <id> [flags]",
+			"aistat policy list|test|audit",
+			"aistat mcp",
 			"aistat tail <id> [flags]",
 			"aistat install [flags]",
-			"aistat doctor [--fix]",
-			"aistat config --show|--init",
+			"aistat uninstall [flags]",
+			"aistat doctor [--fix] [--selftest] [--json]",
+			"aistat config --show|--init|--themes|--keys",
 			"aistat clean [--dry-run]",
 			"aistat help [--format json]",
 		},
@@ -133,18 +145,39 @@ func buildHelpDoc() helpDoc {
 			"0": "Success",
 			"1": "Generic failure",
 			"2": "Invalid usage or unsupported platform",
+			"3": "Timed out (wait)",
+			"4": "An error-level check failed (doctor)",
 		},
 		Env: map[string]string{
-			"AISTAT_HOME": "Override app data directory",
-			"CODEX_HOME":  "Override Codex home directory",
-			"ACCESSIBLE":  "Enable accessible install wizard",
+			"AISTAT_HOME":       "Override app data directory",
+			"CODEX_HOME":        "Override Codex home directory",
+			"CLAUDE_CONFIG_DIR": "Override Claude Code config directory (settings.json, projects/)",
+			"ACCESSIBLE":        "Enable accessible install wizard",
 		},
 		Config: map[string]string{
-			"path": "~/Library/Application Support/aistat/config.json",
+			"path":                    "~/Library/Application Support/aistat/config.json",
+			"statusline_segment":      "Append aistat's segment to a chained statusLine's output (default false)",
+			"permission_hold":         "How long Claude permission prompts wait for a TUI decision (Y/X) while the TUI is open; 0s disables",
+			"policy":                  "policy.rules[] {name, tool, command, path, outside_project, project, decision: allow|deny|ask, reason}; policy.audit (default true); with rules, `aistat install` runs the PreToolUse hook synchronously; invalid rules answer ask",
+			"tui.theme":               "auto (default; dark or light by terminal background), dark, light, high-contrast, or a file name in <app dir>/themes",
+			"tui.keys":                "Rebind TUI actions: {\"action\": [\"key\", ...]}; see `aistat config --keys`",
+			"tui.accessible":          "Plain-text TUI for screen readers and dumb terminals (same as --accessible; on when TERM=dumb)",
+			"redaction.projects":      "keep (default) or hash (stable salted pseudonyms like project-1a2b3c)",
+			"redaction.ids":           "short (default; abc123…xyz) or hash (stable salted pseudonyms like s-1a2b3c4d5e)",
+			"redaction.paths":         "short (default; last two components), base (last component) or hash (components hashed)",
+			"redaction.messages":      "scrub (default; masks API keys, tokens, emails, private URLs and home paths) or hide (<redacted>)",
+			"redaction.aliases":       "Project name -> alias, used in project columns, paths and messages",
+			"redaction.path_rules":    "[{prefix, replace}]; a path under prefix (~ expands) shows as replace + the rest",
+			"redaction.patterns":      "Extra regexes masked as <redacted> in messages",
+			"redaction.private_hosts": "Host suffixes whose URLs are masked (localhost, private IPs, .local, .internal, .corp, .lan built in)",
+			"redaction.salt":          "Pseudonym salt (default: generated into <app dir>/redact_salt)",
 		},
 		Notes: []string{
 			"Use `--watch --json` to stream NDJSON for dashboards.",
-			"TUI keybinds: / filter, : palette, tab dashboard, p projects, s sort, g group, v view, m last-msg, b sidebar.",
+			"TUI keybinds: / filter, : palette, tab dashboard, p projects, s sort, g group, v view, m last-msg, u gauges, b sidebar, f follow transcript, t theme (rebind via tui.keys).",
+			"TUI bulk actions (on the selection or cursor): e end, H hide, T tag, C copy resume commands, o open logs; :export json|md [path].",
+			"Hook pipeline health: per-provider ingest counters in <app dir>/ingest_stats.json (see `aistat doctor`); spool files failing 5 drains move to <app dir>/quarantine.",
+			"TUI mouse: click selects, double-click opens detail, wheel scrolls; click sidebar entries or header counts to toggle filters.",
 		},
 	}
 }
@@ -16,25 +16,27 @@ import (
 // Claude ingestion
 // -------------------------
 
-func ingestClaudeHook(r io.Reader) error {
+// ingestClaudeHook spools a hook event. For PreToolUse it also evaluates the
+// policy rules, and for PermissionRequest it may wait for a TUI decision; in
+// both cases Claude's hook output JSON is written to w. Rejected events are
+// counted in the ingest stats here, spooled ones when they are drained.
+func ingestClaudeHook(r io.Reader, w io.Writer) (err error) {
 	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
 		// Avoid reading from a TTY; hook input should be piped JSON.
 		return nil
 	}
+	defer func() { recordIngest(ProviderClaude, err) }()
 	var m map[string]any
 	dec := json.NewDecoder(io.LimitReader(r, 10*1024*1024))
 	if err := dec.Decode(&m); err != nil {
-		if errors.Is(err, io.EOF) {
-			return nil
-		}
-		return err
+		return decodeReject(err)
 	}
 
 	event := strings.TrimSpace(getString(m, "hook_event_name"))
 	sid := normalizePlaceholder(getString(m, "session_id"))
-	if sid == "" {
-		// No session => ignore (but return nil to not break hooks)
-		return nil
+	if sid == "" || !validSessionID(sid) {
+		// No session => ignore (the hook command never fails)
+		return reject(rejectInvalidID, nil)
 	}
 
 	now := time.Now().UTC()
@@ -51,6 +53,9 @@ func ingestClaudeHook(r io.Reader) error {
 		CWD:            cwd,
 		LastEventName:  event,
 	}
+	// Only high-frequency tool events may collapse into a single spool file;
+	// turn boundaries and status changes must survive until the next drain.
+	overwrite := event == "PreToolUse" || event == "PostToolUse"
 
 	switch event {
 	case "SessionStart":
@@ -59,12 +64,17 @@ func ingestClaudeHook(r io.Reader) error {
 	case "UserPromptSubmit":
 		patch.Status = StatusRunning
 		patch.StatusReason = "user prompt submitted"
+		patch.Turn = turnStart
+	case "PermissionRequest":
+		patch.Status = StatusApproval
+		patch.StatusReason = "awaiting approval"
 	case "PreToolUse", "PostToolUse":
 		patch.Status = StatusRunning
 		patch.StatusReason = "tool activity"
 	case "Stop":
 		patch.Status = StatusWaiting
 		patch.StatusReason = "awaiting input"
+		patch.Turn = turnEnd
 	case "Notification":
 		patch.LastNotificationType = notifType
 		patch.LastNotificationMsg = notifMsg
@@ -75,6 +85,7 @@ func ingestClaudeHook(r io.Reader) error {
 		case "idle_prompt":
 			patch.Status = StatusWaiting
 			patch.StatusReason = "awaiting input"
+			patch.Turn = turnClose
 		default:
 			patch.Status = StatusWaiting
 			if notifType != "" {
@@ -87,14 +98,28 @@ func ingestClaudeHook(r io.Reader) error {
 		patch.Status = StatusEnded
 		patch.StatusReason = "session ended"
 		patch.EndedAt = now.Format(time.RFC3339Nano)
+		patch.Turn = turnClose
 	default:
 		// keep as-is
 	}
 
-	if b, err := json.Marshal(patch); err == nil {
-		_ = writeSpoolBytes(ProviderClaude, "hook", sid, b, true)
+	if overwrite {
+		patch.FirstAt = collapsedFirstAt(sid, patch.At)
+	} else {
+		// Tool events before this one stay a group of their own, applied first
+		_ = sealSpoolOverwrite(ProviderClaude, "hook", sid)
+	}
+	b, _ := json.Marshal(patch)
+	if werr := writeSpoolBytes(ProviderClaude, "hook", sid, b, overwrite); werr != nil {
+		err = reject(rejectSpoolWrite, werr)
+	}
+	switch event {
+	case "PreToolUse":
+		applyClaudePolicy(loadConfig(), m, w)
+	case "PermissionRequest":
+		holdClaudePermission(loadConfig(), m, w)
 	}
-	return nil
+	return err
 }
 
 type ClaudeStatuslineInput struct {
@@ -134,6 +159,7 @@ type ClaudeStatuslineInput struct {
 type ClaudeHookPatch struct {
 	SessionID            string `json:"session_id"`
 	At                   string `json:"at"`
+	FirstAt              string `json:"first_at,omitempty"` // first of the tool events collapsed into this patch
 	TranscriptPath       string `json:"transcript_path,omitempty"`
 	CWD                  string `json:"cwd,omitempty"`
 	LastEventName        string `json:"last_event_name,omitempty"`
@@ -142,6 +168,39 @@ type ClaudeHookPatch struct {
 	EndedAt              string `json:"ended_at,omitempty"`
 	LastNotificationType string `json:"last_notification_type,omitempty"`
 	LastNotificationMsg  string `json:"last_notification_msg,omitempty"`
+	Turn                 string `json:"turn,omitempty"` // turnStart, turnEnd or turnClose
+}
+
+// Turn markers carried by hook patches.
+const (
+	turnStart = "start"
+	turnEnd   = "end"
+	turnClose = "close"
+)
+
+// collapsedFirstAt returns when the first tool event in the session's
+// pending overwrite file happened (at when there is none), so collapsing the
+// tool events keeps the time the session went back to work.
+func collapsedFirstAt(sid, at string) string {
+	p, err := spoolPath(ProviderClaude, "hook", sid)
+	if err != nil {
+		return at
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return at
+	}
+	var prev ClaudeHookPatch
+	if err := json.Unmarshal(b, &prev); err != nil {
+		return at
+	}
+	if prev.FirstAt != "" {
+		return prev.FirstAt
+	}
+	if prev.At != "" {
+		return prev.At
+	}
+	return at
 }
 
 type ClaudeStatuslinePatch struct {
@@ -167,6 +226,8 @@ type ClaudeStatuslinePatch struct {
 }
 
 // ingestClaudeStatusline updates the session record and returns a single-line statusline string for Claude Code.
+// Rejected input is counted in the ingest stats here, spooled updates when
+// they are drained; updates skipped by statusline_min_write are not.
 func ingestClaudeStatusline(r io.Reader) (string, error) {
 	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
 		return "", errors.New("stdin is a TTY")
@@ -174,14 +235,15 @@ func ingestClaudeStatusline(r io.Reader) (string, error) {
 	var in ClaudeStatuslineInput
 	dec := json.NewDecoder(io.LimitReader(r, 10*1024*1024))
 	if err := dec.Decode(&in); err != nil {
+		err = decodeReject(err)
+		recordIngest(ProviderClaude, err)
 		return "", err
 	}
-	if strings.TrimSpace(in.SessionID) == "" {
-		return "", errors.New("missing session_id")
-	}
 	in.SessionID = normalizePlaceholder(in.SessionID)
-	if in.SessionID == "" {
-		return "", errors.New("invalid session_id")
+	if in.SessionID == "" || !validSessionID(in.SessionID) {
+		err := reject(rejectInvalidID, nil)
+		recordIngest(ProviderClaude, err)
+		return "", err
 	}
 
 	cfg := loadConfig()
@@ -231,8 +293,9 @@ func ingestClaudeStatusline(r io.Reader) (string, error) {
 			patch.CurrentCacheCreateTokens = in.ContextWindow.CurrentUsage.CacheCreationInputTokens
 			patch.CurrentCacheReadTokens = in.ContextWindow.CurrentUsage.CacheReadInputTokens
 		}
-		if b, err := json.Marshal(patch); err == nil {
-			_ = writeSpoolBytes(ProviderClaude, "statusline", sid, b, true)
+		b, _ := json.Marshal(patch)
+		if err := writeSpoolBytes(ProviderClaude, "statusline", sid, b, true); err != nil {
+			recordIngest(ProviderClaude, reject(rejectSpoolWrite, err))
 		}
 	}
 	// Build a compact statusline for Claude Code (ANSI is allowed).
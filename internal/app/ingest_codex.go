@@ -2,6 +2,7 @@ package app
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,6 +20,10 @@ import (
 // Codex ingestion + scanning
 // -------------------------
 
+// CodexNotifyPayload covers both notify shapes: the legacy one piped on stdin
+// ({"type", "data": {...}}) and the one Codex passes as its last argument,
+// with hyphenated top-level keys ({"type", "thread-id", "turn-id", "cwd",
+// "input-messages", "last-assistant-message"}).
 type CodexNotifyPayload struct {
 	Type      string `json:"type"`
 	Timestamp string `json:"timestamp"`
@@ -30,6 +35,12 @@ type CodexNotifyPayload struct {
 		ThreadID  string `json:"thread_id"`
 		TurnID    string `json:"turn_id"`
 	} `json:"data"`
+
+	ThreadID             string   `json:"thread-id"`
+	TurnID               string   `json:"turn-id"`
+	CWD                  string   `json:"cwd"`
+	InputMessages        []string `json:"input-messages"`
+	LastAssistantMessage string   `json:"last-assistant-message"`
 }
 
 type CodexNotifyPatch struct {
@@ -42,28 +53,53 @@ type CodexNotifyPatch struct {
 	Message   string `json:"message,omitempty"`
 	EventName string `json:"event_name,omitempty"`
 	EventType string `json:"event_type,omitempty"`
+
+	LastUserText      string `json:"last_user_text,omitempty"`
+	LastAssistantText string `json:"last_assistant_text,omitempty"`
 }
 
-func ingestCodexNotify(r io.Reader) error {
+// ingestCodexNotify reads a notify payload from the last argument (how Codex
+// invokes notify programs) or, without arguments, from stdin. Rejected
+// payloads are counted in the ingest stats here, spooled ones when they are
+// drained.
+func ingestCodexNotify(r io.Reader, args []string) error {
+	var n CodexNotifyPayload
+	if len(args) > 0 {
+		err := json.Unmarshal([]byte(args[len(args)-1]), &n)
+		if err != nil {
+			err = reject(rejectBadJSON, err)
+		} else {
+			err = spoolCodexNotify(n)
+		}
+		recordIngest(ProviderCodex, err)
+		return err
+	}
 	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
 		// Avoid reading from a TTY; notify input should be piped JSON.
 		return nil
 	}
-	var n CodexNotifyPayload
 	dec := json.NewDecoder(io.LimitReader(r, 10*1024*1024))
-	if err := dec.Decode(&n); err != nil {
-		if errors.Is(err, io.EOF) {
-			return nil
-		}
-		return err
+	err := dec.Decode(&n)
+	if err != nil {
+		err = decodeReject(err)
+	} else {
+		err = spoolCodexNotify(n)
 	}
+	recordIngest(ProviderCodex, err)
+	return err
+}
+
+func spoolCodexNotify(n CodexNotifyPayload) error {
 	id := normalizePlaceholder(n.Data.SessionID)
 	if id == "" {
 		id = normalizePlaceholder(n.Data.ThreadID)
 	}
 	if id == "" {
+		id = normalizePlaceholder(n.ThreadID)
+	}
+	if id == "" || !validSessionID(id) {
 		// Avoid breaking user’s Codex. Just no-op.
-		return nil
+		return reject(rejectInvalidID, nil)
 	}
 
 	ts := time.Now().UTC()
@@ -74,18 +110,27 @@ func ingestCodexNotify(r io.Reader) error {
 	}
 
 	patch := CodexNotifyPatch{
-		SessionID: id,
-		At:        ts.Format(time.RFC3339Nano),
-		CWD:       normalizePlaceholder(n.Data.CWD),
-		ThreadID:  normalizePlaceholder(n.Data.ThreadID),
-		TurnID:    normalizePlaceholder(n.Data.TurnID),
-		Title:     normalizePlaceholder(n.Data.Title),
-		Message:   n.Data.Message,
-		EventName: "notify:" + n.Type,
-		EventType: n.Type,
-	}
-	if b, err := json.Marshal(patch); err == nil {
-		_ = writeSpoolBytes(ProviderCodex, "notify", id, b, true)
+		SessionID:         id,
+		At:                ts.Format(time.RFC3339Nano),
+		CWD:               safe(normalizePlaceholder(n.Data.CWD), normalizePlaceholder(n.CWD)),
+		ThreadID:          safe(normalizePlaceholder(n.Data.ThreadID), normalizePlaceholder(n.ThreadID)),
+		TurnID:            safe(normalizePlaceholder(n.Data.TurnID), normalizePlaceholder(n.TurnID)),
+		Title:             normalizePlaceholder(n.Data.Title),
+		Message:           n.Data.Message,
+		EventName:         "notify:" + n.Type,
+		EventType:         n.Type,
+		LastAssistantText: strings.TrimSpace(n.LastAssistantMessage),
+	}
+	// The last input message that is not the injected environment context
+	for i := len(n.InputMessages) - 1; i >= 0; i-- {
+		if text := strings.TrimSpace(n.InputMessages[i]); text != "" && !looksLikeEnvironmentContext(text) {
+			patch.LastUserText = text
+			break
+		}
+	}
+	b, _ := json.Marshal(patch)
+	if err := writeSpoolBytes(ProviderCodex, "notify", id, b, true); err != nil {
+		return reject(rejectSpoolWrite, err)
 	}
 	return nil
 }
@@ -111,6 +156,8 @@ type codexTail struct {
 	LastRole          string
 	LastUserText      string
 	LastAssistantText string
+	Usage             TokenUsage  // last token_count event
+	EventTimes        []time.Time // line timestamps within activityWindow of LastTS
 }
 
 func scanCodexRollouts(cfg Config, now time.Time) ([]SessionRecord, error) {
@@ -158,6 +205,8 @@ func scanCodexRollouts(cfg Config, now time.Time) ([]SessionRecord, error) {
 		})
 	}
 
+	turnCache := loadCodexTurnCache()
+	cacheChanged := false
 	var out []SessionRecord
 	for _, fp := range files {
 		hdr, err := scanCodexHeader(fp, cfg.HeaderScanLines)
@@ -177,18 +226,21 @@ func scanCodexRollouts(cfg Config, now time.Time) ([]SessionRecord, error) {
 		}
 
 		rec := SessionRecord{
-			Provider:          ProviderCodex,
-			ID:                id,
-			RolloutPath:       fp,
-			CWD:               normalizePlaceholder(hdr.CWD),
-			ModelID:           hdr.Model,
-			ApprovalPolicy:    hdr.ApprovalPolicy,
-			LastSeen:          tail.LastTS,
-			LastEvent:         tail.LastTS,
-			LastEventName:     fmt.Sprintf("%s/%s", tail.LastEntryType, tail.LastPayloadType),
-			LastUserText:      tail.LastUserText,
-			LastAssistantText: tail.LastAssistantText,
-			UpdatedAt:         now,
+			Provider:           ProviderCodex,
+			ID:                 id,
+			RolloutPath:        fp,
+			CWD:                normalizePlaceholder(hdr.CWD),
+			ModelID:            hdr.Model,
+			ApprovalPolicy:     hdr.ApprovalPolicy,
+			LastSeen:           tail.LastTS,
+			LastEvent:          tail.LastTS,
+			LastEventName:      fmt.Sprintf("%s/%s", tail.LastEntryType, tail.LastPayloadType),
+			LastUserText:       tail.LastUserText,
+			LastAssistantText:  tail.LastAssistantText,
+			ContextWindowSize:  tail.Usage.ContextWindow,
+			CurrentInputTokens: tail.Usage.ContextTokens,
+			Activity:           tail.EventTimes,
+			UpdatedAt:          now,
 		}
 
 		// If we couldn't parse a timestamp, fall back to modtime.
@@ -201,6 +253,11 @@ func scanCodexRollouts(cfg Config, now time.Time) ([]SessionRecord, error) {
 			rec.LastEvent = rec.LastSeen
 		}
 
+		// Turn boundaries come from task_started/task_complete events.
+		if cachedCodexTurns(turnCache, fp, &rec) {
+			cacheChanged = true
+		}
+
 		// Heuristic: mark approvals if the tail indicates approval requested.
 		if strings.Contains(strings.ToLower(tail.LastPayloadType), "approval") {
 			rec.Status = StatusApproval
@@ -210,6 +267,20 @@ func scanCodexRollouts(cfg Config, now time.Time) ([]SessionRecord, error) {
 		out = append(out, rec)
 	}
 
+	// Forget rollouts that left every scan window
+	keep := cfg.AllScanWindow
+	if scanWindow > keep {
+		keep = scanWindow
+	}
+	for p, e := range turnCache {
+		if now.Sub(e.ModTime) > keep {
+			delete(turnCache, p)
+			cacheChanged = true
+		}
+	}
+	if cacheChanged {
+		saveCodexTurnCache(turnCache)
+	}
 	return out, nil
 }
 
@@ -319,6 +390,15 @@ func scanCodexTail(filePath string, tailBytes int) (codexTail, error) {
 			}
 		}
 
+		// Capture the last token_count (context usage).
+		if e.Type == "event_msg" && tail.Usage.ContextWindow == 0 {
+			var payload map[string]any
+			_ = json.Unmarshal(e.Payload, &payload)
+			if u, ok := codexTokenUsage(payload); ok {
+				tail.Usage = u
+			}
+		}
+
 		// Capture last user + assistant snippets (optional).
 		if e.Type == "response_item" {
 			var payload map[string]any
@@ -337,14 +417,189 @@ func scanCodexTail(filePath string, tailBytes int) (codexTail, error) {
 			}
 		}
 
-		if tail.LastEntryType != "" && !tail.LastTS.IsZero() && tail.LastUserText != "" && tail.LastAssistantText != "" {
+		if tail.LastEntryType != "" && !tail.LastTS.IsZero() && tail.LastUserText != "" && tail.LastAssistantText != "" && tail.Usage.ContextWindow > 0 {
 			break
 		}
 	}
+	if !tail.LastTS.IsZero() {
+		tail.EventTimes = tailEventTimes(b, tail.LastTS.Add(-activityWindow))
+	}
 
 	return tail, nil
 }
 
+// codexTurnState is the turn data replayed from a rollout.
+type codexTurnState struct {
+	TurnStartedAt   *time.Time         `json:"turn_started_at,omitempty"`
+	TurnEndedAt     *time.Time         `json:"turn_ended_at,omitempty"`
+	TurnCount       int                `json:"turn_count,omitempty"`
+	TurnDurationsMS []int64            `json:"turn_durations_ms,omitempty"`
+	Transitions     []StatusTransition `json:"transitions,omitempty"`
+}
+
+func (s codexTurnState) applyTo(rec *SessionRecord) {
+	rec.TurnStartedAt = s.TurnStartedAt
+	rec.TurnEndedAt = s.TurnEndedAt
+	rec.TurnCount = s.TurnCount
+	rec.TurnDurationsMS = s.TurnDurationsMS
+	rec.Transitions = s.Transitions
+}
+
+func turnStateOf(rec SessionRecord) codexTurnState {
+	return codexTurnState{
+		TurnStartedAt:   rec.TurnStartedAt,
+		TurnEndedAt:     rec.TurnEndedAt,
+		TurnCount:       rec.TurnCount,
+		TurnDurationsMS: rec.TurnDurationsMS,
+		Transitions:     rec.Transitions,
+	}
+}
+
+// codexTurnCacheEntry is a rollout's turn state up to Offset, so a scan only
+// parses the lines appended since the last one. Rollouts are append-only; a
+// file that shrank or changed without growing is replayed from the start.
+type codexTurnCacheEntry struct {
+	Offset  int64          `json:"offset"`
+	Size    int64          `json:"size"`
+	ModTime time.Time      `json:"mod_time"`
+	State   codexTurnState `json:"state"`
+}
+
+type codexTurnCache map[string]codexTurnCacheEntry // rollout path -> entry
+
+func codexTurnCachePath() (string, error) {
+	ad, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ad, "codex_turns.json"), nil
+}
+
+func loadCodexTurnCache() codexTurnCache {
+	cache := codexTurnCache{}
+	p, err := codexTurnCachePath()
+	if err != nil {
+		return cache
+	}
+	if b, err := os.ReadFile(p); err == nil {
+		_ = json.Unmarshal(b, &cache)
+	}
+	return cache
+}
+
+func saveCodexTurnCache(cache codexTurnCache) {
+	p, err := codexTurnCachePath()
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(filepath.Dir(p)); err != nil {
+		return // nothing installed yet; don't create the app dir for a cache
+	}
+	_ = writeJSONAtomic(p, cache)
+}
+
+// cachedCodexTurns sets rec's turn fields from the cache, parsing only what
+// was appended to the rollout since it was last scanned. It reports whether
+// the cache entry changed.
+func cachedCodexTurns(cache codexTurnCache, filePath string, rec *SessionRecord) bool {
+	st, err := os.Stat(filePath)
+	if err != nil {
+		return false
+	}
+	size, mod := st.Size(), st.ModTime().UTC()
+	entry, ok := cache[filePath]
+	if ok && entry.Size == size && entry.ModTime.Equal(mod) {
+		entry.State.applyTo(rec)
+		return false
+	}
+	if !ok || size < entry.Offset || size == entry.Size {
+		entry = codexTurnCacheEntry{}
+	}
+
+	var scratch SessionRecord
+	entry.State.applyTo(&scratch)
+	offset, err := scanCodexTurns(filePath, entry.Offset, &scratch)
+	if err != nil {
+		return false
+	}
+	entry.Offset, entry.Size, entry.ModTime = offset, size, mod
+	entry.State = turnStateOf(scratch)
+	entry.State.applyTo(rec)
+	cache[filePath] = entry
+	return true
+}
+
+// scanCodexTurns replays task lifecycle events from a rollout, starting at
+// byte offset from, into rec's turn fields and status transitions. It stops
+// before a trailing partial line and returns the offset it got to. Only
+// event_msg lines mentioning a task, turn, approval or command-start event
+// are decoded.
+func scanCodexTurns(filePath string, from int64, rec *SessionRecord) (int64, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return from, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(from, io.SeekStart); err != nil {
+		return from, err
+	}
+
+	offset := from
+	rd := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := rd.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return offset, nil // a partial line is parsed once it is complete
+			}
+			return offset, err
+		}
+		offset += int64(len(line))
+		applyCodexTurnLine(line, rec)
+	}
+}
+
+func applyCodexTurnLine(line []byte, rec *SessionRecord) {
+	if !bytes.Contains(line, []byte(`"event_msg"`)) {
+		return
+	}
+	if !bytes.Contains(line, []byte(`"task_`)) && !bytes.Contains(line, []byte(`"turn_aborted"`)) &&
+		!bytes.Contains(line, []byte(`_approval_request"`)) && !bytes.Contains(line, []byte(`_begin"`)) {
+		return
+	}
+	var e codexLogEntry
+	if err := json.Unmarshal(line, &e); err != nil || e.Type != "event_msg" {
+		return
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return
+	}
+	at, err := parseRFC3339ish(e.Timestamp)
+	if err != nil {
+		return
+	}
+	pt := asString(payload["type"])
+	switch {
+	case pt == "task_started":
+		beginTurn(rec, at)
+		recordTransition(rec, StatusRunning, "task started", at)
+	case pt == "task_complete":
+		completeTurn(rec, at)
+		recordTransition(rec, StatusWaiting, "turn complete", at)
+	case pt == "turn_aborted":
+		closeTurn(rec, at)
+		recordTransition(rec, StatusWaiting, "turn aborted", at)
+	case strings.HasSuffix(pt, "_approval_request"):
+		recordTransition(rec, StatusApproval, "awaiting approval", at)
+	case strings.HasSuffix(pt, "_begin"):
+		// Work resuming after an approval means the prompt was answered.
+		if n := len(rec.Transitions); n > 0 && rec.Transitions[n-1].Status == StatusApproval {
+			recordTransition(rec, StatusRunning, "approval answered", at)
+		}
+	}
+}
+
 func extractCodexMessageText(role string, content []any) string {
 	if len(content) == 0 {
 		return ""
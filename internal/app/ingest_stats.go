@@ -0,0 +1,326 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -------------------------
+// Ingest stats
+// -------------------------
+
+// spoolMaxAttempts is how many drains a spool file may fail before it is
+// moved to the quarantine directory.
+const spoolMaxAttempts = 5
+
+// Reasons an ingested event was rejected
+const (
+	rejectEmpty      = "empty_payload"
+	rejectInvalidID  = "missing_session_id"
+	rejectBadJSON    = "invalid_json"
+	rejectSpoolWrite = "spool_write"
+	rejectOther      = "other"
+)
+
+// rejectError is an ingest error with the reason it is counted under.
+type rejectError struct {
+	reason string
+	err    error
+}
+
+func (e *rejectError) Error() string {
+	if e.err == nil {
+		return e.reason
+	}
+	return e.reason + ": " + e.err.Error()
+}
+
+func (e *rejectError) Unwrap() error { return e.err }
+
+func reject(reason string, err error) error {
+	return &rejectError{reason: reason, err: err}
+}
+
+// decodeReject classifies a payload decode error.
+func decodeReject(err error) error {
+	if errors.Is(err, io.EOF) {
+		return reject(rejectEmpty, nil)
+	}
+	return reject(rejectBadJSON, err)
+}
+
+// providerIngestStats counts what the hooks of one provider delivered and
+// what draining the spool made of it.
+type providerIngestStats struct {
+	Received         int64            `json:"received"`           // events spooled
+	Rejected         map[string]int64 `json:"rejected,omitempty"` // reason -> count
+	LastEvent        time.Time        `json:"last_event,omitempty"`
+	LastReject       time.Time        `json:"last_reject,omitempty"`
+	LastRejectReason string           `json:"last_reject_reason,omitempty"`
+	DrainFailures    int64            `json:"drain_failures"`
+	LastDrainError   string           `json:"last_drain_error,omitempty"`
+	Quarantined      int64            `json:"quarantined"`
+	Failing          map[string]int   `json:"failing,omitempty"` // <kind>/<spool file> -> failed drains
+}
+
+// rejectedTotal sums the rejections over all reasons.
+func (s providerIngestStats) rejectedTotal() int64 {
+	var n int64
+	for _, c := range s.Rejected {
+		n += c
+	}
+	return n
+}
+
+// problem describes what is going wrong right now, or "": events rejected
+// since the last good one, or spool files that keep failing to apply.
+func (s providerIngestStats) problem() string {
+	switch {
+	case !s.LastReject.IsZero() && s.LastReject.After(s.LastEvent):
+		return "rejecting events (" + s.LastRejectReason + ")"
+	case len(s.Failing) > 0:
+		return fmt.Sprintf("%d spool file(s) failing to apply", len(s.Failing))
+	}
+	return ""
+}
+
+// summary is the counters on one line
+func (s providerIngestStats) summary() string {
+	parts := []string{fmt.Sprintf("received %d", s.Received)}
+	if n := s.rejectedTotal(); n > 0 {
+		reasons := make([]string, 0, len(s.Rejected))
+		for r, c := range s.Rejected {
+			reasons = append(reasons, fmt.Sprintf("%s %d", r, c))
+		}
+		sort.Strings(reasons)
+		parts = append(parts, fmt.Sprintf("rejected %d (%s)", n, strings.Join(reasons, ", ")))
+	}
+	if s.DrainFailures > 0 {
+		parts = append(parts, fmt.Sprintf("drain failures %d", s.DrainFailures))
+	}
+	if s.Quarantined > 0 {
+		parts = append(parts, fmt.Sprintf("quarantined %d", s.Quarantined))
+	}
+	return strings.Join(parts, ", ")
+}
+
+type ingestStats map[Provider]*providerIngestStats
+
+func ingestStatsPath() (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ingest_stats.json"), nil
+}
+
+func loadIngestStats() (ingestStats, error) {
+	p, err := ingestStatsPath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return ingestStats{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats := ingestStats{}
+	if err := json.Unmarshal(b, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+	return stats, nil
+}
+
+// updateIngestStats changes one provider's counters under the stats lock;
+// every hook process writes them.
+func updateIngestStats(provider Provider, mutate func(*providerIngestStats)) error {
+	if err := ensureAppDirs(); err != nil {
+		return err
+	}
+	p, err := ingestStatsPath()
+	if err != nil {
+		return err
+	}
+	return withLock(p+".lock", func() error {
+		stats, err := loadIngestStats()
+		if err != nil {
+			stats = ingestStats{} // start over rather than stop counting
+		}
+		s := stats[provider]
+		if s == nil {
+			s = &providerIngestStats{}
+			stats[provider] = s
+		}
+		mutate(s)
+		return writeJSONAtomic(p, stats)
+	})
+}
+
+// recordIngest counts an ingest event rejected for the reason err carries.
+// Spooled events are counted by the drain that applies them, so a good event
+// never takes the stats lock in the hook process; selftest events are not
+// counted at all.
+func recordIngest(provider Provider, err error) {
+	if err == nil || os.Getenv(selftestEnv) != "" {
+		return
+	}
+	now := time.Now().UTC()
+	_ = updateIngestStats(provider, func(s *providerIngestStats) {
+		reason := rejectOther
+		var re *rejectError
+		if errors.As(err, &re) {
+			reason = re.reason
+		}
+		if s.Rejected == nil {
+			s.Rejected = map[string]int64{}
+		}
+		s.Rejected[reason]++
+		s.LastReject = now
+		s.LastRejectReason = reason
+	})
+}
+
+// quarantineDir holds spool files that repeatedly failed to apply, by
+// provider and kind like the spool.
+func quarantineDir() (string, error) {
+	ad, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ad, "quarantine"), nil
+}
+
+// quarantinedFiles lists the files in the quarantine directory of a
+// provider.
+func quarantinedFiles(provider Provider) []string {
+	qd, err := quarantineDir()
+	if err != nil {
+		return nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(qd, string(provider), "*", "*.json"))
+	return matches
+}
+
+// drainSpoolKind applies a provider's spool files of one kind, oldest first,
+// and counts the applied ones as received in a single stats update. A file
+// that fails to apply stays for the next drain and is moved to the
+// quarantine directory after spoolMaxAttempts failures.
+func drainSpoolKind(provider Provider, kind string, apply func([]byte) error) error {
+	files, err := listSpoolFiles(provider, kind)
+	if err != nil {
+		return err
+	}
+	failed := map[string]error{}
+	var applied []string
+	var received int64
+	var lastEvent time.Time
+	for _, p := range files {
+		info, err := os.Stat(p)
+		if err != nil {
+			continue // drained by another aistat
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		if err := apply(b); err != nil {
+			failed[p] = err
+			continue
+		}
+		_ = os.Remove(p)
+		applied = append(applied, p)
+		if !strings.HasPrefix(filepath.Base(p), selftestSessionPrefix) {
+			received++
+			lastEvent = maxTime(lastEvent, info.ModTime().UTC())
+		}
+	}
+
+	if len(failed) == 0 && len(applied) == 0 {
+		return nil
+	}
+	return updateIngestStats(provider, func(s *providerIngestStats) {
+		s.Received += received
+		s.LastEvent = maxTime(s.LastEvent, lastEvent)
+		if s.Failing == nil {
+			s.Failing = map[string]int{}
+		}
+		for _, p := range applied {
+			delete(s.Failing, kind+"/"+filepath.Base(p))
+		}
+		for p, ferr := range failed {
+			key := kind + "/" + filepath.Base(p)
+			s.Failing[key]++
+			s.DrainFailures++
+			s.LastDrainError = key + ": " + ferr.Error()
+			if s.Failing[key] < spoolMaxAttempts {
+				continue
+			}
+			if err := quarantineSpoolFile(provider, kind, p); err == nil {
+				delete(s.Failing, key)
+				s.Quarantined++
+			}
+		}
+		// Forget files another aistat applied or `aistat clean` removed
+		sd, _ := spoolDir()
+		for key := range s.Failing {
+			if strings.HasPrefix(key, kind+"/") {
+				if _, err := os.Stat(filepath.Join(sd, string(provider), key)); err != nil {
+					delete(s.Failing, key)
+				}
+			}
+		}
+		if len(s.Failing) == 0 {
+			s.Failing = nil
+		}
+	})
+}
+
+func quarantineSpoolFile(provider Provider, kind, path string) error {
+	qd, err := quarantineDir()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(qd, string(provider), kind)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	return os.Rename(path, filepath.Join(dir, filepath.Base(path)))
+}
+
+// ingestStatus is the one-line pipeline health shown in the TUI footer;
+// warn is set when a provider has a problem.
+func ingestStatus(now time.Time) (string, bool) {
+	stats, _ := loadIngestStats()
+	var parts []string
+	warn := false
+	for _, p := range []Provider{ProviderClaude, ProviderCodex} {
+		var s providerIngestStats
+		if stats[p] != nil {
+			s = *stats[p]
+		}
+		problem := s.problem()
+		if n := len(quarantinedFiles(p)); n > 0 && problem == "" {
+			problem = fmt.Sprintf("%d quarantined", n)
+		}
+		switch {
+		case problem != "":
+			warn = true
+			parts = append(parts, string(p)+" "+problem)
+		case !s.LastEvent.IsZero():
+			parts = append(parts, string(p)+" "+fmtAgo(now.Sub(s.LastEvent)))
+		}
+	}
+	if len(parts) == 0 {
+		return "no hook events yet", false
+	}
+	return "ingest: " + strings.Join(parts, " · "), warn
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIngestStatsCountsRejections(t *testing.T) {
+	t.Setenv("AISTAT_HOME", t.TempDir())
+
+	_ = ingestClaudeHook(strings.NewReader(`{"hook_event_name":"Stop",`), io.Discard)
+	_ = ingestClaudeHook(strings.NewReader(`{"hook_event_name":"Stop"}`), io.Discard)
+	_ = ingestCodexNotify(nil, []string{`not json`})
+
+	stats, err := loadIngestStats()
+	if err != nil {
+		t.Fatalf("loadIngestStats error: %v", err)
+	}
+	claude := stats[ProviderClaude]
+	if claude == nil || claude.Received != 0 || claude.Rejected[rejectBadJSON] != 1 || claude.Rejected[rejectInvalidID] != 1 {
+		t.Fatalf("claude stats = %+v", claude)
+	}
+	if claude.problem() == "" {
+		t.Fatalf("rejections since the last good event should be a problem")
+	}
+	if codex := stats[ProviderCodex]; codex == nil || codex.Rejected[rejectBadJSON] != 1 {
+		t.Fatalf("codex stats = %+v", codex)
+	}
+
+	if err := ingestClaudeHook(strings.NewReader(`{"hook_event_name":"Stop","session_id":"sess-stats"}`), io.Discard); err != nil {
+		t.Fatalf("ingestClaudeHook error: %v", err)
+	}
+	stats, _ = loadIngestStats()
+	if s := stats[ProviderClaude]; s.Received != 0 {
+		t.Fatalf("a spooled event should be counted by the drain, not the hook: %+v", s)
+	}
+	if err := drainClaudeSpool(); err != nil {
+		t.Fatalf("drain error: %v", err)
+	}
+	stats, _ = loadIngestStats()
+	if s := stats[ProviderClaude]; s.Received != 1 || s.LastEvent.IsZero() || s.problem() != "" {
+		t.Fatalf("after a good event: %+v (problem %q)", s, s.problem())
+	}
+}
+
+func TestIngestStatsSkipSelftestEvents(t *testing.T) {
+	t.Setenv("AISTAT_HOME", t.TempDir())
+	t.Setenv(selftestEnv, "1")
+
+	_ = ingestClaudeHook(strings.NewReader(`{"hook_event_name":"Stop",`), io.Discard)
+	payload := `{"hook_event_name":"Stop","session_id":"` + selftestSessionPrefix + `1"}`
+	if err := ingestClaudeHook(strings.NewReader(payload), io.Discard); err != nil {
+		t.Fatalf("ingestClaudeHook error: %v", err)
+	}
+	if err := drainClaudeSpool(); err != nil {
+		t.Fatalf("drain error: %v", err)
+	}
+	stats, err := loadIngestStats()
+	if err != nil {
+		t.Fatalf("loadIngestStats error: %v", err)
+	}
+	if s := stats[ProviderClaude]; s != nil && (s.Received != 0 || s.rejectedTotal() != 0) {
+		t.Fatalf("selftest events were counted: %+v", s)
+	}
+}
+
+func TestDrainQuarantinesFailingSpoolFiles(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	if err := writeSpoolBytes(ProviderClaude, "hook", "sess-bad", []byte(`{"session_id":`), true); err != nil {
+		t.Fatalf("writeSpoolBytes error: %v", err)
+	}
+	for i := 0; i < spoolMaxAttempts; i++ {
+		if err := drainClaudeSpool(); err != nil {
+			t.Fatalf("drain error: %v", err)
+		}
+		stats, _ := loadIngestStats()
+		if s := stats[ProviderClaude]; i < spoolMaxAttempts-1 && s.Failing["hook/sess-bad.json"] != i+1 {
+			t.Fatalf("attempt %d: failing = %+v", i+1, s.Failing)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "spool", "claude", "hook", "sess-bad.json")); !os.IsNotExist(err) {
+		t.Fatalf("failing file left in the spool")
+	}
+	if q := quarantinedFiles(ProviderClaude); len(q) != 1 {
+		t.Fatalf("quarantined = %v", q)
+	}
+	stats, _ := loadIngestStats()
+	s := stats[ProviderClaude]
+	if s.Quarantined != 1 || s.DrainFailures != spoolMaxAttempts || len(s.Failing) != 0 {
+		t.Fatalf("stats = %+v", s)
+	}
+	if c := quarantineCheck(); c.Severity != severityWarn {
+		t.Fatalf("quarantine check = %+v", c)
+	}
+	if _, warn := ingestStatus(s.LastEvent); !warn {
+		t.Fatalf("footer status should warn about the quarantined file")
+	}
+
+	if n, err := cleanSpoolData(false); err != nil || n != 1 {
+		t.Fatalf("cleanSpoolData = %d, %v", n, err)
+	}
+	if q := quarantinedFiles(ProviderClaude); len(q) != 0 {
+		t.Fatalf("clean left quarantined files: %v", q)
+	}
+}
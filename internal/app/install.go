@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
@@ -28,14 +27,23 @@ func newInstallCmd() *cobra.Command {
 		noWizard    bool
 		usePath     bool
 		cmdOverride string
+		targets     installTargetFlags
 	)
 
 	cmd := &cobra.Command{
 		Use:   "install",
 		Short: "Install Claude Code hooks/statusline + Codex notify to feed aistat",
 		Long: `This will:
-- Update ~/.claude/settings.json to call aistat from hooks + statusLine
-- Update ~/.codex/config.toml to set notify = ["<cmd>", "ingest", "codex-notify"]
+- Update ~/.claude/settings.json to call aistat from hooks + statusLine; an
+  existing statusLine keeps showing, chained behind aistat's (unless --force)
+- Update ~/.codex/config.toml to point notify (and any profile's notify) at an
+  aistat wrapper; an existing notify program keeps running, chained behind it
+
+--scope project|local wires a project's .claude/settings.json or
+.claude/settings.local.json instead; --claude-dir and --codex-home pick other
+config directories (e.g. separate work and personal setups) and
+--codex-profile wires only the named Codex profiles. Every wired config is
+recorded so uninstall, doctor and doctor --fix know about it.
 
 Backups are created with a timestamp suffix.
 
@@ -65,7 +73,8 @@ By default, when run in a TTY, this command starts an interactive setup wizard.`
 				cmd.Flags().Changed("skip-claude") ||
 				cmd.Flags().Changed("skip-codex") ||
 				cmd.Flags().Changed("use-path") ||
-				cmd.Flags().Changed("cmd")
+				cmd.Flags().Changed("cmd") ||
+				targets.explicit(cmd)
 
 			if interactive && !noWizard && !flagsExplicit {
 				choices, err := runInstallWizard(exe)
@@ -88,15 +97,27 @@ By default, when run in a TTY, this command starts an interactive setup wizard.`
 				return nil
 			}
 
+			claudeTargets, err := targets.claudeTargets()
+			if err != nil {
+				return err
+			}
+			codexTargets, err := targets.codexTargets()
+			if err != nil {
+				return err
+			}
 			var errs []string
 			if !skipClaude {
-				if err := installClaude(callCmd, force, dryRun); err != nil {
-					errs = append(errs, "Claude: "+err.Error())
+				for _, t := range claudeTargets {
+					if err := installClaude(t, callCmd, force, dryRun); err != nil {
+						errs = append(errs, "Claude: "+err.Error())
+					}
 				}
 			}
 			if !skipCodex {
-				if err := installCodex(callCmd, force, dryRun); err != nil {
-					errs = append(errs, "Codex: "+err.Error())
+				for _, t := range codexTargets {
+					if err := installCodex(t, callCmd, dryRun); err != nil {
+						errs = append(errs, "Codex: "+err.Error())
+					}
 				}
 			}
 			if len(errs) > 0 {
@@ -111,13 +132,14 @@ By default, when run in a TTY, this command starts an interactive setup wizard.`
 		},
 	}
 
-	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing statusLine/notify if present")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing Claude statusLine instead of chaining it")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show changes without writing files")
 	cmd.Flags().BoolVar(&skipClaude, "skip-claude", false, "Skip Claude Code setup")
 	cmd.Flags().BoolVar(&skipCodex, "skip-codex", false, "Skip Codex setup")
 	cmd.Flags().BoolVar(&noWizard, "no-wizard", false, "Disable interactive setup wizard")
 	cmd.Flags().BoolVar(&usePath, "use-path", false, "Use 'aistat' instead of an absolute path in configs (requires PATH)")
 	cmd.Flags().StringVar(&cmdOverride, "cmd", "", "Override the command/path written into configs (e.g. /usr/local/bin/aistat)")
+	targets.register(cmd)
 	return cmd
 }
 
@@ -180,8 +202,8 @@ func runInstallWizard(exeAbs string) (installWizardChoices, error) {
 				}),
 
 			huh.NewConfirm().
-				Title("Overwrite existing entries?").
-				Description("Replaces existing statusLine/notify/hook commands if present.").
+				Title("Replace an existing statusLine?").
+				Description("By default a statusLine you already have keeps showing; aistat chains it. Codex notify programs are always chained.").
 				Value(&force),
 
 			huh.NewConfirm().
@@ -256,6 +278,9 @@ func shellEscape(s string) string {
 	return s
 }
 
+// backupTimeLayout is the timestamp suffix of config backups (<path>.bak.<ts>)
+const backupTimeLayout = "20060102T150405Z"
+
 func writeWrapper(path string, content string, dryRun bool) error {
 	if dryRun {
 		fmt.Printf("Would write %s:\n%s\n", path, content)
@@ -270,11 +295,22 @@ func writeWrapper(path string, content string, dryRun bool) error {
 	return nil
 }
 
-func installClaude(exe string, force bool, dryRun bool) error {
-	home, err := os.UserHomeDir()
-	if err != nil {
+// backupConfig copies a config to <path>.bak.<timestamp> before it is edited.
+func backupConfig(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	backup := path + ".bak." + time.Now().UTC().Format(backupTimeLayout)
+	if err := copyFile(path, backup); err != nil {
 		return err
 	}
+	fmt.Printf("Backed up %s -> %s\n", path, backup)
+	return nil
+}
+
+// installClaude wires hooks and the statusLine into one Claude settings file.
+// A statusLine the user already had is chained unless force is set.
+func installClaude(t claudeTarget, exe string, force bool, dryRun bool) error {
 	ad, err := appDir()
 	if err != nil {
 		return err
@@ -298,15 +334,21 @@ run_detached() {
   fi
   "$@"
 }
+if [ "$1" = "--sync" ]; then
+  # PreToolUse/PermissionRequest run in the foreground so decisions reach Claude on stdout.
+  exec 2>/dev/null
+  exec %s ingest claude-hook
+fi
 exec 1>/dev/null 2>/dev/null
 run_detached %s ingest claude-hook
-`, shellEscape(exe))
+`, shellEscape(exe), shellEscape(exe))
 	statusScript := fmt.Sprintf(`#!/bin/sh
 # Generated by aistat. Safe Claude statusline wrapper.
 if [ -t 0 ]; then
   printf "\n"
   exit 0
 fi
+export AISTAT_ORIG_TERM="${TERM-}" AISTAT_ORIG_NO_COLOR="${NO_COLOR-}"
 export TERM=dumb
 export NO_COLOR=1
 run_detached() {
@@ -321,7 +363,7 @@ run_detached() {
   "$@"
 }
 exec 2>/dev/null
-run_detached %s statusline
+run_detached %s statusline "$@"
 `, shellEscape(exe))
 	if err := writeWrapper(hookWrapper, hookScript, dryRun); err != nil {
 		return err
@@ -329,7 +371,7 @@ run_detached %s statusline
 	if err := writeWrapper(statusWrapper, statusScript, dryRun); err != nil {
 		return err
 	}
-	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	settingsPath := t.Path
 	if err := os.MkdirAll(filepath.Dir(settingsPath), 0o700); err != nil {
 		return err
 	}
@@ -349,10 +391,11 @@ run_detached %s statusline
 	}
 
 	hookCmd := shellEscape(hookWrapper)
+	syncHookCmd := hookCmd + " --sync"
 
-	ensureHook := func(event string, matcher any) {
+	ensureHook := func(event string, matcher any, command string) {
 		arr, _ := hooks[event].([]any)
-		// de-dupe
+		// de-dupe (and upgrade entries written for the other wrapper mode)
 		for _, item := range arr {
 			mm, _ := item.(map[string]any)
 			if mm == nil {
@@ -364,7 +407,8 @@ run_detached %s statusline
 				if hm == nil {
 					continue
 				}
-				if asString(hm["command"]) == hookCmd {
+				if c := asString(hm["command"]); c == hookCmd || c == syncHookCmd {
+					hm["command"] = command
 					return
 				}
 			}
@@ -374,7 +418,7 @@ run_detached %s statusline
 			"hooks": []any{
 				map[string]any{
 					"type":    "command",
-					"command": hookCmd,
+					"command": command,
 				},
 			},
 		}
@@ -383,23 +427,70 @@ run_detached %s statusline
 	}
 
 	// Core events
-	ensureHook("SessionStart", "*")
-	ensureHook("SessionEnd", "*")
-	ensureHook("UserPromptSubmit", "*")
-	ensureHook("PreToolUse", "*")
-	ensureHook("PostToolUse", "*")
-	ensureHook("Stop", "*")
+	ensureHook("SessionStart", "*", hookCmd)
+	ensureHook("SessionEnd", "*", hookCmd)
+	ensureHook("UserPromptSubmit", "*", hookCmd)
+	// Synchronous only when policy rules can answer allow/deny/ask; otherwise
+	// tool calls should not wait for aistat.
+	preToolCmd := hookCmd
+	if len(loadConfig().PolicyRules) > 0 {
+		preToolCmd = syncHookCmd
+	}
+	ensureHook("PreToolUse", "*", preToolCmd)
+	// Synchronous so permission prompts can be answered from the TUI.
+	ensureHook("PermissionRequest", "*", syncHookCmd)
+	ensureHook("PostToolUse", "*", hookCmd)
+	ensureHook("Stop", "*", hookCmd)
 	// Status transitions
-	ensureHook("Notification", "permission_prompt")
-	ensureHook("Notification", "idle_prompt")
+	ensureHook("Notification", "permission_prompt", hookCmd)
+	ensureHook("Notification", "idle_prompt", hookCmd)
 
 	settings["hooks"] = hooks
 
-	// statusLine setup
-	if _, exists := settings["statusLine"]; !exists || force {
+	// statusLine setup: a statusLine of the user's own is chained (aistat's
+	// wrapper runs it and shows its output) unless --force replaces it. Other
+	// than the user settings, the wrapper is told which file's chain to run.
+	statusCmd := shellEscape(statusWrapper)
+	if settingsPath != userClaudeSettingsPath() {
+		statusCmd += " " + shellEscape(settingsPath)
+	}
+	chain, err := loadClaudeStatuslineChain(settingsPath)
+	if err != nil {
+		return err
+	}
+	prev, _ := settings["statusLine"].(map[string]any)
+	prevCmd := strings.TrimSpace(asString(prev["command"]))
+	switch {
+	case isAistatStatusline(prevCmd):
+		// aistat's wrapper (or an older form of its command): keep what it
+		// chains, also under --force, so uninstall can still restore it
+		if prevCmd != statusCmd {
+			next := map[string]any{}
+			for k, v := range prev {
+				next[k] = v
+			}
+			next["type"] = "command"
+			next["command"] = statusCmd
+			settings["statusLine"] = next
+		}
+	case prevCmd != "" && !force:
+		raw, _ := json.Marshal(prev)
+		chain = claudeStatuslineChain{Command: prevCmd, StatusLine: raw}
+		// Keep the user's other statusLine settings (padding)
+		next := map[string]any{}
+		for k, v := range prev {
+			next[k] = v
+		}
+		next["type"] = "command"
+		next["command"] = statusCmd
+		settings["statusLine"] = next
+		fmt.Printf("Claude statusLine: chaining existing %s\n", prevCmd)
+	default:
+		// No statusLine, or --force replacing the user's own
+		chain = claudeStatuslineChain{}
 		settings["statusLine"] = map[string]any{
 			"type":    "command",
-			"command": shellEscape(statusWrapper),
+			"command": statusCmd,
 			"padding": 0,
 		}
 	}
@@ -414,121 +505,126 @@ run_detached %s statusline
 		return nil
 	}
 
-	if _, err := os.Stat(settingsPath); err == nil {
-		backup := settingsPath + ".bak." + time.Now().UTC().Format("20060102T150405Z")
-		if err := copyFile(settingsPath, backup); err != nil {
-			return err
-		}
-		fmt.Printf("Backed up %s -> %s\n", settingsPath, backup)
+	if err := backupConfig(settingsPath); err != nil {
+		return err
 	}
 
+	// Record the chain first so a failed write never loses the user's command
+	if err := saveClaudeStatuslineChain(settingsPath, chain); err != nil {
+		return err
+	}
 	if err := os.WriteFile(settingsPath, b, 0o600); err != nil {
 		return err
 	}
 	fmt.Printf("Updated %s\n", settingsPath)
-	return nil
+	return recordInstallTarget(installTarget{Provider: ProviderClaude, Scope: t.Scope, Path: settingsPath})
 }
 
-func installCodex(exe string, force bool, dryRun bool) error {
+// installCodex points notify of a config.toml (top level and each profile
+// that sets its own, or just the profiles asked for) at aistat's wrapper. A
+// notify program the user already had is kept: the wrapper passes each
+// payload on to it as well.
+func installCodex(t codexTarget, exe string, dryRun bool) error {
 	ad, err := appDir()
 	if err != nil {
 		return err
 	}
 	wrapperDir := filepath.Join(ad, "bin")
-	notifyWrapper := filepath.Join(wrapperDir, "aistat-codex-notify")
-	notifyScript := fmt.Sprintf(`#!/bin/sh
-# Generated by aistat. Safe Codex notify wrapper.
-[ -t 0 ] && exit 0
-export TERM=dumb
-export NO_COLOR=1
-run_detached() {
-  if command -v setsid >/dev/null 2>&1; then
-    setsid "$@"
-    return
-  fi
-  if command -v perl >/dev/null 2>&1; then
-    perl -MPOSIX -e 'POSIX::setsid(); exec @ARGV' -- "$@"
-    return
-  fi
-  "$@"
-}
-tmp="${TMPDIR:-/tmp}/aistat-codex-notify.$$"
-if ! cat >"$tmp"; then
-  rm -f "$tmp"
-  exit 0
-fi
-(run_detached %s ingest codex-notify <"$tmp" >/dev/null 2>&1; rm -f "$tmp") >/dev/null 2>&1 &
-exit 0
-`, shellEscape(exe))
-	if err := writeWrapper(notifyWrapper, notifyScript, dryRun); err != nil {
-		return err
-	}
-	cfgPath := codexConfigPath()
-	if cfgPath == "" {
-		return fmt.Errorf("failed to resolve Codex config path")
-	}
+	cfgPath := t.Path
 	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
 		return err
 	}
 
-	notifyLine := fmt.Sprintf(`notify = [%q]`, notifyWrapper)
-
 	var content string
 	if b, err := os.ReadFile(cfgPath); err == nil {
 		content = string(b)
 	} else {
 		content = ""
 	}
+	current, err := parseCodexNotify(content)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", cfgPath, err)
+	}
+	chain, err := loadCodexNotifyChain(cfgPath)
+	if err != nil {
+		return err
+	}
+	profiles := current.targets()
+	if len(t.Profiles) > 0 {
+		profiles = t.Profiles
+	}
 
-	state := classifyCodexNotify(content, exe, notifyWrapper)
-	if strings.Contains(content, "notify =") {
-		if !force {
-			switch state {
-			case codexNotifyWrapper:
-				fmt.Printf("Codex notify already configured in %s\n", cfgPath)
-				return nil
-			case codexNotifyUnsafe:
-				// Auto-upgrade legacy/unsafe notify entries without forcing.
-			default:
-				return fmt.Errorf("notify already set in %s (use --force to overwrite)", cfgPath)
-			}
+	updated := content
+	for _, profile := range profiles {
+		wrapper := filepath.Join(wrapperDir, codexNotifyWrapperName(cfgPath, profile))
+		argv := current.notify(profile)
+		label := "Codex notify"
+		if profile != "" {
+			label += " (profile " + profile + ")"
 		}
-		re := regexp.MustCompile(`(?m)^notify\s*=.*$`)
-		content = re.ReplaceAllString(content, notifyLine)
-	} else {
-		lines := strings.Split(content, "\n")
-		insertAt := len(lines)
-		for i, line := range lines {
-			if strings.HasPrefix(strings.TrimSpace(line), "[") {
-				insertAt = i
-				break
-			}
+		switch classifyNotifyArgv(argv, exe, wrapper) {
+		case codexNotifyWrapper:
+			// Keep whatever it already chains
+		case codexNotifyOther:
+			chain[profile] = argv
+			fmt.Printf("%s: chaining existing %s\n", label, strings.Join(argv, " "))
+		case codexNotifyUnsafe:
+			// Another aistat wrapper or a direct aistat call: aistat already
+			// sits in front of whatever is recorded, keep it
+		default:
+			// Not set: nothing to chain
+			delete(chain, profile)
+		}
+		if err := writeWrapper(wrapper, codexNotifyScript(exe, chain[profile]), dryRun); err != nil {
+			return err
+		}
+		if updated, err = setCodexNotify(updated, profile, []string{wrapper}); err != nil {
+			return fmt.Errorf("%s: %w", cfgPath, err)
+		}
+	}
+	for profile := range chain {
+		if profile != "" && current.Profiles[profile] == nil {
+			delete(chain, profile) // the profile is gone
 		}
-		var out []string
-		out = append(out, lines[:insertAt]...)
-		out = append(out, notifyLine)
-		out = append(out, lines[insertAt:]...)
-		content = strings.Join(out, "\n")
+	}
+
+	if updated == content {
+		fmt.Printf("Codex notify already configured in %s\n", cfgPath)
+		if dryRun {
+			return nil
+		}
+		if err := saveCodexNotifyChain(cfgPath, chain); err != nil {
+			return err
+		}
+		return recordInstallTarget(codexInstallTarget(t))
 	}
 
 	if dryRun {
-		fmt.Printf("Would write %s:\n%s\n", cfgPath, content)
+		fmt.Printf("Would write %s:\n%s\n", cfgPath, updated)
 		return nil
 	}
 
-	if _, err := os.Stat(cfgPath); err == nil {
-		backup := cfgPath + ".bak." + time.Now().UTC().Format("20060102T150405Z")
-		if err := copyFile(cfgPath, backup); err != nil {
-			return err
-		}
-		fmt.Printf("Backed up %s -> %s\n", cfgPath, backup)
+	if err := backupConfig(cfgPath); err != nil {
+		return err
 	}
 
-	if err := os.WriteFile(cfgPath, []byte(content), 0o600); err != nil {
+	// Record the chain first so a failed write never loses the user's command
+	if err := saveCodexNotifyChain(cfgPath, chain); err != nil {
+		return err
+	}
+	if err := os.WriteFile(cfgPath, []byte(updated), 0o600); err != nil {
 		return err
 	}
 	fmt.Printf("Updated %s\n", cfgPath)
-	return nil
+	return recordInstallTarget(codexInstallTarget(t))
+}
+
+func codexInstallTarget(t codexTarget) installTarget {
+	scope := "home"
+	if len(t.Profiles) > 0 {
+		scope = "profiles"
+	}
+	return installTarget{Provider: ProviderCodex, Scope: scope, Path: t.Path, Profiles: t.Profiles}
 }
 
 type codexNotifyState int
@@ -539,29 +635,3 @@ const (
 	codexNotifyUnsafe
 	codexNotifyOther
 )
-
-func classifyCodexNotify(content, exe, wrapper string) codexNotifyState {
-	lines := findCodexNotifyLines(content)
-	if len(lines) == 0 {
-		return codexNotifyMissing
-	}
-	for _, line := range lines {
-		if strings.Contains(line, wrapper) {
-			return codexNotifyWrapper
-		}
-	}
-	for _, line := range lines {
-		if strings.Contains(line, "codex-notify") && (strings.Contains(line, "aistat") || strings.Contains(line, exe)) {
-			return codexNotifyUnsafe
-		}
-	}
-	return codexNotifyOther
-}
-
-func findCodexNotifyLines(content string) []string {
-	if !strings.Contains(content, "notify") {
-		return nil
-	}
-	re := regexp.MustCompile(`(?m)^notify\s*=.*$`)
-	return re.FindAllString(content, -1)
-}
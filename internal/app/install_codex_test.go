@@ -0,0 +1,165 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInstallCodexChainsExistingNotify(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+	t.Setenv("CODEX_HOME", filepath.Join(root, "codex"))
+
+	// The user's own notify program records the payload it was given
+	got := filepath.Join(root, "payload.txt")
+	sound := filepath.Join(root, "sound.sh")
+	if err := os.WriteFile(sound, []byte("#!/bin/sh\nprintf '%s %s' \"$1\" \"$2\" >"+shellQuote(got)+"\n"), 0o700); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+
+	cfg := `# my codex config
+model = "o3"
+notify = ["` + sound + `", "top"]
+
+[profiles.work]
+model = "gpt-5"
+notify = [
+  "` + sound + `",  # comment
+  "work",
+]
+
+[profiles.plain]
+model = "o4-mini"
+`
+	cfgPath := codexConfigPath()
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	for i := 0; i < 2; i++ { // a second install keeps the chain
+		if err := installCodex(codexTarget{Path: cfgPath}, "/bin/true", false); err != nil {
+			t.Fatalf("installCodex error: %v", err)
+		}
+	}
+
+	b, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !strings.HasPrefix(string(b), "# my codex config\n") || !strings.Contains(string(b), `model = "o4-mini"`) {
+		t.Fatalf("config was not preserved:\n%s", b)
+	}
+	settings, err := parseCodexNotify(string(b))
+	if err != nil {
+		t.Fatalf("parseCodexNotify error: %v", err)
+	}
+	ad, _ := appDir()
+	topWrapper := filepath.Join(ad, "bin", codexNotifyWrapperName(cfgPath, ""))
+	workWrapper := filepath.Join(ad, "bin", codexNotifyWrapperName(cfgPath, "work"))
+	if !slices.Equal(settings.Top, []string{topWrapper}) || !slices.Equal(settings.Profiles["work"], []string{workWrapper}) {
+		t.Fatalf("unexpected notify settings: %+v", settings)
+	}
+	if _, ok := settings.Profiles["plain"]; ok {
+		t.Fatalf("profile without notify should be left alone: %+v", settings)
+	}
+
+	chain, err := loadCodexNotifyChain(cfgPath)
+	if err != nil {
+		t.Fatalf("loadCodexNotifyChain error: %v", err)
+	}
+	if !slices.Equal(chain[""], []string{sound, "top"}) || !slices.Equal(chain["work"], []string{sound, "work"}) {
+		t.Fatalf("unexpected chain: %+v", chain)
+	}
+
+	// The wrapper fans the payload out to the original program
+	if out, err := exec.Command("sh", workWrapper, `{"type":"agent-turn-complete"}`).CombinedOutput(); err != nil {
+		t.Fatalf("wrapper failed: %v %s", err, out)
+	}
+	payload, err := os.ReadFile(got)
+	if err != nil {
+		t.Fatalf("chained program did not run: %v", err)
+	}
+	if string(payload) != `work {"type":"agent-turn-complete"}` {
+		t.Fatalf("unexpected chained payload: %q", payload)
+	}
+}
+
+func TestCodexNotifyWrapperSkipsChainInSelftest(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+	t.Setenv("CODEX_HOME", filepath.Join(root, "codex"))
+
+	marker := filepath.Join(root, "ran")
+	cfgPath := codexConfigPath()
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(cfgPath, []byte(`notify = ["touch", "`+marker+`"]`+"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := installCodex(codexTarget{Path: cfgPath}, "/bin/true", false); err != nil {
+		t.Fatalf("installCodex error: %v", err)
+	}
+	wrapper := filepath.Join(root, "aistat", "bin", codexNotifyWrapperName(cfgPath, ""))
+
+	cmd := exec.Command("sh", wrapper, `{"type":"agent-turn-complete"}`)
+	cmd.Env = append(os.Environ(), selftestEnv+"=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("wrapper failed: %v %s", err, out)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("chained program ran during a self-test")
+	}
+}
+
+func TestCodexNotifyWrapperNameIgnoresCodexHome(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+	t.Setenv("CODEX_HOME", filepath.Join(root, "codex"))
+
+	// Installed as another Codex home (--codex-home) ...
+	other := filepath.Join(root, "other", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(other), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(other, []byte(`notify = ["ding"]`+"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := installCodex(codexTarget{Path: other}, "/bin/true", false); err != nil {
+		t.Fatalf("installCodex error: %v", err)
+	}
+	name := codexNotifyWrapperName(other, "")
+
+	// ... and fixed later with CODEX_HOME pointing at it
+	t.Setenv("CODEX_HOME", filepath.Dir(other))
+	if got := codexNotifyWrapperName(codexConfigPath(), ""); got != name {
+		t.Fatalf("wrapper name changed with CODEX_HOME: %s vs %s", got, name)
+	}
+	if err := installCodex(codexTarget{Path: codexConfigPath()}, "/bin/true", false); err != nil {
+		t.Fatalf("installCodex error: %v", err)
+	}
+	chain, err := loadCodexNotifyChain(other)
+	if err != nil || !slices.Equal(chain[""], []string{"ding"}) {
+		t.Fatalf("chain = %+v, %v; want ding kept", chain, err)
+	}
+
+	// A wrapper under another name never drops the recorded chain
+	b, _ := os.ReadFile(other)
+	stale := strings.Replace(string(b), name, "aistat-codex-notify-old", 1)
+	if err := os.WriteFile(other, []byte(stale), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := installCodex(codexTarget{Path: other}, "/bin/true", false); err != nil {
+		t.Fatalf("installCodex error: %v", err)
+	}
+	if chain, _ := loadCodexNotifyChain(other); !slices.Equal(chain[""], []string{"ding"}) {
+		t.Fatalf("chain = %+v after reinstall over another wrapper; want ding kept", chain)
+	}
+}
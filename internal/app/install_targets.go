@@ -0,0 +1,288 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// -------------------------
+// Install targets (scopes)
+// -------------------------
+
+// Claude settings scopes aistat can be wired into
+const (
+	claudeScopeUser    = "user"    // <config dir>/settings.json
+	claudeScopeProject = "project" // <project>/.claude/settings.json (shared)
+	claudeScopeLocal   = "local"   // <project>/.claude/settings.local.json (personal)
+)
+
+// claudeConfigDir is Claude Code's user config directory: CLAUDE_CONFIG_DIR,
+// else ~/.claude.
+func claudeConfigDir() string {
+	if v := strings.TrimSpace(os.Getenv("CLAUDE_CONFIG_DIR")); v != "" {
+		return v
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || strings.TrimSpace(home) == "" {
+		return ""
+	}
+	return filepath.Join(home, ".claude")
+}
+
+func userClaudeSettingsPath() string {
+	dir := claudeConfigDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "settings.json")
+}
+
+// claudeTarget is a Claude settings file aistat wires hooks + statusLine into
+type claudeTarget struct {
+	Scope string
+	Path  string
+}
+
+// codexTarget is a Codex config.toml aistat wires notify into. Profiles nil
+// means the top level plus every profile that sets its own notify.
+type codexTarget struct {
+	Path     string
+	Profiles []string
+}
+
+// installTarget is a config aistat was installed into, as recorded in
+// install_targets.json.
+type installTarget struct {
+	Provider Provider `json:"provider"`
+	Scope    string   `json:"scope"`
+	Path     string   `json:"path"`
+	Profiles []string `json:"profiles,omitempty"` // Codex profiles asked for
+}
+
+func installTargetsPath() (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "install_targets.json"), nil
+}
+
+// loadInstallTargets returns the recorded install targets, by provider then
+// path.
+func loadInstallTargets() ([]installTarget, error) {
+	p, err := installTargetsPath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var targets []installTarget
+	if err := json.Unmarshal(b, &targets); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+	return targets, nil
+}
+
+func saveInstallTargets(targets []installTarget) error {
+	p, err := installTargetsPath()
+	if err != nil {
+		return err
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Provider != targets[j].Provider {
+			return targets[i].Provider < targets[j].Provider
+		}
+		return targets[i].Path < targets[j].Path
+	})
+	if len(targets) == 0 {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return writeJSONAtomic(p, targets)
+}
+
+// recordInstallTarget adds (or updates) a target after a successful install.
+func recordInstallTarget(t installTarget) error {
+	targets, err := loadInstallTargets()
+	if err != nil {
+		return err
+	}
+	targets = slices.DeleteFunc(targets, func(o installTarget) bool {
+		return o.Provider == t.Provider && o.Path == t.Path
+	})
+	return saveInstallTargets(append(targets, t))
+}
+
+// forgetInstallTarget drops a target after uninstall.
+func forgetInstallTarget(provider Provider, path string) error {
+	targets, err := loadInstallTargets()
+	if err != nil {
+		return err
+	}
+	targets = slices.DeleteFunc(targets, func(o installTarget) bool {
+		return o.Provider == provider && o.Path == path
+	})
+	return saveInstallTargets(targets)
+}
+
+// forgetCodexProfiles drops profiles from a recorded Codex target after they
+// were uninstalled, and the target once none of its profiles are left.
+func forgetCodexProfiles(path string, profiles []string) error {
+	targets, err := loadInstallTargets()
+	if err != nil {
+		return err
+	}
+	for i := range targets {
+		t := &targets[i]
+		if t.Provider != ProviderCodex || t.Path != path || len(t.Profiles) == 0 {
+			continue
+		}
+		t.Profiles = slices.DeleteFunc(t.Profiles, func(p string) bool { return slices.Contains(profiles, p) })
+		if len(t.Profiles) == 0 {
+			return forgetInstallTarget(ProviderCodex, path)
+		}
+	}
+	return saveInstallTargets(targets)
+}
+
+// installTargetFlags selects where install, uninstall and doctor --fix wire
+// aistat.
+type installTargetFlags struct {
+	scope         string
+	project       string
+	claudeDirs    []string
+	codexHomes    []string
+	codexProfiles []string
+}
+
+func (f *installTargetFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.scope, "scope", claudeScopeUser, "Claude settings to wire: user, project (.claude/settings.json) or local (.claude/settings.local.json)")
+	cmd.Flags().StringVar(&f.project, "project", "", "Project directory for --scope project|local (default: current directory)")
+	cmd.Flags().StringArrayVar(&f.claudeDirs, "claude-dir", nil, "Claude config directory for --scope user (repeatable; default: CLAUDE_CONFIG_DIR or ~/.claude)")
+	cmd.Flags().StringArrayVar(&f.codexHomes, "codex-home", nil, "Codex home directory (repeatable; default: CODEX_HOME or ~/.codex)")
+	cmd.Flags().StringArrayVar(&f.codexProfiles, "codex-profile", nil, "Wire only these Codex profiles (repeatable; default: top level and profiles with their own notify)")
+}
+
+// explicit reports whether any target flag was given.
+func (f *installTargetFlags) explicit(cmd *cobra.Command) bool {
+	for _, name := range []string{"scope", "project", "claude-dir", "codex-home", "codex-profile"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *installTargetFlags) claudeTargets() ([]claudeTarget, error) {
+	switch f.scope {
+	case claudeScopeUser, "":
+		if len(f.claudeDirs) == 0 {
+			p := userClaudeSettingsPath()
+			if p == "" {
+				return nil, errors.New("failed to resolve Claude config directory")
+			}
+			return []claudeTarget{{Scope: claudeScopeUser, Path: p}}, nil
+		}
+		var out []claudeTarget
+		for _, d := range f.claudeDirs {
+			if strings.TrimSpace(d) == "" {
+				return nil, errors.New("failed to resolve Claude config directory")
+			}
+			abs, err := filepath.Abs(d)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, claudeTarget{Scope: claudeScopeUser, Path: filepath.Join(abs, "settings.json")})
+		}
+		return out, nil
+	case claudeScopeProject, claudeScopeLocal:
+		if len(f.claudeDirs) > 0 {
+			return nil, errors.New("--claude-dir only applies to --scope user")
+		}
+		dir := f.project
+		if strings.TrimSpace(dir) == "" {
+			dir = "."
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		name := "settings.json"
+		if f.scope == claudeScopeLocal {
+			name = "settings.local.json"
+		}
+		return []claudeTarget{{Scope: f.scope, Path: filepath.Join(abs, ".claude", name)}}, nil
+	default:
+		return nil, fmt.Errorf("unknown --scope %q (want user, project or local)", f.scope)
+	}
+}
+
+func (f *installTargetFlags) codexTargets() ([]codexTarget, error) {
+	if len(f.codexHomes) == 0 {
+		p := codexConfigPath()
+		if p == "" {
+			return nil, errors.New("failed to resolve Codex config path")
+		}
+		return []codexTarget{{Path: p, Profiles: f.codexProfiles}}, nil
+	}
+	var out []codexTarget
+	for _, h := range f.codexHomes {
+		if strings.TrimSpace(h) == "" {
+			return nil, errors.New("failed to resolve Codex home directory")
+		}
+		abs, err := filepath.Abs(h)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, codexTarget{Path: filepath.Join(abs, "config.toml"), Profiles: f.codexProfiles})
+	}
+	return out, nil
+}
+
+// recordedTargets returns the recorded targets as install targets, falling
+// back to the defaults when nothing is recorded (installs made before targets
+// were recorded).
+func recordedTargets() ([]claudeTarget, []codexTarget, error) {
+	targets, err := loadInstallTargets()
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		claude []claudeTarget
+		codex  []codexTarget
+	)
+	for _, t := range targets {
+		switch t.Provider {
+		case ProviderClaude:
+			claude = append(claude, claudeTarget{Scope: t.Scope, Path: t.Path})
+		case ProviderCodex:
+			codex = append(codex, codexTarget{Path: t.Path, Profiles: t.Profiles})
+		}
+	}
+	if len(claude) == 0 {
+		if p := userClaudeSettingsPath(); p != "" {
+			claude = []claudeTarget{{Scope: claudeScopeUser, Path: p}}
+		}
+	}
+	if len(codex) == 0 {
+		if p := codexConfigPath(); p != "" {
+			codex = []codexTarget{{Path: p}}
+		}
+	}
+	return claude, codex, nil
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInstallScopedTargets(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+	t.Setenv("CODEX_HOME", filepath.Join(root, "codex"))
+
+	project := filepath.Join(root, "repo")
+	flags := installTargetFlags{scope: claudeScopeLocal, project: project}
+	claudeTargets, err := flags.claudeTargets()
+	if err != nil {
+		t.Fatalf("claudeTargets error: %v", err)
+	}
+	local := claudeTargets[0]
+	if local.Path != filepath.Join(project, ".claude", "settings.local.json") {
+		t.Fatalf("local settings path = %s", local.Path)
+	}
+	if err := os.MkdirAll(filepath.Dir(local.Path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(local.Path, []byte(`{"statusLine":{"type":"command","command":"echo repo"}}`), 0o600); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+	user := claudeTarget{Scope: claudeScopeUser, Path: userClaudeSettingsPath()}
+
+	// A second Codex home, wired for one profile only
+	workHome := filepath.Join(root, "codex-work")
+	flags = installTargetFlags{codexHomes: []string{workHome}, codexProfiles: []string{"work"}}
+	codexTargets, err := flags.codexTargets()
+	if err != nil {
+		t.Fatalf("codexTargets error: %v", err)
+	}
+	work := codexTargets[0]
+	if err := os.MkdirAll(workHome, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(work.Path, []byte("notify = [\"ding\"]\n\n[profiles.work]\nmodel = \"o3\"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	for _, ct := range []claudeTarget{local, user} {
+		if err := installClaude(ct, "/bin/true", false, false); err != nil {
+			t.Fatalf("installClaude(%s) error: %v", ct.Scope, err)
+		}
+	}
+	if err := installCodex(work, "/bin/true", false); err != nil {
+		t.Fatalf("installCodex error: %v", err)
+	}
+
+	// The local settings' statusLine names its file, so its own chain runs
+	b, _ := os.ReadFile(local.Path)
+	var settings struct {
+		StatusLine map[string]any `json:"statusLine"`
+	}
+	_ = json.Unmarshal(b, &settings)
+	if cmd := asString(settings.StatusLine["command"]); !strings.HasSuffix(cmd, " "+shellEscape(local.Path)) {
+		t.Fatalf("local statusLine command = %q", cmd)
+	}
+	var out bytes.Buffer
+	runStatusline(strings.NewReader(`{"session_id":"sess-scope"}`), &out, local.Path)
+	if got := strings.TrimSpace(out.String()); got != "repo" {
+		t.Fatalf("local statusline = %q, want %q", got, "repo")
+	}
+
+	b, _ = os.ReadFile(work.Path)
+	current, err := parseCodexNotify(string(b))
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	wrapper := filepath.Join(root, "aistat", "bin", codexNotifyWrapperName(work.Path, "work"))
+	if !slices.Equal(current.Top, []string{"ding"}) || !slices.Equal(current.Profiles["work"], []string{wrapper}) {
+		t.Fatalf("notify = %+v, want top untouched and work -> %s", current, wrapper)
+	}
+	if filepath.Base(wrapper) == codexNotifyWrapperName(codexConfigPath(), "work") {
+		t.Fatalf("wrapper of another Codex home should not collide: %s", wrapper)
+	}
+
+	recorded, err := loadInstallTargets()
+	if err != nil || len(recorded) != 3 {
+		t.Fatalf("recorded targets = %+v, %v", recorded, err)
+	}
+
+	// Uninstalling one Claude scope keeps the wrappers the other uses
+	if err := uninstallClaude(local, false); err != nil {
+		t.Fatalf("uninstallClaude error: %v", err)
+	}
+	hook := filepath.Join(root, "aistat", "bin", "aistat-claude-hook")
+	if _, err := os.Stat(hook); err != nil {
+		t.Fatalf("hook wrapper removed while the user settings still use it")
+	}
+	if err := uninstallClaude(user, false); err != nil {
+		t.Fatalf("uninstallClaude error: %v", err)
+	}
+	if _, err := os.Stat(hook); !os.IsNotExist(err) {
+		t.Fatalf("hook wrapper left behind")
+	}
+
+	if err := uninstallCodex(work, false); err != nil {
+		t.Fatalf("uninstallCodex error: %v", err)
+	}
+	if recorded, _ := loadInstallTargets(); len(recorded) != 0 {
+		t.Fatalf("targets left after uninstall: %+v", recorded)
+	}
+}
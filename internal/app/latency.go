@@ -0,0 +1,371 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+// -------------------------
+// Status transitions
+// -------------------------
+
+// recordTransition appends a status change to the record's history. Repeats of
+// the current status are ignored; out-of-order entries are sorted into place.
+func recordTransition(rec *SessionRecord, status Status, reason string, at time.Time) {
+	if status == "" || status == StatusUnknown || at.IsZero() {
+		return
+	}
+	if n := len(rec.Transitions); n > 0 {
+		last := rec.Transitions[n-1]
+		if last.Status == status && !at.Before(last.At) {
+			return
+		}
+		if at.Before(last.At) {
+			rec.Transitions = normalizeTransitions(append(rec.Transitions, StatusTransition{At: at, Status: status, Reason: reason}))
+			return
+		}
+	}
+	rec.Transitions = append(rec.Transitions, StatusTransition{At: at, Status: status, Reason: reason})
+	if len(rec.Transitions) > maxTransitions {
+		rec.Transitions = rec.Transitions[len(rec.Transitions)-maxTransitions:]
+	}
+}
+
+// normalizeTransitions sorts by time, collapses consecutive repeats of the
+// same status (keeping the earliest) and bounds the history.
+func normalizeTransitions(ts []StatusTransition) []StatusTransition {
+	sort.SliceStable(ts, func(i, j int) bool { return ts[i].At.Before(ts[j].At) })
+	out := ts[:0]
+	for _, t := range ts {
+		if n := len(out); n > 0 && out[n-1].Status == t.Status {
+			continue
+		}
+		out = append(out, t)
+	}
+	if len(out) > maxTransitions {
+		out = out[len(out)-maxTransitions:]
+	}
+	return out
+}
+
+// -------------------------
+// Latency report
+// -------------------------
+
+// idlePeriod is a stretch where the agent was stopped and waiting on a person:
+// from entering waiting/approval until the next running transition.
+type idlePeriod struct {
+	Provider Provider      `json:"provider"`
+	ID       string        `json:"id"`
+	Project  string        `json:"project"`
+	State    Status        `json:"state"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"-"`
+	DurMS    int64         `json:"duration_ms"`
+	Open     bool          `json:"open,omitempty"` // still waiting; End is "now"
+}
+
+type latencyRow struct {
+	Group   string `json:"group"`
+	Count   int    `json:"count"`
+	Open    int    `json:"open"`
+	TotalMS int64  `json:"total_ms"`
+	P50MS   int64  `json:"p50_ms"`
+	P90MS   int64  `json:"p90_ms"`
+	P95MS   int64  `json:"p95_ms"`
+	MaxMS   int64  `json:"max_ms"`
+}
+
+type latencyReport struct {
+	Since      time.Time    `json:"since"`
+	Overall    latencyRow   `json:"overall"`
+	ByProject  []latencyRow `json:"by_project,omitempty"`
+	ByProvider []latencyRow `json:"by_provider,omitempty"`
+	ByHour     []latencyRow `json:"by_hour,omitempty"`
+	ByState    []latencyRow `json:"by_state,omitempty"`
+	Worst      []idlePeriod `json:"worst"`
+}
+
+var latencyBreakdowns = []string{"project", "provider", "hour", "state"}
+
+func newLatencyCmd() *cobra.Command {
+	var (
+		flagJSON     bool
+		flagProvider string
+		flagProjects []string
+		flagSince    string
+		flagBy       string
+		flagTop      int
+		flagRedact   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "latency",
+		Short: "Report how long sessions wait on a person (waiting/approval → next action)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := loadConfig()
+			cfg.ProviderFilter = strings.TrimSpace(strings.ToLower(flagProvider))
+			cfg.ProjectFilters = normalizeList(flagProjects)
+			cfg.Redact = flagRedact
+			cfg.IncludeEnded = true
+
+			since, err := time.ParseDuration(flagSince)
+			if err != nil || since <= 0 {
+				return fmt.Errorf("invalid --since: %q", flagSince)
+			}
+			if since > cfg.AllScanWindow {
+				cfg.AllScanWindow = since
+			}
+			by := strings.TrimSpace(strings.ToLower(flagBy))
+			if by != "" && by != "all" {
+				valid := false
+				for _, b := range latencyBreakdowns {
+					valid = valid || b == by
+				}
+				if !valid {
+					return fmt.Errorf("invalid --by: %s (use %s)", by, strings.Join(latencyBreakdowns, "|"))
+				}
+			}
+
+			now := time.Now().UTC()
+			records, err := gatherRecords(cfg, now)
+			if err != nil {
+				return err
+			}
+			var periods []idlePeriod
+			for _, r := range records {
+				periods = append(periods, collectIdlePeriods(r, now, cfg)...)
+			}
+			report := buildLatencyReport(periods, now.Add(-since), by, flagTop)
+
+			if flagJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			renderLatencyReport(report)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output JSON instead of tables")
+	cmd.Flags().StringVar(&flagProvider, "provider", "", "Filter by provider: claude|codex")
+	cmd.Flags().StringSliceVar(&flagProjects, "project", nil, "Filter by project name (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&flagSince, "since", "168h", "Only include idle periods that started within this duration")
+	cmd.Flags().StringVar(&flagBy, "by", "", "Only show one breakdown: project|provider|hour|state (default: all)")
+	cmd.Flags().IntVar(&flagTop, "top", 10, "Number of worst idle periods to list")
+	cmd.Flags().BoolVar(&flagRedact, "redact", loadConfig().Redact, "Redact project names/IDs (default from config)")
+	return cmd
+}
+
+// collectIdlePeriods walks a record's transitions. A period that is still
+// waiting counts as open only while the session is active; periods closed by
+// the session ending never got a response and are dropped.
+func collectIdlePeriods(r SessionRecord, now time.Time, cfg Config) []idlePeriod {
+	if len(r.Transitions) == 0 {
+		return nil
+	}
+	project := projectNameForRecord(r)
+	id := r.ID
+	if cfg.Redact {
+		rd := cfg.redaction()
+		project = rd.project(project)
+		id = rd.id(id)
+	}
+
+	var out []idlePeriod
+	var open *idlePeriod
+	for _, t := range r.Transitions {
+		switch t.Status {
+		case StatusWaiting, StatusApproval, StatusNeedsAttn:
+			if open == nil {
+				open = &idlePeriod{Provider: r.Provider, ID: id, Project: project, State: t.Status, Start: t.At}
+			}
+		case StatusRunning:
+			if open != nil {
+				open.End = t.At
+				out = append(out, *open)
+				open = nil
+			}
+		case StatusEnded:
+			open = nil
+		}
+	}
+	if open != nil && r.EndedAt == nil && now.Sub(nonZeroTime(r.LastSeen, r.UpdatedAt, now)) <= cfg.ActiveWindow {
+		open.End = now
+		open.Open = true
+		out = append(out, *open)
+	}
+	for i := range out {
+		out[i].Duration = out[i].End.Sub(out[i].Start)
+		out[i].DurMS = out[i].Duration.Milliseconds()
+	}
+	return out
+}
+
+func buildLatencyReport(periods []idlePeriod, since time.Time, by string, top int) latencyReport {
+	var kept []idlePeriod
+	for _, p := range periods {
+		if p.Start.Before(since) || p.Duration < 0 {
+			continue
+		}
+		kept = append(kept, p)
+	}
+
+	report := latencyReport{Since: since, Overall: latencyRowFor("all", kept)}
+	want := func(name string) bool { return by == "" || by == "all" || by == name }
+	if want("project") {
+		report.ByProject = latencyBreakdown(kept, func(p idlePeriod) string { return safe(p.Project, "unknown") }, false)
+	}
+	if want("provider") {
+		report.ByProvider = latencyBreakdown(kept, func(p idlePeriod) string { return string(p.Provider) }, false)
+	}
+	if want("hour") {
+		report.ByHour = latencyBreakdown(kept, func(p idlePeriod) string {
+			return fmt.Sprintf("%02d:00", p.Start.In(time.Local).Hour())
+		}, true)
+	}
+	if want("state") {
+		report.ByState = latencyBreakdown(kept, func(p idlePeriod) string { return string(p.State) }, false)
+	}
+
+	worst := append([]idlePeriod(nil), kept...)
+	sort.SliceStable(worst, func(i, j int) bool { return worst[i].Duration > worst[j].Duration })
+	if top >= 0 && len(worst) > top {
+		worst = worst[:top]
+	}
+	report.Worst = worst
+	return report
+}
+
+// latencyBreakdown groups periods by key. Rows are ordered by total idle time
+// (most expensive first) unless byKey is set, e.g. for hour of day.
+func latencyBreakdown(periods []idlePeriod, keyFn func(idlePeriod) string, byKey bool) []latencyRow {
+	groups := map[string][]idlePeriod{}
+	for _, p := range periods {
+		k := keyFn(p)
+		groups[k] = append(groups[k], p)
+	}
+	rows := make([]latencyRow, 0, len(groups))
+	for k, ps := range groups {
+		rows = append(rows, latencyRowFor(k, ps))
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if byKey || rows[i].TotalMS == rows[j].TotalMS {
+			return rows[i].Group < rows[j].Group
+		}
+		return rows[i].TotalMS > rows[j].TotalMS
+	})
+	return rows
+}
+
+// latencyRowFor summarizes periods. Open periods are counted and included in
+// the total, but percentiles only use periods that actually got a response.
+func latencyRowFor(group string, periods []idlePeriod) latencyRow {
+	row := latencyRow{Group: group}
+	var closed []int64
+	for _, p := range periods {
+		row.Count++
+		row.TotalMS += p.DurMS
+		if p.Open {
+			row.Open++
+			continue
+		}
+		closed = append(closed, p.DurMS)
+	}
+	if len(closed) == 0 {
+		return row
+	}
+	sort.Slice(closed, func(i, j int) bool { return closed[i] < closed[j] })
+	row.P50MS = percentileMS(closed, 0.50)
+	row.P90MS = percentileMS(closed, 0.90)
+	row.P95MS = percentileMS(closed, 0.95)
+	row.MaxMS = closed[len(closed)-1]
+	return row
+}
+
+func renderLatencyReport(r latencyReport) {
+	if r.Overall.Count == 0 {
+		fmt.Fprintln(os.Stdout, "No idle periods found.")
+		return
+	}
+	fmt.Fprintf(os.Stdout, "Idle periods since %s\n", r.Since.In(time.Local).Format("2006-01-02 15:04"))
+	renderLatencyTable("OVERALL", []latencyRow{r.Overall})
+	sections := []struct {
+		title string
+		rows  []latencyRow
+	}{
+		{"PROJECT", r.ByProject},
+		{"PROVIDER", r.ByProvider},
+		{"HOUR", r.ByHour},
+		{"STATE", r.ByState},
+	}
+	for _, s := range sections {
+		if len(s.rows) == 0 {
+			continue
+		}
+		fmt.Fprintln(os.Stdout)
+		renderLatencyTable(s.title, s.rows)
+	}
+
+	if len(r.Worst) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stdout)
+	fmt.Fprintln(os.Stdout, "Worst idle periods")
+	tw := prettytable.NewWriter()
+	tw.SetOutputMirror(os.Stdout)
+	tw.SetStyle(prettytable.StyleLight)
+	tw.Style().Options.SeparateRows = false
+	tw.AppendHeader(prettytable.Row{"IDLE", "STATE", "PROVIDER", "PROJECT", "ID", "START", ""})
+	tw.SetColumnConfigs([]prettytable.ColumnConfig{{Number: 1, Align: text.AlignRight}})
+	for _, p := range r.Worst {
+		flag := ""
+		if p.Open {
+			flag = "open"
+		}
+		tw.AppendRow(prettytable.Row{
+			fmtElapsed(p.Duration),
+			string(p.State),
+			string(p.Provider),
+			safe(p.Project, "unknown"),
+			p.ID,
+			p.Start.In(time.Local).Format("01-02 15:04"),
+			flag,
+		})
+	}
+	tw.Render()
+}
+
+func renderLatencyTable(title string, rows []latencyRow) {
+	tw := prettytable.NewWriter()
+	tw.SetOutputMirror(os.Stdout)
+	tw.SetStyle(prettytable.StyleLight)
+	tw.Style().Options.SeparateRows = false
+
+	tw.AppendHeader(prettytable.Row{title, "COUNT", "OPEN", "TOTAL", "P50", "P90", "P95", "MAX"})
+	var configs []prettytable.ColumnConfig
+	for i := 2; i <= 8; i++ {
+		configs = append(configs, prettytable.ColumnConfig{Number: i, Align: text.AlignRight})
+	}
+	tw.SetColumnConfigs(configs)
+	ms := func(v int64) string {
+		if v <= 0 {
+			return "-"
+		}
+		return fmtElapsed(time.Duration(v) * time.Millisecond)
+	}
+	for _, r := range rows {
+		tw.AppendRow(prettytable.Row{r.Group, r.Count, r.Open, ms(r.TotalMS), ms(r.P50MS), ms(r.P90MS), ms(r.P95MS), ms(r.MaxMS)})
+	}
+	tw.Render()
+}
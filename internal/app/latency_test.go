@@ -0,0 +1,118 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordTransitionCollapsesRepeats(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	var rec SessionRecord
+	recordTransition(&rec, StatusRunning, "", base)
+	recordTransition(&rec, StatusRunning, "", base.Add(time.Second))
+	recordTransition(&rec, StatusWaiting, "", base.Add(time.Minute))
+	// Late arrival is sorted into place.
+	recordTransition(&rec, StatusApproval, "", base.Add(30*time.Second))
+
+	want := []Status{StatusRunning, StatusApproval, StatusWaiting}
+	if len(rec.Transitions) != len(want) {
+		t.Fatalf("unexpected transitions: %+v", rec.Transitions)
+	}
+	for i, st := range want {
+		if rec.Transitions[i].Status != st {
+			t.Fatalf("transition %d: expected %s, got %s", i, st, rec.Transitions[i].Status)
+		}
+	}
+}
+
+func TestCollectIdlePeriods(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Redact = false
+	base := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	now := base.Add(20 * time.Minute)
+
+	rec := SessionRecord{
+		Provider: ProviderClaude,
+		ID:       "sess-1",
+		CWD:      "/tmp/alpha",
+		LastSeen: now.Add(-time.Minute),
+	}
+	recordTransition(&rec, StatusRunning, "", base)
+	recordTransition(&rec, StatusWaiting, "", base.Add(time.Minute))
+	recordTransition(&rec, StatusRunning, "", base.Add(4*time.Minute))
+	recordTransition(&rec, StatusApproval, "", base.Add(5*time.Minute))
+	recordTransition(&rec, StatusRunning, "", base.Add(5*time.Minute+30*time.Second))
+	recordTransition(&rec, StatusWaiting, "", base.Add(10*time.Minute))
+
+	periods := collectIdlePeriods(rec, now, cfg)
+	if len(periods) != 3 {
+		t.Fatalf("expected 3 periods, got %d", len(periods))
+	}
+	if periods[0].State != StatusWaiting || periods[0].Duration != 3*time.Minute {
+		t.Fatalf("unexpected first period: %+v", periods[0])
+	}
+	if periods[1].State != StatusApproval || periods[1].Duration != 30*time.Second {
+		t.Fatalf("unexpected approval period: %+v", periods[1])
+	}
+	if !periods[2].Open || periods[2].Duration != 10*time.Minute {
+		t.Fatalf("expected open trailing period, got %+v", periods[2])
+	}
+	if periods[0].Project != "alpha" {
+		t.Fatalf("unexpected project: %q", periods[0].Project)
+	}
+
+	// A session that ended without a response drops the trailing period.
+	recordTransition(&rec, StatusEnded, "", base.Add(11*time.Minute))
+	rec.EndedAt = ptrTime(base.Add(11 * time.Minute))
+	if got := collectIdlePeriods(rec, now, cfg); len(got) != 2 {
+		t.Fatalf("expected 2 periods after end, got %d", len(got))
+	}
+}
+
+func TestBuildLatencyReport(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	mk := func(project string, state Status, d time.Duration, open bool) idlePeriod {
+		return idlePeriod{
+			Provider: ProviderCodex,
+			Project:  project,
+			State:    state,
+			Start:    base,
+			End:      base.Add(d),
+			Duration: d,
+			DurMS:    d.Milliseconds(),
+			Open:     open,
+		}
+	}
+	periods := []idlePeriod{
+		mk("alpha", StatusWaiting, time.Minute, false),
+		mk("alpha", StatusWaiting, 3*time.Minute, false),
+		mk("beta", StatusApproval, 10*time.Minute, false),
+		mk("beta", StatusWaiting, 30*time.Minute, true),
+	}
+	// Periods starting before the window are ignored.
+	old := mk("gamma", StatusWaiting, time.Hour, false)
+	old.Start = base.Add(-48 * time.Hour)
+	periods = append(periods, old)
+
+	report := buildLatencyReport(periods, base.Add(-time.Hour), "", 2)
+	if report.Overall.Count != 4 || report.Overall.Open != 1 {
+		t.Fatalf("unexpected overall: %+v", report.Overall)
+	}
+	if report.Overall.MaxMS != (10 * time.Minute).Milliseconds() {
+		t.Fatalf("open periods must not count toward max, got %d", report.Overall.MaxMS)
+	}
+	if len(report.ByProject) != 2 || report.ByProject[0].Group != "beta" {
+		t.Fatalf("expected beta first by total idle, got %+v", report.ByProject)
+	}
+	if len(report.ByState) != 2 {
+		t.Fatalf("unexpected state breakdown: %+v", report.ByState)
+	}
+	if len(report.Worst) != 2 || !report.Worst[0].Open {
+		t.Fatalf("unexpected worst list: %+v", report.Worst)
+	}
+
+	only := buildLatencyReport(periods, base.Add(-time.Hour), "hour", 10)
+	if len(only.ByHour) != 1 || only.ByProject != nil {
+		t.Fatalf("expected only hour breakdown, got %+v", only)
+	}
+}
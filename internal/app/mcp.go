@@ -0,0 +1,459 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// -------------------------
+// MCP server (stdio)
+// -------------------------
+
+// mcpProtocolVersions are the protocol revisions the server speaks, newest
+// first. 2025-03-26 is left out: it requires JSON-RPC batches, which the
+// server does not accept.
+var mcpProtocolVersions = []string{"2025-06-18", "2024-11-05"}
+
+// negotiateProtocolVersion echoes the client's revision when the server
+// speaks it and otherwise offers the newest one, as the spec asks.
+func negotiateProtocolVersion(requested string) string {
+	if slices.Contains(mcpProtocolVersions, requested) {
+		return requested
+	}
+	return mcpProtocolVersions[0]
+}
+
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+// mcpArgs is the union of all tool arguments; each tool reads what it needs.
+type mcpArgs struct {
+	ID             string     `json:"id"`
+	Provider       string     `json:"provider"`
+	Project        stringList `json:"project"`
+	Status         stringList `json:"status"`
+	All            bool       `json:"all"`
+	IncludeLastMsg bool       `json:"include_last_msg"`
+	Max            int        `json:"max"`
+	Sort           string     `json:"sort"`
+	GroupBy        string     `json:"group_by"`
+}
+
+// stringList accepts either a single string or an array of strings.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*l = normalizeList([]string{one})
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return errors.New("expected a string or an array of strings")
+	}
+	*l = normalizeList(many)
+	return nil
+}
+
+// mcpSession is the agent-facing shape of a SessionView.
+type mcpSession struct {
+	Provider      Provider  `json:"provider"`
+	ID            string    `json:"id"`
+	Status        Status    `json:"status"`
+	Reason        string    `json:"reason,omitempty"`
+	Project       string    `json:"project,omitempty"`
+	Dir           string    `json:"dir,omitempty"`
+	Branch        string    `json:"branch,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	CostUSD       float64   `json:"cost_usd"`
+	LastSeen      time.Time `json:"last_seen"`
+	AgeSeconds    int64     `json:"age_seconds"`
+	Turns         int       `json:"turns,omitempty"`
+	TurnSeconds   int64     `json:"turn_seconds,omitempty"` // in-flight turn
+	LastUser      string    `json:"last_user,omitempty"`
+	LastAssistant string    `json:"last_assistant,omitempty"`
+	Detail        string    `json:"detail,omitempty"`
+}
+
+func toMCPSession(v SessionView, withDetail bool) mcpSession {
+	s := mcpSession{
+		Provider:      v.Provider,
+		ID:            v.ID,
+		Status:        v.Status,
+		Reason:        v.Reason,
+		Project:       v.Project,
+		Dir:           v.Dir,
+		Branch:        v.Branch,
+		Model:         v.Model,
+		CostUSD:       v.Cost,
+		LastSeen:      v.LastSeen,
+		AgeSeconds:    int64(v.Age / time.Second),
+		Turns:         v.TurnCount,
+		TurnSeconds:   int64(v.TurnElapsed / time.Second),
+		LastUser:      v.LastUser,
+		LastAssistant: v.LastAssist,
+	}
+	if withDetail {
+		s.Detail = v.Detail
+	}
+	return s
+}
+
+func newMCPCmd() *cobra.Command {
+	var flagNoRedact bool
+
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Run a Model Context Protocol server on stdio",
+		Long:  "Serves session status to MCP clients (Claude Code, Codex, ...) over stdio. Paths, IDs and messages are redacted unless --no-redact is set.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s := &mcpServer{redact: !flagNoRedact, redactor: loadConfig().redaction()}
+			return s.serve(cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagNoRedact, "no-redact", false, "Expose raw IDs, paths and messages (redaction is on by default)")
+	return cmd
+}
+
+type mcpServer struct {
+	redact   bool
+	redactor *redactor // built once from the redaction settings at startup
+}
+
+// serve handles newline-delimited JSON-RPC messages until r is exhausted.
+func (s *mcpServer) serve(r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 8*1024*1024)
+	enc := json.NewEncoder(w)
+
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			if err := enc.Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: "parse error"}}); err != nil {
+				return err
+			}
+			continue
+		}
+		resp, ok := s.handle(req)
+		if !ok {
+			continue // notification
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// handle returns the response for req, or false for notifications.
+func (s *mcpServer) handle(req rpcRequest) (rpcResponse, bool) {
+	if len(req.ID) == 0 || string(req.ID) == "null" {
+		return rpcResponse{}, false
+	}
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+		return resp, true
+	}
+
+	switch req.Method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &p)
+		resp.Result = map[string]any{
+			"protocolVersion": negotiateProtocolVersion(p.ProtocolVersion),
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": appName, "version": buildVersion()},
+		}
+	case "ping":
+		resp.Result = map[string]any{}
+	case "tools/list":
+		resp.Result = map[string]any{"tools": mcpTools()}
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			resp.Error = &rpcError{Code: rpcInvalidParams, Message: "invalid params"}
+			return resp, true
+		}
+		result, err := s.callTool(p.Name, p.Arguments)
+		if err != nil {
+			var unknown unknownToolError
+			if errors.As(err, &unknown) {
+				resp.Error = &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+				return resp, true
+			}
+			resp.Result = mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}
+			return resp, true
+		}
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			resp.Result = mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}
+			return resp, true
+		}
+		resp.Result = mcpToolResult{Content: []mcpContent{{Type: "text", Text: string(b)}}}
+	default:
+		resp.Error = &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	return resp, true
+}
+
+type unknownToolError string
+
+func (e unknownToolError) Error() string { return "unknown tool: " + string(e) }
+
+func (s *mcpServer) callTool(name string, raw json.RawMessage) (any, error) {
+	var args mcpArgs
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %v", err)
+		}
+	}
+	cfg, err := s.toolConfig(args)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "list_sessions":
+		if args.Sort != "" {
+			cfg.SortBy = args.Sort
+		}
+		views, err := gatherSessions(cfg)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]mcpSession, 0, len(views))
+		for _, v := range views {
+			out = append(out, toMCPSession(v, false))
+		}
+		return out, nil
+	case "get_session":
+		return s.getSession(cfg, args.ID)
+	case "list_projects":
+		stats, err := gatherProjectStats(cfg)
+		if err != nil {
+			return nil, err
+		}
+		sortProjectStats(stats, args.Sort)
+		return stats, nil
+	case "summary":
+		groupBy := strings.ToLower(strings.TrimSpace(args.GroupBy))
+		if groupBy == "" {
+			groupBy = "project"
+		}
+		switch groupBy {
+		case "provider", "project", "status", "day", "hour":
+		default:
+			return nil, fmt.Errorf("invalid group_by: %s", args.GroupBy)
+		}
+		views, err := gatherSessions(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return summarizeSessions(views, groupBy), nil
+	default:
+		return nil, unknownToolError(name)
+	}
+}
+
+// toolConfig builds the per-call config. Whether to redact follows the server
+// flag, not the config file; how follows the server's redactor.
+func (s *mcpServer) toolConfig(args mcpArgs) (Config, error) {
+	cfg := loadConfig()
+	cfg.Redact = s.redact
+	if s.redactor != nil {
+		cfg.redactor = s.redactor
+	}
+	cfg.IncludeLastMsg = args.IncludeLastMsg
+	cfg.GroupBy = ""
+
+	cfg.ProviderFilter = strings.ToLower(strings.TrimSpace(args.Provider))
+	if cfg.ProviderFilter != "" && cfg.ProviderFilter != string(ProviderClaude) && cfg.ProviderFilter != string(ProviderCodex) {
+		return Config{}, errors.New("invalid provider (use claude or codex)")
+	}
+	cfg.ProjectFilters = []string(args.Project)
+	statuses, err := parseStatusFilters(args.Status)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.StatusFilters = statuses
+	cfg.IncludeEnded = args.All
+	if cfg.IncludeEnded {
+		cfg.AllScanWindow = defaultAllScanWindow
+	}
+	if args.Max < 0 {
+		return Config{}, errors.New("max must be >= 0")
+	}
+	if args.Max > 0 {
+		cfg.MaxSessions = args.Max
+	}
+	return cfg, nil
+}
+
+func (s *mcpServer) getSession(cfg Config, id string) (mcpSession, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return mcpSession{}, errors.New("missing session id")
+	}
+	now := time.Now().UTC()
+
+	// Look across the wide window, but report status as the list would.
+	viewCfg := cfg
+	cfg.IncludeEnded = true
+	cfg.AllScanWindow = defaultAllScanWindow
+	records, err := gatherRecords(cfg, now)
+	if err != nil {
+		return mcpSession{}, err
+	}
+
+	rd := cfg.redaction()
+	var matches []SessionRecord
+	for _, r := range records {
+		if r.ID == id || rd.id(r.ID) == id {
+			matches = []SessionRecord{r}
+			break
+		}
+		if matchID(rd, r.ID, id) {
+			matches = append(matches, r)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return mcpSession{}, errors.New("session not found")
+	case 1:
+		viewCfg.IncludeLastMsg = true
+		return toMCPSession(makeView(matches[0], now, viewCfg), true), nil
+	default:
+		return mcpSession{}, fmt.Errorf("ambiguous session id %q (%d matches)", id, len(matches))
+	}
+}
+
+func mcpTools() []mcpTool {
+	str := func(desc string) map[string]any {
+		return map[string]any{"type": "string", "description": desc}
+	}
+	strs := func(desc string) map[string]any {
+		return map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": desc}
+	}
+	boolean := func(desc string) map[string]any {
+		return map[string]any{"type": "boolean", "description": desc}
+	}
+	provider := map[string]any{"type": "string", "enum": []string{"claude", "codex"}, "description": "Only this provider"}
+	object := func(props map[string]any, required ...string) map[string]any {
+		schema := map[string]any{"type": "object", "properties": props}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+
+	return []mcpTool{
+		{
+			Name:        "list_sessions",
+			Description: "List Claude Code and Codex sessions with their current status (running, waiting, approval, needs_attention, stale, ended).",
+			InputSchema: object(map[string]any{
+				"provider":         provider,
+				"project":          strs("Only these projects"),
+				"status":           strs("Only these statuses"),
+				"all":              boolean("Include ended/stale sessions"),
+				"include_last_msg": boolean("Include the last user/assistant messages"),
+				"max":              map[string]any{"type": "integer", "minimum": 0, "description": "Maximum sessions to return (0 = config default)"},
+				"sort":             str("Sort by: last_seen|status|provider|cost|project"),
+			}),
+		},
+		{
+			Name:        "get_session",
+			Description: "Get one session by ID or ID prefix, including status detail and last messages.",
+			InputSchema: object(map[string]any{
+				"id":       str("Session ID, ID prefix or redacted ID"),
+				"provider": provider,
+			}, "id"),
+		},
+		{
+			Name:        "list_projects",
+			Description: "List projects with session counts, statuses and last activity.",
+			InputSchema: object(map[string]any{
+				"provider": provider,
+				"all":      boolean("Include ended/stale sessions"),
+				"sort":     str("Sort by: count|name|last_seen"),
+			}),
+		},
+		{
+			Name:        "summary",
+			Description: "Count sessions by status, grouped by project, provider, status, day or hour.",
+			InputSchema: object(map[string]any{
+				"group_by": map[string]any{"type": "string", "enum": []string{"project", "provider", "status", "day", "hour"}},
+				"provider": provider,
+				"project":  strs("Only these projects"),
+				"all":      boolean("Include ended/stale sessions"),
+			}),
+		},
+	}
+}
+
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
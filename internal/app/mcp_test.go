@@ -0,0 +1,164 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func runMCP(t *testing.T, s *mcpServer, msgs ...string) []rpcResponse {
+	t.Helper()
+	var out bytes.Buffer
+	if err := s.serve(strings.NewReader(strings.Join(msgs, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("serve error: %v", err)
+	}
+	var resps []rpcResponse
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var r rpcResponse
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		resps = append(resps, r)
+	}
+	return resps
+}
+
+func mcpToolText(t *testing.T, r rpcResponse) (string, bool) {
+	t.Helper()
+	b, _ := json.Marshal(r.Result)
+	var res mcpToolResult
+	if err := json.Unmarshal(b, &res); err != nil || len(res.Content) != 1 {
+		t.Fatalf("unexpected tool result: %s", b)
+	}
+	return res.Content[0].Text, res.IsError
+}
+
+func TestMCPHandshake(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	resps := runMCP(t, &mcpServer{redact: true},
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"nope"}`,
+		`not json`,
+	)
+	if len(resps) != 4 {
+		t.Fatalf("expected 4 responses (notification gets none), got %d", len(resps))
+	}
+	init, _ := json.Marshal(resps[0].Result)
+	if !strings.Contains(string(init), `"protocolVersion":"2025-06-18"`) || !strings.Contains(string(init), `"name":"aistat"`) {
+		t.Fatalf("unexpected initialize result: %s", init)
+	}
+	tools, _ := json.Marshal(resps[1].Result)
+	for _, name := range []string{"list_sessions", "get_session", "list_projects", "summary"} {
+		if !strings.Contains(string(tools), `"name":"`+name+`"`) {
+			t.Fatalf("tools/list missing %s: %s", name, tools)
+		}
+	}
+	if resps[2].Error == nil || resps[2].Error.Code != rpcMethodNotFound {
+		t.Fatalf("expected method not found, got %+v", resps[2])
+	}
+	if resps[3].Error == nil || resps[3].Error.Code != rpcParseError {
+		t.Fatalf("expected parse error, got %+v", resps[3])
+	}
+}
+
+func TestMCPToolsRedactByDefault(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CODEX_HOME", t.TempDir())
+
+	b, _ := json.Marshal(map[string]any{
+		"hook_event_name": "UserPromptSubmit",
+		"session_id":      "sess-mcp-1234567890",
+		"cwd":             "/Users/alice/work/secret-project",
+		"prompt":          "deploy to prod",
+	})
+	if err := ingestClaudeHook(bytes.NewReader(b), io.Discard); err != nil {
+		t.Fatalf("ingestClaudeHook: %v", err)
+	}
+
+	resps := runMCP(t, &mcpServer{redact: true},
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_sessions","arguments":{"include_last_msg":true}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"summary","arguments":{"group_by":"provider"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_session","arguments":{"id":"does-not-exist"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"missing_tool"}}`,
+	)
+	if len(resps) != 4 {
+		t.Fatalf("expected 4 responses, got %d", len(resps))
+	}
+
+	text, isErr := mcpToolText(t, resps[0])
+	if isErr {
+		t.Fatalf("list_sessions failed: %s", text)
+	}
+	var sessions []mcpSession
+	if err := json.Unmarshal([]byte(text), &sessions); err != nil || len(sessions) != 1 {
+		t.Fatalf("unexpected list_sessions output: %s", text)
+	}
+	if strings.Contains(text, "sess-mcp-1234567890") || strings.Contains(text, "/Users/alice/work/secret-project") {
+		t.Fatalf("expected redacted output, got %s", text)
+	}
+
+	text, isErr = mcpToolText(t, resps[1])
+	if isErr || !strings.Contains(text, `"group": "claude"`) {
+		t.Fatalf("unexpected summary output: %s", text)
+	}
+
+	if text, isErr := mcpToolText(t, resps[2]); !isErr || !strings.Contains(text, "not found") {
+		t.Fatalf("expected get_session tool error, got %q", text)
+	}
+	if resps[3].Error == nil || resps[3].Error.Code != rpcInvalidParams {
+		t.Fatalf("expected unknown tool error, got %+v", resps[3])
+	}
+
+	// Redacted IDs round-trip through get_session.
+	req, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 5, "method": "tools/call",
+		"params": map[string]any{"name": "get_session", "arguments": map[string]any{"id": sessions[0].ID}},
+	})
+	resps = runMCP(t, &mcpServer{redact: true}, string(req))
+	text, isErr = mcpToolText(t, resps[0])
+	if isErr || !strings.Contains(text, `"detail"`) {
+		t.Fatalf("unexpected get_session output: %s", text)
+	}
+}
+
+func TestMCPNegotiatesProtocolVersion(t *testing.T) {
+	for requested, want := range map[string]string{
+		"2024-11-05": "2024-11-05",
+		"2025-06-18": "2025-06-18",
+		"2025-03-26": "2025-06-18",
+		"1999-01-01": "2025-06-18",
+		"":           "2025-06-18",
+	} {
+		if got := negotiateProtocolVersion(requested); got != want {
+			t.Fatalf("negotiateProtocolVersion(%q) = %q, want %q", requested, got, want)
+		}
+	}
+}
+
+func TestMCPToolConfigKeepsMaxSessionsDefault(t *testing.T) {
+	t.Setenv("AISTAT_HOME", t.TempDir())
+	s := &mcpServer{redact: true}
+
+	cfg, err := s.toolConfig(mcpArgs{})
+	if err != nil {
+		t.Fatalf("toolConfig error: %v", err)
+	}
+	if want := loadConfig().MaxSessions; cfg.MaxSessions != want {
+		t.Fatalf("MaxSessions = %d without max, want the config default %d", cfg.MaxSessions, want)
+	}
+	if cfg, _ = s.toolConfig(mcpArgs{Max: 3}); cfg.MaxSessions != 3 {
+		t.Fatalf("MaxSessions = %d, want 3", cfg.MaxSessions)
+	}
+	if _, err := s.toolConfig(mcpArgs{Max: -1}); err == nil {
+		t.Fatalf("expected an error for a negative max")
+	}
+}
@@ -88,7 +88,7 @@ func renderTable(sessions []SessionView, fields []string) {
 	for i, f := range fields {
 		headers = append(headers, strings.ToUpper(f))
 		switch f {
-		case "age", "cost":
+		case "age", "cost", "turns", "last_turn", "mean_turn", "p95_turn":
 			configs = append(configs, prettytable.ColumnConfig{Number: i + 1, Align: text.AlignRight})
 		}
 	}
@@ -134,11 +134,29 @@ func fieldValue(s SessionView, field string) string {
 		return s.LastUser
 	case "last_assistant":
 		return s.LastAssist
+	case "turns":
+		if s.TurnCount == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d", s.TurnCount)
+	case "last_turn":
+		return fmtTurnDuration(s.LastTurn)
+	case "mean_turn":
+		return fmtTurnDuration(s.MeanTurn)
+	case "p95_turn":
+		return fmtTurnDuration(s.P95Turn)
 	default:
 		return ""
 	}
 }
 
+func fmtTurnDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return fmtElapsed(d)
+}
+
 func sessionsToMaps(sessions []SessionView, fields []string) []map[string]any {
 	if len(fields) == 0 {
 		fields = defaultFields()
@@ -0,0 +1,283 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -------------------------
+// Permission requests (answered from the TUI)
+// -------------------------
+
+const (
+	// Claude's default hook timeout is 60s; stay well below it.
+	defaultPermissionHold = 30 * time.Second
+	// The hook only holds a request while a TUI has touched its heartbeat
+	// this recently. The TUI touches it on its own ticker, whatever --refresh
+	// is, so the max age only has to cover a few missed beats.
+	permissionListenerMaxAge = 5 * time.Second
+	permissionHeartbeatEvery = time.Second
+	permissionPoll           = 200 * time.Millisecond
+)
+
+// PermissionRequest is a held Claude PermissionRequest hook waiting for a
+// decision from the TUI.
+type PermissionRequest struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	CWD       string    `json:"cwd,omitempty"`
+	Tool      string    `json:"tool"`
+	Summary   string    `json:"summary,omitempty"` // command or target path
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type PermissionDecision struct {
+	ID        string    `json:"id"`
+	Allow     bool      `json:"allow"`
+	Message   string    `json:"message,omitempty"`
+	DecidedAt time.Time `json:"decided_at"`
+}
+
+func permissionsDir(sub string) (string, error) {
+	ad, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ad, "permissions", sub), nil
+}
+
+func permissionHeartbeatPath() (string, error) {
+	return permissionsDir("tui.heartbeat")
+}
+
+// touchPermissionHeartbeat marks a TUI as able to answer permission requests.
+func touchPermissionHeartbeat() error {
+	p, err := permissionHeartbeatPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := os.Chtimes(p, now, now); err == nil {
+		return nil
+	}
+	return os.WriteFile(p, nil, 0o600)
+}
+
+// keepPermissionHeartbeat touches the heartbeat every
+// permissionHeartbeatEvery until the returned stop is called, which also
+// clears it.
+func keepPermissionHeartbeat() (stop func()) {
+	_ = touchPermissionHeartbeat()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		t := time.NewTicker(permissionHeartbeatEvery)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				_ = touchPermissionHeartbeat()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		clearPermissionHeartbeat()
+	}
+}
+
+func clearPermissionHeartbeat() {
+	if p, err := permissionHeartbeatPath(); err == nil {
+		_ = os.Remove(p)
+	}
+}
+
+func permissionListenerActive(now time.Time) bool {
+	p, err := permissionHeartbeatPath()
+	if err != nil {
+		return false
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		return false
+	}
+	return now.Sub(st.ModTime()) <= permissionListenerMaxAge
+}
+
+func writeJSONAtomic(path string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp_*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// holdClaudePermission parks a PermissionRequest hook until the TUI answers,
+// the hold expires or the TUI goes away. It returns true when a decision was
+// written to w; otherwise Claude shows its normal prompt.
+func holdClaudePermission(cfg Config, m map[string]any, w io.Writer) bool {
+	now := time.Now().UTC()
+	if cfg.PermissionHold <= 0 || !permissionListenerActive(now) {
+		return false
+	}
+	in := policyInputFromHook(m)
+	if in.SessionID == "" {
+		return false
+	}
+	req := PermissionRequest{
+		ID:        fmt.Sprintf("%d_%d", now.UnixNano(), os.Getpid()),
+		SessionID: in.SessionID,
+		CWD:       in.CWD,
+		Tool:      in.Tool,
+		Summary:   safe(in.Command, in.Path),
+		CreatedAt: now,
+		ExpiresAt: now.Add(cfg.PermissionHold),
+	}
+	pendingDir, err := permissionsDir("pending")
+	if err != nil {
+		return false
+	}
+	decisionDir, err := permissionsDir("decisions")
+	if err != nil {
+		return false
+	}
+	pendingPath := filepath.Join(pendingDir, req.ID+".json")
+	decisionPath := filepath.Join(decisionDir, req.ID+".json")
+	if err := writeJSONAtomic(pendingPath, req); err != nil {
+		return false
+	}
+	defer func() {
+		_ = os.Remove(pendingPath)
+		_ = os.Remove(decisionPath)
+	}()
+
+	for time.Now().Before(req.ExpiresAt) {
+		if b, err := os.ReadFile(decisionPath); err == nil {
+			var d PermissionDecision
+			if err := json.Unmarshal(b, &d); err == nil {
+				writePermissionDecision(w, d)
+				return true
+			}
+		}
+		if !permissionListenerActive(time.Now()) {
+			return false
+		}
+		time.Sleep(permissionPoll)
+	}
+	return false
+}
+
+func writePermissionDecision(w io.Writer, d PermissionDecision) {
+	decision := map[string]any{"behavior": "allow"}
+	if !d.Allow {
+		msg := d.Message
+		if msg == "" {
+			msg = "Denied from aistat"
+		}
+		decision = map[string]any{"behavior": "deny", "message": msg}
+	}
+	out := map[string]any{
+		"hookSpecificOutput": map[string]any{
+			"hookEventName": "PermissionRequest",
+			"decision":      decision,
+		},
+	}
+	if b, err := json.Marshal(out); err == nil {
+		fmt.Fprintln(w, string(b))
+	}
+}
+
+// listPendingPermissions returns unexpired held requests (oldest first) and
+// removes leftovers from hooks that were killed mid-wait.
+func listPendingPermissions(now time.Time) ([]PermissionRequest, error) {
+	dir, err := permissionsDir("pending")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []PermissionRequest
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		b, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		var req PermissionRequest
+		if err := json.Unmarshal(b, &req); err != nil {
+			continue
+		}
+		if !now.Before(req.ExpiresAt) {
+			if now.Sub(req.ExpiresAt) > time.Minute {
+				_ = os.Remove(p)
+			}
+			continue
+		}
+		out = append(out, req)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// answerPermission records a decision for a held request.
+func answerPermission(id string, allow bool) error {
+	if id == "" || strings.Trim(id, ".") == "" || fileSafeRe.MatchString(id) {
+		return errors.New("invalid request id")
+	}
+	pendingDir, err := permissionsDir("pending")
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(pendingDir, id+".json")); err != nil {
+		return errors.New("permission request is no longer pending")
+	}
+	decisionDir, err := permissionsDir("decisions")
+	if err != nil {
+		return err
+	}
+	return writeJSONAtomic(filepath.Join(decisionDir, id+".json"), PermissionDecision{
+		ID:        id,
+		Allow:     allow,
+		DecidedAt: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func permissionHookPayload() map[string]any {
+	return map[string]any{
+		"hook_event_name": "PermissionRequest",
+		"session_id":      "sess-1",
+		"cwd":             "/tmp/alpha",
+		"tool_name":       "Bash",
+		"tool_input":      map[string]any{"command": "rm -rf build"},
+	}
+}
+
+func TestHoldClaudePermissionWithoutTUI(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	cfg := defaultConfig()
+	var out bytes.Buffer
+	if holdClaudePermission(cfg, permissionHookPayload(), &out) {
+		t.Fatalf("expected no hold without a TUI heartbeat")
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no output, got %q", out.String())
+	}
+}
+
+func TestHoldClaudePermissionAnswered(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	if err := touchPermissionHeartbeat(); err != nil {
+		t.Fatalf("touchPermissionHeartbeat: %v", err)
+	}
+	cfg := defaultConfig()
+	cfg.PermissionHold = 5 * time.Second
+
+	go func() {
+		deadline := time.Now().Add(3 * time.Second)
+		for time.Now().Before(deadline) {
+			reqs, _ := listPendingPermissions(time.Now().UTC())
+			if len(reqs) == 1 {
+				if reqs[0].Tool != "Bash" || reqs[0].Summary != "rm -rf build" {
+					t.Errorf("unexpected pending request: %+v", reqs[0])
+				}
+				_ = answerPermission(reqs[0].ID, false)
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	var out bytes.Buffer
+	if !holdClaudePermission(cfg, permissionHookPayload(), &out) {
+		t.Fatalf("expected the request to be answered")
+	}
+	var resp struct {
+		HookSpecificOutput struct {
+			HookEventName string `json:"hookEventName"`
+			Decision      struct {
+				Behavior string `json:"behavior"`
+				Message  string `json:"message"`
+			} `json:"decision"`
+		} `json:"hookSpecificOutput"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("decode output %q: %v", out.String(), err)
+	}
+	if resp.HookSpecificOutput.HookEventName != "PermissionRequest" || resp.HookSpecificOutput.Decision.Behavior != "deny" {
+		t.Fatalf("unexpected output: %+v", resp)
+	}
+	if reqs, _ := listPendingPermissions(time.Now().UTC()); len(reqs) != 0 {
+		t.Fatalf("expected pending request to be cleaned up, got %d", len(reqs))
+	}
+}
+
+func TestAnswerPermissionRejectsUnknownIDs(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	if err := answerPermission("../escape", true); err == nil {
+		t.Fatalf("expected invalid id to be rejected")
+	}
+	if err := answerPermission("123_456", true); err == nil {
+		t.Fatalf("expected missing request to be rejected")
+	}
+}
+
+func TestPermissionHeartbeatKeepsItselfFresh(t *testing.T) {
+	t.Setenv("AISTAT_HOME", t.TempDir())
+
+	stop := keepPermissionHeartbeat()
+	p, err := permissionHeartbeatPath()
+	if err != nil {
+		t.Fatalf("permissionHeartbeatPath: %v", err)
+	}
+	// As if the last beat were long ago, e.g. with a slow --refresh
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(p, old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+	deadline := time.Now().Add(permissionHeartbeatEvery + time.Second)
+	for !permissionListenerActive(time.Now()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("heartbeat was not refreshed on its own ticker")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	stop()
+	if permissionListenerActive(time.Now()) {
+		t.Fatalf("stop should clear the heartbeat")
+	}
+}
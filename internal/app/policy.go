@@ -0,0 +1,508 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// -------------------------
+// Policy gate (Claude PreToolUse)
+// -------------------------
+
+const (
+	policyAllow = "allow"
+	policyDeny  = "deny"
+	policyAsk   = "ask"
+	// policyPass is audited when no rule matched and Claude decides as usual.
+	policyPass = "pass"
+
+	// Audit log is rotated once it grows past this size.
+	policyAuditMaxBytes = 5 * 1024 * 1024
+)
+
+// PolicyRule matches a tool call; every non-empty field must match. Rules are
+// evaluated in order and the first match decides.
+type PolicyRule struct {
+	Name           string `json:"name,omitempty"`
+	Tool           string `json:"tool,omitempty"`            // tool name glob, "|" separates alternatives (e.g. "Edit|Write")
+	Command        string `json:"command,omitempty"`         // regex on tool_input.command
+	Path           string `json:"path,omitempty"`            // glob on the target path ("**" spans directories, "~" expands)
+	OutsideProject bool   `json:"outside_project,omitempty"` // target path resolves outside the session's cwd
+	Project        string `json:"project,omitempty"`         // project name (same matching as --project)
+	Decision       string `json:"decision"`                  // allow|deny|ask
+	Reason         string `json:"reason,omitempty"`
+}
+
+// policyInput is the part of a PreToolUse hook payload rules look at.
+type policyInput struct {
+	SessionID string
+	CWD       string
+	Project   string
+	Tool      string
+	Command   string
+	Path      string
+}
+
+type policyResult struct {
+	Decision string
+	Reason   string
+	Rule     string
+}
+
+type policyAuditEntry struct {
+	TS        time.Time `json:"ts"`
+	SessionID string    `json:"session_id"`
+	Project   string    `json:"project,omitempty"`
+	Tool      string    `json:"tool"`
+	Command   string    `json:"command,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Decision  string    `json:"decision"`
+	Rule      string    `json:"rule,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+func validPolicyDecision(d string) bool {
+	return d == policyAllow || d == policyDeny || d == policyAsk
+}
+
+// validatePolicyRule reports configuration problems (bad regex/decision).
+func validatePolicyRule(r PolicyRule) error {
+	if !validPolicyDecision(strings.ToLower(strings.TrimSpace(r.Decision))) {
+		return fmt.Errorf("invalid decision %q (use allow|deny|ask)", r.Decision)
+	}
+	if r.Command != "" {
+		if _, err := regexp.Compile(r.Command); err != nil {
+			return fmt.Errorf("invalid command regex: %w", err)
+		}
+	}
+	if r.Path != "" {
+		if _, err := globToRegexp(expandHome(r.Path)); err != nil {
+			return fmt.Errorf("invalid path glob: %w", err)
+		}
+	}
+	if r.Tool == "" && r.Command == "" && r.Path == "" && !r.OutsideProject && r.Project == "" {
+		return errors.New("rule matches everything (set tool, command, path, outside_project or project)")
+	}
+	return nil
+}
+
+// normalizePolicyRules lowercases rule projects the way --project values are,
+// since matchesProject compares against lowercased names.
+func normalizePolicyRules(rules []PolicyRule) []PolicyRule {
+	out := make([]PolicyRule, len(rules))
+	for i, r := range rules {
+		r.Project = strings.ToLower(strings.TrimSpace(r.Project))
+		out[i] = r
+	}
+	return out
+}
+
+func policyRuleLabel(i int, r PolicyRule) string {
+	if strings.TrimSpace(r.Name) != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("rule-%d", i+1)
+}
+
+// evaluatePolicy returns the decision of the first matching rule, or
+// policyPass when none match. An invalid rule fails closed: it answers ask
+// for every call to its tool and project, since what it meant to match is
+// unknown.
+func evaluatePolicy(rd *redactor, rules []PolicyRule, in policyInput) policyResult {
+	for i, r := range rules {
+		if err := validatePolicyRule(r); err != nil {
+			if r.Tool != "" && !matchToolPattern(r.Tool, in.Tool) || r.Project != "" && !matchesProject(rd, in.Project, []string{r.Project}) {
+				continue
+			}
+			return policyResult{
+				Decision: policyAsk,
+				Reason:   fmt.Sprintf("aistat policy: %s is invalid (%v)", policyRuleLabel(i, r), err),
+				Rule:     policyRuleLabel(i, r),
+			}
+		}
+		if !policyRuleMatches(rd, r, in) {
+			continue
+		}
+		reason := r.Reason
+		if reason == "" {
+			reason = "aistat policy: " + policyRuleLabel(i, r)
+		}
+		return policyResult{
+			Decision: strings.ToLower(strings.TrimSpace(r.Decision)),
+			Reason:   reason,
+			Rule:     policyRuleLabel(i, r),
+		}
+	}
+	return policyResult{Decision: policyPass}
+}
+
+func policyRuleMatches(rd *redactor, r PolicyRule, in policyInput) bool {
+	if r.Tool != "" && !matchToolPattern(r.Tool, in.Tool) {
+		return false
+	}
+	if r.Command != "" {
+		re, err := regexp.Compile(r.Command)
+		if err != nil || in.Command == "" || !re.MatchString(in.Command) {
+			return false
+		}
+	}
+	if r.Path != "" || r.OutsideProject {
+		if in.Path == "" {
+			return false
+		}
+		abs := in.Path
+		if !filepath.IsAbs(abs) && in.CWD != "" {
+			abs = filepath.Join(in.CWD, abs)
+		}
+		abs = filepath.Clean(abs)
+		if r.Path != "" && !matchPathGlob(r.Path, abs, in.CWD) {
+			return false
+		}
+		if r.OutsideProject && (in.CWD == "" || pathWithin(abs, in.CWD)) {
+			return false
+		}
+	}
+	if r.Project != "" && !matchesProject(rd, in.Project, []string{r.Project}) {
+		return false
+	}
+	return true
+}
+
+func matchToolPattern(pattern, tool string) bool {
+	for _, alt := range strings.Split(pattern, "|") {
+		alt = strings.TrimSpace(alt)
+		if alt == "" {
+			continue
+		}
+		if ok, err := filepath.Match(alt, tool); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathGlob matches absolute globs against the absolute path and relative
+// globs against the path relative to cwd.
+func matchPathGlob(pattern, abs, cwd string) bool {
+	pattern = expandHome(pattern)
+	target := abs
+	if !filepath.IsAbs(pattern) {
+		if cwd == "" {
+			return false
+		}
+		rel, err := filepath.Rel(cwd, abs)
+		if err != nil {
+			return false
+		}
+		target = rel
+	}
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(target)
+}
+
+// globToRegexp converts a glob with "*", "?" and "**" into an anchored regexp.
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				// "**/" also matches zero directories.
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+func expandHome(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+	}
+	return p
+}
+
+func pathWithin(p, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), p)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// policyInputFromHook extracts the fields rules match on from a hook payload.
+func policyInputFromHook(m map[string]any) policyInput {
+	in := policyInput{
+		SessionID: normalizePlaceholder(getString(m, "session_id")),
+		CWD:       normalizePlaceholder(getString(m, "cwd")),
+		Tool:      strings.TrimSpace(getString(m, "tool_name")),
+	}
+	in.Project = baseName(in.CWD)
+	if ti, ok := m["tool_input"].(map[string]any); ok {
+		in.Command = asString(ti["command"])
+		for _, k := range []string{"file_path", "notebook_path", "path"} {
+			if p := strings.TrimSpace(asString(ti[k])); p != "" {
+				in.Path = p
+				break
+			}
+		}
+	}
+	return in
+}
+
+// applyClaudePolicy evaluates a PreToolUse payload and writes Claude's hook
+// output JSON to w when a rule decides. Every evaluation is audited.
+func applyClaudePolicy(cfg Config, m map[string]any, w io.Writer) policyResult {
+	if len(cfg.PolicyRules) == 0 {
+		return policyResult{Decision: policyPass}
+	}
+	in := policyInputFromHook(m)
+	res := evaluatePolicy(cfg.redaction(), cfg.PolicyRules, in)
+	if res.Decision != policyPass {
+		out := map[string]any{
+			"hookSpecificOutput": map[string]any{
+				"hookEventName":            "PreToolUse",
+				"permissionDecision":       res.Decision,
+				"permissionDecisionReason": res.Reason,
+			},
+		}
+		if b, err := json.Marshal(out); err == nil {
+			fmt.Fprintln(w, string(b))
+		}
+	}
+	if cfg.PolicyAudit {
+		_ = appendPolicyAudit(policyAuditEntry{
+			TS:        time.Now().UTC(),
+			SessionID: in.SessionID,
+			Project:   in.Project,
+			Tool:      in.Tool,
+			Command:   in.Command,
+			Path:      in.Path,
+			Decision:  res.Decision,
+			Rule:      res.Rule,
+			Reason:    res.Reason,
+		})
+	}
+	return res
+}
+
+func policyAuditPath() (string, error) {
+	ad, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ad, "policy", "audit.jsonl"), nil
+}
+
+func appendPolicyAudit(e policyAuditEntry) error {
+	p, err := policyAuditPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return withLock(p+".lock", func() error {
+		if st, err := os.Stat(p); err == nil && st.Size() > policyAuditMaxBytes {
+			_ = os.Rename(p, p+".1")
+		}
+		f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = f.Write(append(b, '\n'))
+		return err
+	})
+}
+
+func readPolicyAudit(limit int) ([]policyAuditEntry, error) {
+	p, err := policyAuditPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var out []policyAuditEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for sc.Scan() {
+		var e policyAuditEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		out = append(out, e)
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out, sc.Err()
+}
+
+func newPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Inspect and test Claude tool-use policy rules",
+		Long: `Rules live under "policy" in config.json and are evaluated on every Claude
+PreToolUse hook. The first matching rule returns allow/deny/ask to Claude;
+when nothing matches Claude decides as usual.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List configured policy rules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := loadConfig()
+			if len(cfg.PolicyRules) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No policy rules configured.")
+				return nil
+			}
+			tw := prettytable.NewWriter()
+			tw.SetOutputMirror(cmd.OutOrStdout())
+			tw.SetStyle(prettytable.StyleLight)
+			tw.Style().Options.SeparateRows = false
+			tw.AppendHeader(prettytable.Row{"#", "NAME", "DECISION", "TOOL", "COMMAND", "PATH", "PROJECT", "PROBLEM"})
+			for i, r := range cfg.PolicyRules {
+				path := r.Path
+				if r.OutsideProject {
+					path = strings.TrimSpace(path + " (outside project)")
+				}
+				problem := ""
+				if err := validatePolicyRule(r); err != nil {
+					problem = err.Error()
+				}
+				tw.AppendRow(prettytable.Row{i + 1, policyRuleLabel(i, r), r.Decision, r.Tool, r.Command, path, r.Project, problem})
+			}
+			tw.Render()
+			fmt.Fprintf(cmd.OutOrStdout(), "Audit log: %v\n", cfg.PolicyAudit)
+			return nil
+		},
+	})
+
+	var (
+		testTool    string
+		testCommand string
+		testPath    string
+		testCWD     string
+		testProject string
+	)
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Show which rule would decide a tool call",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := loadConfig()
+			cwd := testCWD
+			if cwd == "" {
+				cwd, _ = os.Getwd()
+			}
+			in := policyInput{CWD: cwd, Tool: testTool, Command: testCommand, Path: testPath, Project: testProject}
+			if in.Project == "" {
+				in.Project = baseName(cwd)
+			}
+			res := evaluatePolicy(cfg.redaction(), cfg.PolicyRules, in)
+			if res.Decision == policyPass {
+				fmt.Fprintln(cmd.OutOrStdout(), "pass (no rule matched)")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s (%s): %s\n", res.Decision, res.Rule, res.Reason)
+			return nil
+		},
+	}
+	testCmd.Flags().StringVar(&testTool, "tool", "Bash", "Tool name (e.g. Bash, Edit, Write)")
+	testCmd.Flags().StringVar(&testCommand, "command", "", "Bash command")
+	testCmd.Flags().StringVar(&testPath, "path", "", "Target file path")
+	testCmd.Flags().StringVar(&testCWD, "cwd", "", "Session working directory (default: current directory)")
+	testCmd.Flags().StringVar(&testProject, "project", "", "Project name (default: base name of cwd)")
+	cmd.AddCommand(testCmd)
+
+	var (
+		auditLimit int
+		auditJSON  bool
+	)
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show recent policy decisions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := readPolicyAudit(auditLimit)
+			if err != nil {
+				return err
+			}
+			if auditJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+			if len(entries) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No policy decisions recorded.")
+				return nil
+			}
+			tw := prettytable.NewWriter()
+			tw.SetOutputMirror(cmd.OutOrStdout())
+			tw.SetStyle(prettytable.StyleLight)
+			tw.Style().Options.SeparateRows = false
+			tw.AppendHeader(prettytable.Row{"TIME", "DECISION", "RULE", "PROJECT", "TOOL", "TARGET"})
+			for _, e := range entries {
+				target := e.Command
+				if target == "" {
+					target = e.Path
+				}
+				tw.AppendRow(prettytable.Row{
+					e.TS.In(time.Local).Format("01-02 15:04:05"),
+					e.Decision,
+					e.Rule,
+					e.Project,
+					e.Tool,
+					truncateString(target, 60),
+				})
+			}
+			tw.Render()
+			return nil
+		},
+	}
+	auditCmd.Flags().IntVarP(&auditLimit, "limit", "n", 50, "Number of most recent entries to show")
+	auditCmd.Flags().BoolVar(&auditJSON, "json", false, "Output JSON")
+	cmd.AddCommand(auditCmd)
+
+	return cmd
+}
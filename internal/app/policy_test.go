@@ -0,0 +1,175 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	rules := []PolicyRule{
+		{Name: "no-force-push", Tool: "Bash", Command: `git\s+push\s+.*(--force|-f\b)`, Decision: "deny", Reason: "force push is not allowed"},
+		{Name: "outside-repo", Tool: "Edit|Write", OutsideProject: true, Decision: "ask"},
+		{Name: "env-files", Path: "**/.env*", Decision: "deny"},
+		{Name: "alpha-tests", Tool: "Bash", Command: `^go test`, Project: "alpha", Decision: "allow"},
+		{Name: "broken", Tool: "Grep", Command: "(", Decision: "deny"},
+	}
+
+	cases := []struct {
+		name string
+		in   policyInput
+		want string
+		rule string
+	}{
+		{"force push", policyInput{Tool: "Bash", Command: "git push origin main --force"}, policyDeny, "no-force-push"},
+		{"plain push", policyInput{Tool: "Bash", Command: "git push origin main"}, policyPass, ""},
+		{"write outside", policyInput{Tool: "Write", CWD: "/work/alpha", Path: "/etc/hosts"}, policyAsk, "outside-repo"},
+		{"write relative escape", policyInput{Tool: "Edit", CWD: "/work/alpha", Path: "../beta/main.go"}, policyAsk, "outside-repo"},
+		{"write inside", policyInput{Tool: "Write", CWD: "/work/alpha", Path: "cmd/main.go"}, policyPass, ""},
+		{"env file", policyInput{Tool: "Read", CWD: "/work/alpha", Path: "/work/alpha/config/.env.local"}, policyDeny, "env-files"},
+		{"env file at root", policyInput{Tool: "Read", CWD: "/work/alpha", Path: ".env"}, policyDeny, "env-files"},
+		{"project allow", policyInput{Tool: "Bash", Command: "go test ./...", Project: "alpha"}, policyAllow, "alpha-tests"},
+		{"other project", policyInput{Tool: "Bash", Command: "go test ./...", Project: "beta"}, policyPass, ""},
+		{"invalid rule asks", policyInput{Tool: "Grep", CWD: "/work/alpha"}, policyAsk, "broken"},
+	}
+	for _, tc := range cases {
+		got := evaluatePolicy(defaultConfig().redaction(), rules, tc.in)
+		if got.Decision != tc.want || got.Rule != tc.rule {
+			t.Fatalf("%s: expected %s/%s, got %s/%s", tc.name, tc.want, tc.rule, got.Decision, got.Rule)
+		}
+	}
+	if err := validatePolicyRule(rules[len(rules)-1]); err == nil {
+		t.Fatalf("expected invalid regex to be reported")
+	}
+}
+
+func TestPolicyProjectIgnoresCase(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	cf := ConfigFile{Policy: &PolicyConfigFile{Rules: []PolicyRule{
+		{Name: "broken", Tool: "Grep", Command: "(", Project: "MyRepo", Decision: "deny"},
+		{Name: "myrepo-tests", Tool: "Bash", Command: `^go test`, Project: " MyRepo ", Decision: "allow"},
+	}}}
+	b, _ := json.Marshal(cf)
+	if err := os.WriteFile(filepath.Join(root, "config.json"), b, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg := loadConfig()
+	if got := evaluatePolicy(cfg.redaction(), cfg.PolicyRules, policyInput{Tool: "Bash", Command: "go test ./...", Project: "MyRepo"}); got.Decision != policyAllow {
+		t.Fatalf("a mixed-case project rule should match, got %+v", got)
+	}
+	if got := evaluatePolicy(cfg.redaction(), cfg.PolicyRules, policyInput{Tool: "Grep", Project: "myrepo"}); got.Decision != policyAsk || got.Rule != "broken" {
+		t.Fatalf("an invalid mixed-case project rule should fail closed, got %+v", got)
+	}
+}
+
+func TestClaudeHookPolicyOutput(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	cf := ConfigFile{Policy: &PolicyConfigFile{Rules: []PolicyRule{
+		{Name: "no-force-push", Tool: "Bash", Command: `--force`, Decision: "deny", Reason: "no force pushes"},
+	}}}
+	b, _ := json.Marshal(cf)
+	if err := os.WriteFile(filepath.Join(root, "config.json"), b, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	hook := func(command string) []byte {
+		payload, _ := json.Marshal(map[string]any{
+			"hook_event_name": "PreToolUse",
+			"session_id":      "sess-1",
+			"cwd":             "/tmp/alpha",
+			"tool_name":       "Bash",
+			"tool_input":      map[string]any{"command": command},
+		})
+		var out bytes.Buffer
+		if err := ingestClaudeHook(bytes.NewReader(payload), &out); err != nil {
+			t.Fatalf("ingestClaudeHook error: %v", err)
+		}
+		return out.Bytes()
+	}
+
+	out := hook("git push --force")
+	var resp struct {
+		HookSpecificOutput struct {
+			HookEventName            string `json:"hookEventName"`
+			PermissionDecision       string `json:"permissionDecision"`
+			PermissionDecisionReason string `json:"permissionDecisionReason"`
+		} `json:"hookSpecificOutput"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("decode hook output %q: %v", out, err)
+	}
+	if resp.HookSpecificOutput.HookEventName != "PreToolUse" || resp.HookSpecificOutput.PermissionDecision != "deny" {
+		t.Fatalf("unexpected hook output: %+v", resp)
+	}
+	if resp.HookSpecificOutput.PermissionDecisionReason != "no force pushes" {
+		t.Fatalf("unexpected reason: %q", resp.HookSpecificOutput.PermissionDecisionReason)
+	}
+
+	if out := hook("git status"); len(out) != 0 {
+		t.Fatalf("expected no output without a matching rule, got %q", out)
+	}
+
+	entries, err := readPolicyAudit(0)
+	if err != nil {
+		t.Fatalf("readPolicyAudit error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Decision != policyDeny || entries[1].Decision != policyPass {
+		t.Fatalf("unexpected audit entries: %+v", entries)
+	}
+}
+
+func TestInstallWiresPreToolUseForPolicyRules(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+	if err := ensureAppDirs(); err != nil {
+		t.Fatalf("ensureAppDirs error: %v", err)
+	}
+	settingsPath := userClaudeSettingsPath()
+	target := claudeTarget{Scope: claudeScopeUser, Path: settingsPath}
+
+	preToolUse := func() (string, doctorCheck) {
+		t.Helper()
+		if err := installClaude(target, "/bin/true", false, false); err != nil {
+			t.Fatalf("installClaude error: %v", err)
+		}
+		b, err := os.ReadFile(settingsPath)
+		if err != nil {
+			t.Fatalf("read settings: %v", err)
+		}
+		var settings map[string]any
+		if err := json.Unmarshal(b, &settings); err != nil {
+			t.Fatalf("parse settings: %v", err)
+		}
+		c, _ := findCheck(runDoctorChecks(), "claude.hook.PreToolUse")
+		return aistatHookCommand(settings, "PreToolUse"), c
+	}
+
+	// No rules: tool calls do not wait for aistat
+	cmd, c := preToolUse()
+	if cmd == "" || strings.HasSuffix(cmd, " --sync") || c.Severity != severityOK {
+		t.Fatalf("expected a detached PreToolUse hook, got %q (%+v)", cmd, c)
+	}
+
+	cf := ConfigFile{Policy: &PolicyConfigFile{Rules: []PolicyRule{{Tool: "Bash", Command: "rm -rf", Decision: "deny"}}}}
+	b, _ := json.Marshal(cf)
+	if err := os.WriteFile(filepath.Join(root, "aistat", "config.json"), b, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if c, _ := findCheck(runDoctorChecks(), "claude.hook.PreToolUse"); c.Severity != severityWarn {
+		t.Fatalf("expected a warning while rules are not enforced, got %+v", c)
+	}
+	cmd, c = preToolUse()
+	if !strings.HasSuffix(cmd, " --sync") || c.Severity != severityOK {
+		t.Fatalf("expected a synchronous PreToolUse hook, got %q (%+v)", cmd, c)
+	}
+}
@@ -0,0 +1,401 @@
+package app
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// -------------------------
+// Redaction
+// -------------------------
+
+// Redaction modes
+const (
+	redactKeep  = "keep"
+	redactShort = "short"
+	redactBase  = "base"
+	redactHash  = "hash"
+	redactScrub = "scrub"
+	redactHide  = "hide"
+)
+
+// redactMode normalizes a configured mode, falling back to def.
+func redactMode(v, def string) string {
+	if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+		return v
+	}
+	return def
+}
+
+// secretPatterns find credentials in message text. Only the match is
+// replaced; key=value secrets keep their key.
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:.*?)(?:-----END [A-Z ]*PRIVATE KEY-----|$)`), "<private-key>"},
+	{regexp.MustCompile(`(?i)\b([A-Za-z0-9_.-]*(?:api[_-]?key|secret|token|passw(?:or)?d|pwd|access[_-]?key|auth[_-]?key|credentials?))(["']?\s*[:=]\s*["']?)[^\s"',;&<]{4,}`), "$1$2<secret>"},
+	{regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`), "<api-key>"},
+	{regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), "<api-key>"},
+	{regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`), "<api-key>"},
+	{regexp.MustCompile(`\b[rsp]k_(?:live|test)_[0-9A-Za-z]{16,}`), "<api-key>"},
+	{regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{30,}|github_pat_[A-Za-z0-9_]{20,}|glpat-[A-Za-z0-9_-]{20,}|xox[abposr]-[A-Za-z0-9-]{10,})`), "<token>"},
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`), "<token>"},
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/-]{12,}=*`), "$1 <token>"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "<email>"},
+}
+
+var (
+	urlRe         = regexp.MustCompile("\\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\\s<>\"'`)\\]]+")
+	urlUserinfoRe = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*://)[^/@\s]+@`)
+)
+
+// privateHostSuffixes are hosts (and their subdomains) that only resolve
+// on a private network.
+var privateHostSuffixes = []string{"localhost", "local", "internal", "intranet", "corp", "lan", "home.arpa"}
+
+// redactor applies the redaction config. Pseudonyms are salted HMACs, so a
+// project or session keeps its pseudonym between runs without revealing the
+// name behind it.
+type redactor struct {
+	projects, ids, paths, messages string
+
+	aliases      map[string]string // lower-case project name -> alias
+	aliasRe      *regexp.Regexp    // alias names as words in messages
+	rules        []RedactionPathRule
+	rulesRe      []*regexp.Regexp // rule prefixes in messages
+	homeRe       *regexp.Regexp
+	patterns     []*regexp.Regexp
+	privateHosts []string
+
+	// The salt is read (or generated) on the first pseudonym, so building a
+	// redactor touches no files.
+	saltConf string
+	saltOnce sync.Once
+	salt     []byte
+}
+
+// newRedactor builds a redactor from the config; problems lists the
+// settings it ignored.
+func newRedactor(rc RedactionConfigFile) (r *redactor, problems []string) {
+	r = &redactor{
+		projects: redactMode(rc.Projects, redactKeep),
+		ids:      redactMode(rc.IDs, redactShort),
+		paths:    redactMode(rc.Paths, redactShort),
+		messages: redactMode(rc.Messages, redactScrub),
+		aliases:  map[string]string{},
+		saltConf: rc.Salt,
+	}
+	modes := []struct {
+		key     string
+		v       *string
+		def     string
+		allowed []string
+	}{
+		{"projects", &r.projects, redactKeep, []string{redactKeep, redactHash}},
+		{"ids", &r.ids, redactShort, []string{redactShort, redactHash}},
+		{"paths", &r.paths, redactShort, []string{redactShort, redactBase, redactHash}},
+		{"messages", &r.messages, redactScrub, []string{redactScrub, redactHide}},
+	}
+	for _, m := range modes {
+		if !slices.Contains(m.allowed, *m.v) {
+			problems = append(problems, fmt.Sprintf("%s %q (use %s)", m.key, *m.v, strings.Join(m.allowed, "|")))
+			*m.v = m.def
+		}
+	}
+
+	var names []string
+	for name, alias := range rc.Aliases {
+		name = strings.TrimSpace(name)
+		if name == "" || strings.TrimSpace(alias) == "" {
+			problems = append(problems, fmt.Sprintf("alias %q -> %q", name, alias))
+			continue
+		}
+		r.aliases[strings.ToLower(name)] = alias
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		// Longest first so a name does not shadow a longer one containing it
+		sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+		quoted := make([]string, len(names))
+		for i, n := range names {
+			quoted[i] = regexp.QuoteMeta(n)
+		}
+		r.aliasRe = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	}
+
+	for i, rule := range rc.PathRules {
+		prefix := strings.TrimSpace(rule.Prefix)
+		if prefix == "" {
+			problems = append(problems, fmt.Sprintf("path_rules[%d] has no prefix", i))
+			continue
+		}
+		prefix = filepath.Clean(expandHome(prefix))
+		r.rules = append(r.rules, RedactionPathRule{Prefix: prefix, Replace: rule.Replace})
+		r.rulesRe = append(r.rulesRe, pathPrefixRe(prefix))
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		r.homeRe = pathPrefixRe(filepath.Clean(home))
+	}
+
+	for _, p := range rc.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("pattern %q: %v", p, err))
+			continue
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	for _, h := range rc.PrivateHosts {
+		if h = strings.Trim(strings.ToLower(strings.TrimSpace(h)), "."); h != "" {
+			r.privateHosts = append(r.privateHosts, h)
+		}
+	}
+
+	return r, problems
+}
+
+// pathPrefixRe matches a path prefix in text, but not as the start of a
+// longer file name.
+func pathPrefixRe(prefix string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(prefix)
+	if last := prefix[len(prefix)-1]; last == '_' || last >= '0' && last <= '9' || last >= 'A' && last <= 'Z' || last >= 'a' && last <= 'z' {
+		expr += `\b`
+	}
+	return regexp.MustCompile(expr)
+}
+
+// redactSalt is the configured salt, or one generated once into the app dir
+// so pseudonyms stay the same between runs.
+func redactSalt(configured string) []byte {
+	if configured != "" {
+		return []byte(configured)
+	}
+	fresh := make([]byte, 16)
+	_, _ = rand.Read(fresh)
+	salt := []byte(hex.EncodeToString(fresh))
+
+	ad, err := appDir()
+	if err != nil || ensureAppDirs() != nil {
+		return salt // stable for this run only
+	}
+	p := filepath.Join(ad, "redact_salt")
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err == nil {
+		_, werr := f.Write(salt)
+		if cerr := f.Close(); werr != nil || cerr != nil {
+			_ = os.Remove(p)
+		}
+		return salt
+	}
+	if errors.Is(err, os.ErrExist) {
+		if b, err := os.ReadFile(p); err == nil && len(bytes.TrimSpace(b)) > 0 {
+			return bytes.TrimSpace(b)
+		}
+	}
+	return salt
+}
+
+func (r *redactor) pseudonym(kind, s string, n int) string {
+	r.saltOnce.Do(func() { r.salt = redactSalt(r.saltConf) })
+	mac := hmac.New(sha256.New, r.salt)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))[:n]
+}
+
+func (r *redactor) project(p string) string {
+	if p == "" {
+		return ""
+	}
+	if alias, ok := r.aliases[strings.ToLower(p)]; ok {
+		return alias
+	}
+	if r.projects == redactHash {
+		return "project-" + r.pseudonym("project", p, 6)
+	}
+	return p
+}
+
+func (r *redactor) id(id string) string {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ""
+	}
+	if r.ids == redactHash {
+		return "s-" + r.pseudonym("id", id, 10)
+	}
+	if len(id) <= 10 {
+		return id
+	}
+	return id[:6] + "…" + id[len(id)-3:]
+}
+
+// path applies the first matching path rule, or keeps the last components
+// with aliased project names swapped in (and every component hashed in hash
+// mode).
+func (r *redactor) path(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	p = filepath.Clean(p)
+	for _, rule := range r.rules {
+		if !pathWithin(p, rule.Prefix) {
+			continue
+		}
+		rel, err := filepath.Rel(rule.Prefix, p)
+		if err != nil || rel == "." {
+			return rule.Replace
+		}
+		return strings.TrimSuffix(rule.Replace, "/") + "/" + filepath.ToSlash(rel)
+	}
+
+	keep := 2
+	if r.paths == redactBase {
+		keep = 1
+	}
+	parts := strings.Split(p, string(os.PathSeparator))
+	short := len(parts) > keep
+	if short {
+		parts = parts[len(parts)-keep:]
+	}
+	for i, c := range parts {
+		if c == "" {
+			continue
+		}
+		if alias, ok := r.aliases[strings.ToLower(c)]; ok {
+			parts[i] = alias
+			continue
+		}
+		if r.paths == redactHash {
+			ext := filepath.Ext(c)
+			parts[i] = r.pseudonym("path", strings.TrimSuffix(c, ext), 6) + ext
+		}
+	}
+	if short {
+		return "…/" + strings.Join(parts, "/")
+	}
+	return strings.Join(parts, string(os.PathSeparator))
+}
+
+func (r *redactor) message(s string) string {
+	if s == "" {
+		return ""
+	}
+	if r.messages == redactHide {
+		return "<redacted>"
+	}
+	return r.scrub(s)
+}
+
+// scrub masks the sensitive spans of a message: credentials, emails, private
+// URLs, custom patterns, then paths and aliased project names.
+func (r *redactor) scrub(s string) string {
+	s = urlRe.ReplaceAllStringFunc(s, r.scrubURL)
+	for _, sp := range secretPatterns {
+		s = sp.re.ReplaceAllString(s, sp.repl)
+	}
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, "<redacted>")
+	}
+	for i, re := range r.rulesRe {
+		s = re.ReplaceAllLiteralString(s, r.rules[i].Replace)
+	}
+	if r.homeRe != nil {
+		s = r.homeRe.ReplaceAllLiteralString(s, "~")
+	}
+	if r.aliasRe != nil {
+		s = r.aliasRe.ReplaceAllStringFunc(s, func(name string) string {
+			return r.aliases[strings.ToLower(name)]
+		})
+	}
+	return s
+}
+
+// scrubURL hides URLs of private hosts and the credentials of any URL.
+func (r *redactor) scrubURL(raw string) string {
+	trimmed := strings.TrimRight(raw, ".,;:!?")
+	tail := raw[len(trimmed):]
+	rest := trimmed[strings.Index(trimmed, "://")+3:]
+	host := rest
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if r.privateHost(strings.Trim(host, "[]")) {
+		return "<private-url>" + tail
+	}
+	return urlUserinfoRe.ReplaceAllString(trimmed, "${1}<credentials>@") + tail
+}
+
+func (r *redactor) privateHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()
+	}
+	if !strings.Contains(host, ".") {
+		return true // intranet short name
+	}
+	for _, list := range [][]string{privateHostSuffixes, r.privateHosts} {
+		for _, s := range list {
+			if host == s || strings.HasSuffix(host, "."+s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redaction is the config's redactor; a Config not made by defaultConfig or
+// loadConfig gets one built from its Redaction.
+func (c Config) redaction() *redactor {
+	if c.redactor != nil {
+		return c.redactor
+	}
+	r, _ := newRedactor(c.Redaction)
+	return r
+}
+
+func maybeRedactPath(r *redactor, p string, redact bool) string {
+	if !redact {
+		return p
+	}
+	return r.path(p)
+}
+
+func redactIDIfNeeded(r *redactor, id string, redact bool) string {
+	if !redact {
+		return id
+	}
+	return r.id(id)
+}
+
+func redactMessageIfNeeded(r *redactor, s string, redact bool) string {
+	if !redact {
+		return s
+	}
+	return r.message(s)
+}
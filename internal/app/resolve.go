@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-func resolveSourcePath(providerFilter string, id string) (string, Provider, error) {
+func resolveSourcePath(rd *redactor, providerFilter string, id string) (string, Provider, error) {
 	if strings.TrimSpace(id) == "" {
 		return "", "", errors.New("missing session id")
 	}
@@ -23,7 +23,7 @@ func resolveSourcePath(providerFilter string, id string) (string, Provider, erro
 		if providerFilter != "" && string(r.Provider) != providerFilter {
 			continue
 		}
-		if !matchID(r.ID, id) {
+		if !matchID(rd, r.ID, id) {
 			continue
 		}
 		p := r.TranscriptPath
@@ -39,14 +39,14 @@ func resolveSourcePath(providerFilter string, id string) (string, Provider, erro
 	return "", "", errors.New("could not resolve source path (try --redact=false)")
 }
 
-func matchID(actual, query string) bool {
+func matchID(rd *redactor, actual, query string) bool {
 	if actual == query {
 		return true
 	}
 	if strings.HasPrefix(actual, query) {
 		return true
 	}
-	redacted := redactIDIfNeeded(actual, true)
+	redacted := rd.id(actual)
 	if redacted == query {
 		return true
 	}
@@ -56,7 +56,7 @@ func matchID(actual, query string) bool {
 	return false
 }
 
-func resolveRecord(providerFilter string, id string) (SessionRecord, error) {
+func resolveRecord(rd *redactor, providerFilter string, id string) (SessionRecord, error) {
 	if strings.TrimSpace(id) == "" {
 		return SessionRecord{}, errors.New("missing session id")
 	}
@@ -73,7 +73,7 @@ func resolveRecord(providerFilter string, id string) (SessionRecord, error) {
 		if providerFilter != "" && string(r.Provider) != providerFilter {
 			continue
 		}
-		if matchID(r.ID, id) {
+		if matchID(rd, r.ID, id) {
 			return r, nil
 		}
 	}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
@@ -21,7 +22,12 @@ func Run() int {
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "ingest":
-			redirectStdoutStderrToDevNull()
+			// Claude hooks read policy decisions from stdout; everything else stays silent.
+			if len(os.Args) > 2 && os.Args[2] == "claude-hook" {
+				redirectStderrToDevNull()
+			} else {
+				redirectStdoutStderrToDevNull()
+			}
 		case "statusline":
 			redirectStderrToDevNull()
 		}
@@ -47,6 +53,8 @@ func Run() int {
 		flagSortBy        string
 		flagGroupBy       string
 		flagIncludeLast   bool
+		flagResetUI       bool
+		flagAccessible    bool
 	)
 
 	rootCmd := &cobra.Command{
@@ -59,12 +67,15 @@ func Run() int {
 			if err != nil {
 				return err
 			}
+			if flagAccessible || os.Getenv("TERM") == "dumb" {
+				cfg.TUIAccessible = true
+			}
 
 			// Default behavior:
 			// - If stdout is a TTY and --no-tui not set and --json not set => TUI
 			// - Else => list once (or watch if --watch)
 			if term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) && !flagNoTUI && !flagJSON && cfg.GroupBy == "" {
-				return runTUINew(cfg)
+				return runTUINew(cfg, flagResetUI)
 			}
 			return runList(cfg, flagJSON, flagWatch)
 		},
@@ -90,9 +101,13 @@ func Run() int {
 	rootCmd.Flags().StringVar(&flagSortBy, "sort", "last_seen", "Sort by: last_seen|status|provider|cost|project")
 	rootCmd.Flags().StringVar(&flagGroupBy, "group-by", "", "Group by: provider|project|status|day|hour (non-TUI only)")
 	rootCmd.Flags().BoolVar(&flagIncludeLast, "include-last-msg", false, "Include last user/assistant messages when available")
+	rootCmd.Flags().BoolVar(&flagResetUI, "reset-ui", false, "Forget saved TUI state (pins, filters, layout) before starting")
+	rootCmd.Flags().BoolVar(&flagAccessible, "accessible", baseCfg.TUIAccessible, "Plain-text TUI for screen readers (no colors, icons or animations; on when TERM=dumb)")
 
 	// install
 	rootCmd.AddCommand(newInstallCmd())
+	// uninstall
+	rootCmd.AddCommand(newUninstallCmd())
 	// doctor
 	rootCmd.AddCommand(newDoctorCmd())
 
@@ -107,16 +122,17 @@ func Run() int {
 		Short:  "Internal: ingest Claude Code hook events",
 		Hidden: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_ = ingestClaudeHook(os.Stdin)
+			_ = ingestClaudeHook(os.Stdin, os.Stdout)
 			return nil
 		},
 	})
 	ingest.AddCommand(&cobra.Command{
 		Use:    "codex-notify",
-		Short:  "Internal: ingest Codex notify payloads",
+		Short:  "Internal: ingest Codex notify payloads (last argument or stdin)",
 		Hidden: true,
+		Args:   cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_ = ingestCodexNotify(os.Stdin)
+			_ = ingestCodexNotify(os.Stdin, args)
 			return nil
 		},
 	})
@@ -124,17 +140,16 @@ func Run() int {
 
 	// statusline (hidden)
 	rootCmd.AddCommand(&cobra.Command{
-		Use:    "statusline",
+		Use:    "statusline [settings-path]",
 		Short:  "Internal: Claude Code statusLine command (reads JSON from stdin; prints one line)",
 		Hidden: true,
+		Args:   cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			line, err := ingestClaudeStatusline(os.Stdin)
-			if err != nil {
-				// Statusline must never be noisy; fall back to empty line.
-				fmt.Println("")
-				return nil
+			settingsPath := ""
+			if len(args) > 0 {
+				settingsPath = args[0]
 			}
-			fmt.Println(line)
+			runStatusline(os.Stdin, os.Stdout, settingsPath)
 			return nil
 		},
 	})
@@ -151,10 +166,22 @@ func Run() int {
 	rootCmd.AddCommand(newSummaryCmd())
 	// projects
 	rootCmd.AddCommand(newProjectsCmd())
+	// latency
+	rootCmd.AddCommand(newLatencyCmd())
+	// wait
+	rootCmd.AddCommand(newWaitCmd())
+	// policy
+	rootCmd.AddCommand(newPolicyCmd())
+	// mcp
+	rootCmd.AddCommand(newMCPCmd())
 	// help (agent-friendly)
 	rootCmd.AddCommand(newHelpCmd())
 
 	if err := rootCmd.Execute(); err != nil {
+		var ee *exitError
+		if errors.As(err, &ee) {
+			return ee.code
+		}
 		return 1
 	}
 	return 0
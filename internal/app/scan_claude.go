@@ -2,6 +2,7 @@ package app
 
 import (
 	"encoding/json"
+	"errors"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -14,11 +15,11 @@ import (
 // -------------------------
 
 func scanClaudeTranscripts(cfg Config, now time.Time) ([]SessionRecord, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
+	dir := claudeConfigDir()
+	if dir == "" {
+		return nil, errors.New("failed to resolve Claude config directory")
 	}
-	projectsDir := filepath.Join(home, ".claude", "projects")
+	projectsDir := filepath.Join(dir, "projects")
 
 	scanWindow := cfg.ActiveWindow
 	if cfg.IncludeEnded {
@@ -56,7 +57,8 @@ func scanClaudeTranscripts(cfg Config, now time.Time) ([]SessionRecord, error) {
 			continue
 		}
 		lastSeen := st.ModTime().UTC()
-		cwd := normalizePlaceholder(scanClaudeTailForCWD(fp, cfg.TailBytesClaude))
+		tail, _ := readTailBytes(fp, cfg.TailBytesClaude)
+		cwd := normalizePlaceholder(claudeTailCWD(tail))
 
 		out = append(out, SessionRecord{
 			Provider:       ProviderClaude,
@@ -66,6 +68,7 @@ func scanClaudeTranscripts(cfg Config, now time.Time) ([]SessionRecord, error) {
 			LastSeen:       lastSeen,
 			LastEvent:      lastSeen,
 			LastEventName:  "transcript",
+			Activity:       tailEventTimes(tail, now.Add(-activityWindow)),
 			Status:         StatusUnknown,
 			StatusReason:   "observed via transcript",
 			UpdatedAt:      now,
@@ -75,11 +78,8 @@ func scanClaudeTranscripts(cfg Config, now time.Time) ([]SessionRecord, error) {
 	return out, nil
 }
 
-func scanClaudeTailForCWD(filePath string, tailBytes int) string {
-	b, err := readTailBytes(filePath, tailBytes)
-	if err != nil {
-		return ""
-	}
+// claudeTailCWD returns the most recent "cwd" in a transcript tail.
+func claudeTailCWD(b []byte) string {
 	lines := splitLines(b)
 	for i := len(lines) - 1; i >= 0; i-- {
 		line := strings.TrimSpace(lines[i])
@@ -2,6 +2,7 @@ package app
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -18,6 +19,7 @@ import (
 type SessionView struct {
 	Provider Provider
 	ID       string
+	Key      string `json:"-"` // provider:id, never redacted (for matching, not display)
 	Status   Status
 	Reason   string
 
@@ -28,17 +30,73 @@ type SessionView struct {
 	Cost    float64
 	Age     time.Duration
 
-	LastSeen time.Time
+	LastSeen    time.Time
+	StatusSince time.Time `json:"-"` // when the session entered Status (zero if unknown)
 
 	SourcePath string
 	Detail     string
 	LastUser   string
 	LastAssist string
+
+	// Turn tracking
+	TurnCount   int
+	TurnElapsed time.Duration // in-flight turn duration, 0 when idle
+	LastTurn    time.Duration
+	MeanTurn    time.Duration
+	P95Turn     time.Duration
+
+	// Gauges
+	ContextTokens int     // tokens in the context window
+	ContextWindow int     // window size (0 if unknown)
+	BurnRate      float64 // USD per hour of session time (0 if unknown)
+	Activity      []int   // events per minute, oldest first (nil when idle)
 }
 
 func gatherSessions(cfg Config) ([]SessionView, error) {
 	now := time.Now().UTC()
 
+	records, err := gatherRecords(cfg, now)
+	if err != nil {
+		return nil, err
+	}
+	return viewsFromRecords(records, cfg, now), nil
+}
+
+// viewsFromRecords builds, filters, sorts and caps views for gathered records.
+func viewsFromRecords(records []SessionRecord, cfg Config, now time.Time) []SessionView {
+	var views []SessionView
+	for _, r := range records {
+		v := makeView(r, now, cfg)
+
+		if !cfg.IncludeEnded {
+			if v.Status == StatusEnded || v.Status == StatusStale {
+				continue
+			}
+		}
+		if !matchesStatus(v.Status, cfg.StatusFilters) {
+			continue
+		}
+
+		// Active filter: unless --all, show only active-window sessions
+		if !cfg.IncludeEnded && v.Age > cfg.ActiveWindow {
+			continue
+		}
+
+		views = append(views, v)
+	}
+
+	sortSessions(views, cfg.SortBy)
+
+	if cfg.MaxSessions > 0 && len(views) > cfg.MaxSessions {
+		views = views[:cfg.MaxSessions]
+	}
+
+	return views
+}
+
+// gatherRecords drains the spool, loads stored records, merges fallback scans
+// and applies the provider/project filters.
+func gatherRecords(cfg Config, now time.Time) ([]SessionRecord, error) {
 	cleanInvalidRecords()
 
 	if cfg.ProviderFilter == "" || cfg.ProviderFilter == string(ProviderClaude) {
@@ -72,58 +130,22 @@ func gatherSessions(cfg Config) ([]SessionView, error) {
 		}
 	}
 
-	var views []SessionView
+	var out []SessionRecord
 	for _, r := range merged {
 		// Provider filter
 		if cfg.ProviderFilter != "" && string(r.Provider) != cfg.ProviderFilter {
 			continue
 		}
-		if len(cfg.ProjectFilters) > 0 && !matchesProject(projectNameForRecord(r), cfg.ProjectFilters) {
-			continue
-		}
-		v := makeView(r, now, cfg)
-
-		if !cfg.IncludeEnded {
-			if v.Status == StatusEnded || v.Status == StatusStale {
-				continue
-			}
-		}
-		if !matchesStatus(v.Status, cfg.StatusFilters) {
-			continue
-		}
-
-		// Active filter: unless --all, show only active-window sessions
-		if !cfg.IncludeEnded && v.Age > cfg.ActiveWindow {
+		if len(cfg.ProjectFilters) > 0 && !matchesProject(cfg.redaction(), projectNameForRecord(r), cfg.ProjectFilters) {
 			continue
 		}
-
-		views = append(views, v)
+		out = append(out, r)
 	}
-
-	sortSessions(views, cfg.SortBy)
-
-	if cfg.MaxSessions > 0 && len(views) > cfg.MaxSessions {
-		views = views[:cfg.MaxSessions]
-	}
-
-	return views, nil
+	return out, nil
 }
 
 func drainCodexSpool() error {
-	files, err := listSpoolFiles(ProviderCodex, "notify")
-	if err != nil {
-		return err
-	}
-	for _, p := range files {
-		b, err := os.ReadFile(p)
-		if err != nil {
-			continue
-		}
-		if err := applyCodexNotifyPatch(b); err == nil {
-			_ = os.Remove(p)
-		}
-	}
-	return nil
+	return drainSpoolKind(ProviderCodex, "notify", applyCodexNotifyPatch)
 }
 
 func applyCodexNotifyPatch(b []byte) error {
@@ -143,6 +165,10 @@ func applyCodexNotifyPatch(b []byte) error {
 	}
 
 	return updateRecord(ProviderCodex, sid, func(rec *SessionRecord) {
+		// A turn after the session was marked ended revives it.
+		if rec.EndedAt != nil && at.After(*rec.EndedAt) {
+			rec.EndedAt = nil
+		}
 		rec.LastSeen = maxTime(rec.LastSeen, at)
 		rec.LastEvent = maxTime(rec.LastEvent, at)
 		if patch.EventName != "" {
@@ -163,29 +189,24 @@ func applyCodexNotifyPatch(b []byte) error {
 		if patch.Message != "" {
 			rec.Message = patch.Message
 		}
+		if patch.LastUserText != "" {
+			rec.LastUserText = patch.LastUserText
+		}
+		if patch.LastAssistantText != "" {
+			rec.LastAssistantText = patch.LastAssistantText
+		}
 		rec.Status = StatusWaiting
 		rec.StatusReason = "turn complete"
+		recordTransition(rec, StatusWaiting, "turn complete", at)
+		// The rollout scan counts the turn; the notify only closes it.
+		closeTurn(rec, at)
 	})
 }
 func drainClaudeSpool() error {
-	applyKind := func(kind string, applyFn func([]byte) error) {
-		files, err := listSpoolFiles(ProviderClaude, kind)
-		if err != nil {
-			return
-		}
-		for _, p := range files {
-			b, err := os.ReadFile(p)
-			if err != nil {
-				continue
-			}
-			if err := applyFn(b); err == nil {
-				_ = os.Remove(p)
-			}
-		}
-	}
-	applyKind("hook", applyClaudeHookPatch)
-	applyKind("statusline", applyClaudeStatuslinePatch)
-	return nil
+	return errors.Join(
+		drainSpoolKind(ProviderClaude, "hook", applyClaudeHookPatch),
+		drainSpoolKind(ProviderClaude, "statusline", applyClaudeStatuslinePatch),
+	)
 }
 
 func applyClaudeHookPatch(b []byte) error {
@@ -203,6 +224,13 @@ func applyClaudeHookPatch(b []byte) error {
 			at = t
 		}
 	}
+	// Collapsed tool events change the status when the first of them happened
+	since := at
+	if patch.FirstAt != "" {
+		if t, err := parseRFC3339ish(patch.FirstAt); err == nil && t.Before(at) {
+			since = t
+		}
+	}
 	var endedAt *time.Time
 	if patch.EndedAt != "" {
 		if t, err := parseRFC3339ish(patch.EndedAt); err == nil {
@@ -240,6 +268,27 @@ func applyClaudeHookPatch(b []byte) error {
 			rec.EndedAt = endedAt
 		} else if patch.Status == StatusRunning {
 			rec.EndedAt = nil
+			// Activity after a permission prompt means it was answered.
+			if patch.LastNotificationType == "" {
+				rec.LastNotificationType = ""
+			}
+		}
+
+		if patch.Status != "" {
+			statusAt := since
+			if n := len(rec.Transitions); n > 0 && !statusAt.After(rec.Transitions[n-1].At) {
+				statusAt = at
+			}
+			recordTransition(rec, patch.Status, patch.StatusReason, statusAt)
+		}
+
+		switch patch.Turn {
+		case turnStart:
+			beginTurn(rec, at)
+		case turnEnd:
+			completeTurn(rec, at)
+		case turnClose:
+			closeTurn(rec, at)
 		}
 	})
 }
@@ -373,9 +422,14 @@ func mergeInto(dst map[string]SessionRecord, src SessionRecord) {
 			cur.StatusReason = src.StatusReason
 		}
 	}
-	// Preserve endedAt if we have one
+	// Preserve endedAt if we have one, unless the scan saw newer activity
 	if cur.EndedAt == nil && src.EndedAt != nil {
 		cur.EndedAt = src.EndedAt
+	} else if cur.EndedAt != nil && src.EndedAt == nil && src.LastSeen.After(*cur.EndedAt) {
+		cur.EndedAt = nil
+		if cur.Status == StatusEnded {
+			cur.Status, cur.StatusReason = src.Status, src.StatusReason
+		}
 	}
 	// Prefer higher-fidelity Claude numbers (non-zero)
 	if src.CostUSD != 0 {
@@ -394,6 +448,15 @@ func mergeInto(dst map[string]SessionRecord, src SessionRecord) {
 		cur.CurrentCacheReadTokens = src.CurrentCacheReadTokens
 	}
 
+	// Codex rollouts report context usage without a cost
+	if src.CostUSD == 0 && src.ContextWindowSize != 0 {
+		cur.ContextWindowSize = src.ContextWindowSize
+		cur.CurrentInputTokens = src.CurrentInputTokens
+	}
+	if len(src.Activity) > 0 {
+		cur.Activity = src.Activity
+	}
+
 	// Codex notify metadata
 	if cur.Title == "" {
 		cur.Title = src.Title
@@ -413,6 +476,10 @@ func mergeInto(dst map[string]SessionRecord, src SessionRecord) {
 	if src.LastAssistantText != "" {
 		cur.LastAssistantText = src.LastAssistantText
 	}
+	mergeTurns(&cur, src)
+	if len(src.Transitions) > 0 {
+		cur.Transitions = normalizeTransitions(append(append([]StatusTransition(nil), cur.Transitions...), src.Transitions...))
+	}
 
 	dst[k] = cur
 }
@@ -449,28 +516,46 @@ func makeView(r SessionRecord, now time.Time, cfg Config) SessionView {
 		source = r.RolloutPath
 	}
 
+	rd := cfg.redaction()
 	displayID := r.ID
 	if cfg.Redact {
-		displayID = redactIDIfNeeded(r.ID, true)
-		project = redactProject(project)
-		dir = shortenPath(dir, 2)
-		source = redactPath(source)
+		displayID = rd.id(r.ID)
+		project = rd.project(project)
+		dir = rd.path(dir)
+		source = rd.path(source)
 	}
 
 	detail := buildDetail(r, status, reason, cfg, now)
 	lastUser := ""
 	lastAssistant := ""
 	if cfg.IncludeLastMsg {
-		lastUser = redactMessageIfNeeded(r.LastUserText, cfg.Redact)
-		lastAssistant = redactMessageIfNeeded(r.LastAssistantText, cfg.Redact)
+		lastUser = redactMessageIfNeeded(rd, r.LastUserText, cfg.Redact)
+		lastAssistant = redactMessageIfNeeded(rd, r.LastAssistantText, cfg.Redact)
 	}
 
 	// Get git branch for worktree identification
 	branch := getBranchName(normalizePlaceholder(r.CWD))
 
+	var turnElapsed time.Duration
+	if turnInFlight(r) && status == StatusRunning {
+		turnElapsed = now.Sub(*r.TurnStartedAt)
+	}
+	lastTurn, meanTurn, p95Turn := turnStats(r)
+
+	var statusSince time.Time
+	if n := len(r.Transitions); n > 0 && r.Transitions[n-1].Status == status {
+		statusSince = r.Transitions[n-1].At
+	}
+
+	var burn float64
+	if r.CostUSD > 0 && r.DurationMS >= time.Minute.Milliseconds() {
+		burn = r.CostUSD / (time.Duration(r.DurationMS) * time.Millisecond).Hours()
+	}
+
 	return SessionView{
 		Provider:   r.Provider,
 		ID:         displayID,
+		Key:        keyFor(r.Provider, r.ID),
 		Status:     status,
 		Reason:     reason,
 		Project:    project,
@@ -481,9 +566,22 @@ func makeView(r SessionRecord, now time.Time, cfg Config) SessionView {
 		Age:        age,
 		LastSeen:   last,
 		SourcePath: source,
-		Detail:     detail,
-		LastUser:   lastUser,
-		LastAssist: lastAssistant,
+
+		StatusSince: statusSince,
+		Detail:      detail,
+		LastUser:    lastUser,
+		LastAssist:  lastAssistant,
+
+		TurnCount:   r.TurnCount,
+		TurnElapsed: turnElapsed,
+		LastTurn:    lastTurn,
+		MeanTurn:    meanTurn,
+		P95Turn:     p95Turn,
+
+		ContextTokens: r.CurrentInputTokens + r.CurrentCacheCreateTokens + r.CurrentCacheReadTokens,
+		ContextWindow: r.ContextWindowSize,
+		BurnRate:      burn,
+		Activity:      activitySeries(r.Activity, now),
 	}
 }
 
@@ -504,6 +602,11 @@ func deriveStatus(r SessionRecord, now time.Time, cfg Config) (Status, string) {
 		return StatusApproval, "awaiting approval"
 	}
 
+	// A turn in flight stays running no matter how quiet it is.
+	if turnInFlight(r) {
+		return StatusRunning, "running for " + fmtElapsed(now.Sub(*r.TurnStartedAt))
+	}
+
 	// Running heuristic: very recent activity.
 	if age <= cfg.RunningWindow {
 		return StatusRunning, "running"
@@ -552,10 +655,8 @@ func buildDetail(r SessionRecord, status Status, reason string, cfg Config, now
 	var b strings.Builder
 	fmt.Fprintf(&b, "Status: %s — %s\n", status, reason)
 	provider := string(r.Provider)
-	sessionID := r.ID
-	if cfg.Redact {
-		sessionID = redactIDIfNeeded(sessionID, true)
-	}
+	rd := cfg.redaction()
+	sessionID := redactIDIfNeeded(rd, r.ID, cfg.Redact)
 	if provider != "" {
 		fmt.Fprintf(&b, "Provider: %s\n", provider)
 	}
@@ -571,6 +672,17 @@ func buildDetail(r SessionRecord, status Status, reason string, cfg Config, now
 	if !r.UpdatedAt.IsZero() {
 		fmt.Fprintf(&b, "Updated at: %s\n", r.UpdatedAt.In(time.Local).Format("2006-01-02 15:04:05"))
 	}
+	if turnInFlight(r) && status == StatusRunning {
+		fmt.Fprintf(&b, "Turn: in flight for %s\n", fmtElapsed(now.Sub(*r.TurnStartedAt)))
+	}
+	if r.TurnCount > 0 {
+		last, mean, p95 := turnStats(r)
+		if len(r.TurnDurationsMS) > 0 {
+			fmt.Fprintf(&b, "Turns: %d (last %s, mean %s, p95 %s)\n", r.TurnCount, fmtElapsed(last), fmtElapsed(mean), fmtElapsed(p95))
+		} else {
+			fmt.Fprintf(&b, "Turns: %d\n", r.TurnCount)
+		}
+	}
 	projectDir := normalizePlaceholder(r.ProjectDir)
 	cwd := normalizePlaceholder(r.CWD)
 	modelID := normalizePlaceholder(r.ModelID)
@@ -587,10 +699,10 @@ func buildDetail(r SessionRecord, status Status, reason string, cfg Config, now
 			fmt.Fprintf(&b, "Model: %s\n", safe(modelDisplay, modelID))
 		}
 		if projectDir != "" {
-			fmt.Fprintf(&b, "Project: %s\n", maybeRedactPath(projectDir, cfg.Redact))
+			fmt.Fprintf(&b, "Project: %s\n", maybeRedactPath(rd, projectDir, cfg.Redact))
 		}
 		if cwd != "" {
-			fmt.Fprintf(&b, "CWD: %s\n", maybeRedactPath(cwd, cfg.Redact))
+			fmt.Fprintf(&b, "CWD: %s\n", maybeRedactPath(rd, cwd, cfg.Redact))
 		}
 		if r.CostUSD != 0 {
 			fmt.Fprintf(&b, "Cost: $%.4f\n", r.CostUSD)
@@ -601,14 +713,14 @@ func buildDetail(r SessionRecord, status Status, reason string, cfg Config, now
 			fmt.Fprintf(&b, "Context: %d/%d (%.0f%%)\n", cur, r.ContextWindowSize, pct)
 		}
 		if transcriptPath != "" {
-			fmt.Fprintf(&b, "Transcript: %s\n", maybeRedactPath(transcriptPath, cfg.Redact))
+			fmt.Fprintf(&b, "Transcript: %s\n", maybeRedactPath(rd, transcriptPath, cfg.Redact))
 		}
 	} else {
 		if modelID != "" {
 			fmt.Fprintf(&b, "Model: %s\n", modelID)
 		}
 		if cwd != "" {
-			fmt.Fprintf(&b, "CWD: %s\n", maybeRedactPath(cwd, cfg.Redact))
+			fmt.Fprintf(&b, "CWD: %s\n", maybeRedactPath(rd, cwd, cfg.Redact))
 		}
 		if approvalPolicy != "" {
 			fmt.Fprintf(&b, "Approval policy: %s\n", approvalPolicy)
@@ -617,20 +729,20 @@ func buildDetail(r SessionRecord, status Status, reason string, cfg Config, now
 			fmt.Fprintf(&b, "Thread/Turn: %s / %s\n", safe(threadID, "n/a"), safe(turnID, "n/a"))
 		}
 		if title != "" {
-			fmt.Fprintf(&b, "Title: %s\n", redactMessageIfNeeded(title, cfg.Redact))
+			fmt.Fprintf(&b, "Title: %s\n", redactMessageIfNeeded(rd, title, cfg.Redact))
 		}
 		if r.Message != "" {
-			fmt.Fprintf(&b, "Message: %s\n", redactMessageIfNeeded(r.Message, cfg.Redact))
+			fmt.Fprintf(&b, "Message: %s\n", redactMessageIfNeeded(rd, r.Message, cfg.Redact))
 		}
 		if rolloutPath != "" {
-			fmt.Fprintf(&b, "Rollout: %s\n", maybeRedactPath(rolloutPath, cfg.Redact))
+			fmt.Fprintf(&b, "Rollout: %s\n", maybeRedactPath(rd, rolloutPath, cfg.Redact))
 		}
 		if cfg.IncludeLastMsg {
 			if r.LastUserText != "" {
-				fmt.Fprintf(&b, "Last user: %s\n", redactMessageIfNeeded(r.LastUserText, cfg.Redact))
+				fmt.Fprintf(&b, "Last user: %s\n", redactMessageIfNeeded(rd, r.LastUserText, cfg.Redact))
 			}
 			if r.LastAssistantText != "" {
-				fmt.Fprintf(&b, "Last assistant: %s\n", redactMessageIfNeeded(r.LastAssistantText, cfg.Redact))
+				fmt.Fprintf(&b, "Last assistant: %s\n", redactMessageIfNeeded(rd, r.LastAssistantText, cfg.Redact))
 			}
 		}
 	}
@@ -641,13 +753,15 @@ func buildDetail(r SessionRecord, status Status, reason string, cfg Config, now
 	return b.String()
 }
 
-func matchesProject(project string, filters []string) bool {
+func matchesProject(rd *redactor, project string, filters []string) bool {
 	if project == "" {
 		return false
 	}
 	p := strings.ToLower(project)
+	// Also match the name redaction shows, so a listed project can be filtered on
+	shown := strings.ToLower(rd.project(project))
 	for _, f := range filters {
-		if f == p {
+		if f == p || f == shown {
 			return true
 		}
 	}
@@ -31,12 +31,12 @@ func newShowCmd() *cobra.Command {
 				return fmt.Errorf("missing session id")
 			}
 
-			rec, err := resolveRecord(provider, id)
+			cfg := loadConfig()
+			rec, err := resolveRecord(cfg.redaction(), provider, id)
 			if err != nil {
 				return err
 			}
 
-			cfg := loadConfig()
 			cfg.IncludeEnded = true
 			cfg.IncludeLastMsg = includeLastMsg
 			cfg.Redact = redact
@@ -0,0 +1,202 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// -------------------------
+// Claude statusLine chaining
+// -------------------------
+
+// statuslineChainTimeout bounds how long the chained statusLine command may
+// take before aistat prints its own line instead.
+const statuslineChainTimeout = 3 * time.Second
+
+// statuslineChainWaitDelay bounds how long aistat waits for the chained
+// command's output to close once it has exited or timed out (a background
+// job it started may hold it open).
+const statuslineChainWaitDelay = 500 * time.Millisecond
+
+// The statusLine wrapper exports TERM=dumb and NO_COLOR=1 for aistat and
+// keeps the values Claude Code gave it in these, for the chained command.
+const (
+	statuslineOrigTermEnv    = "AISTAT_ORIG_TERM"
+	statuslineOrigNoColorEnv = "AISTAT_ORIG_NO_COLOR"
+)
+
+// claudeStatuslineChain is the statusLine aistat's wrapper replaced: its
+// command runs on every update with the same stdin JSON and its output is
+// what Claude Code shows.
+type claudeStatuslineChain struct {
+	Command    string          `json:"command"`
+	StatusLine json.RawMessage `json:"status_line,omitempty"` // the previous setting, as it was
+}
+
+func claudeStatuslineChainPath() (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "claude_statusline_chain.json"), nil
+}
+
+// loadClaudeStatuslineChains returns the chained statusLines by the settings
+// file they were set in.
+func loadClaudeStatuslineChains() (map[string]claudeStatuslineChain, error) {
+	chains := map[string]claudeStatuslineChain{}
+	p, err := claudeStatuslineChainPath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return chains, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &chains); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+	return chains, nil
+}
+
+// loadClaudeStatuslineChain returns the statusLine chained in a settings file
+// (zero when none).
+func loadClaudeStatuslineChain(settingsPath string) (claudeStatuslineChain, error) {
+	chains, err := loadClaudeStatuslineChains()
+	if err != nil {
+		return claudeStatuslineChain{}, err
+	}
+	return chains[settingsPath], nil
+}
+
+// saveClaudeStatuslineChain records the statusLine chained in a settings file;
+// an empty command removes it.
+func saveClaudeStatuslineChain(settingsPath string, chain claudeStatuslineChain) error {
+	chains, err := loadClaudeStatuslineChains()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(chain.Command) == "" {
+		delete(chains, settingsPath)
+	} else {
+		chains[settingsPath] = chain
+	}
+	p, err := claudeStatuslineChainPath()
+	if err != nil {
+		return err
+	}
+	if len(chains) == 0 {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return writeJSONAtomic(p, chains)
+}
+
+// isAistatStatusline reports whether a statusLine command is aistat's own
+// (the wrapper, or a legacy direct `aistat statusline` call).
+func isAistatStatusline(command string) bool {
+	return strings.Contains(command, "aistat-claude-statusline") ||
+		strings.Contains(command, "aistat") && strings.HasSuffix(strings.TrimSpace(command), " statusline")
+}
+
+// runStatusline is the statusLine command: it records the metrics and prints
+// the output of the command chained in settingsPath ("" for the user
+// settings) when one is set (with aistat's segment appended when
+// statusline_segment is on), else aistat's own line.
+func runStatusline(r io.Reader, w io.Writer, settingsPath string) {
+	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprintln(w, "")
+		return
+	}
+	in, err := io.ReadAll(io.LimitReader(r, 10*1024*1024))
+	if err != nil {
+		// Statusline must never be noisy; fall back to empty line.
+		fmt.Fprintln(w, "")
+		return
+	}
+	own, _ := ingestClaudeStatusline(bytes.NewReader(in))
+
+	if settingsPath == "" {
+		settingsPath = userClaudeSettingsPath()
+	}
+	chain, _ := loadClaudeStatuslineChain(settingsPath)
+	if chain.Command == "" || os.Getenv(selftestEnv) != "" {
+		fmt.Fprintln(w, own)
+		return
+	}
+	prev, err := runChainedStatusline(chain.Command, in)
+	switch {
+	case err != nil || prev == "":
+		fmt.Fprintln(w, own)
+	case loadConfig().StatuslineSegment && own != "":
+		fmt.Fprintln(w, prev+"  "+own)
+	default:
+		fmt.Fprintln(w, prev)
+	}
+}
+
+// runChainedStatusline runs a statusLine command the way Claude Code does
+// (through the shell, JSON on stdin) and returns its output without the
+// trailing newline.
+func runChainedStatusline(command string, in []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), statuslineChainTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Env = chainedStatuslineEnv(os.Environ())
+	cmd.WaitDelay = statuslineChainWaitDelay
+	out, err := cmd.Output()
+	// A command that exited fine but left a job holding stdout still counts
+	if err != nil && !(errors.Is(err, exec.ErrWaitDelay) && ctx.Err() == nil) {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// chainedStatuslineEnv restores the TERM and NO_COLOR the wrapper replaced,
+// so the chained command renders as it would without aistat. Without the
+// wrapper's saved values the environment is passed on as is.
+func chainedStatuslineEnv(env []string) []string {
+	vals := map[string]string{}
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			vals[k] = v
+		}
+	}
+	origTerm, ok := vals[statuslineOrigTermEnv]
+	if !ok {
+		return env
+	}
+	origNoColor := vals[statuslineOrigNoColorEnv]
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		switch k, _, _ := strings.Cut(kv, "="); k {
+		case "TERM", "NO_COLOR", statuslineOrigTermEnv, statuslineOrigNoColorEnv:
+			continue
+		}
+		out = append(out, kv)
+	}
+	if origTerm != "" {
+		out = append(out, "TERM="+origTerm)
+	}
+	if origNoColor != "" {
+		out = append(out, "NO_COLOR="+origNoColor)
+	}
+	return out
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunStatuslineChained(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+	t.Setenv("CLAUDE_CONFIG_DIR", filepath.Join(root, "claude"))
+	settingsPath := userClaudeSettingsPath()
+
+	input := `{"session_id":"sess-chain","model":{"display_name":"Opus"},"cost":{"total_cost_usd":1.25}}`
+	run := func() string {
+		var out bytes.Buffer
+		runStatusline(strings.NewReader(input), &out, "")
+		return strings.TrimRight(out.String(), "\n")
+	}
+
+	own := run()
+	if own == "" {
+		t.Fatalf("expected aistat's own line without a chain")
+	}
+
+	// The chained command sees the same JSON on stdin
+	if err := saveClaudeStatuslineChain(settingsPath, claudeStatuslineChain{Command: `grep -q sess-chain && echo mine`}); err != nil {
+		t.Fatalf("save chain: %v", err)
+	}
+	if got := run(); got != "mine" {
+		t.Fatalf("chained output = %q, want %q", got, "mine")
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "config.json"), []byte(`{"statusline_segment": true}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if got := run(); got != "mine  "+own {
+		t.Fatalf("segment output = %q, want %q", got, "mine  "+own)
+	}
+
+	// A failing command falls back to aistat's line
+	if err := saveClaudeStatuslineChain(settingsPath, claudeStatuslineChain{Command: "exit 1"}); err != nil {
+		t.Fatalf("save chain: %v", err)
+	}
+	if got := run(); got != own {
+		t.Fatalf("fallback output = %q, want %q", got, own)
+	}
+
+	if err := saveClaudeStatuslineChain(settingsPath, claudeStatuslineChain{}); err != nil {
+		t.Fatalf("clear chain: %v", err)
+	}
+	if p, _ := claudeStatuslineChainPath(); existsStr(p) == "ok" {
+		t.Fatalf("empty chain should remove %s", p)
+	}
+}
+
+func TestRunChainedStatuslineRestoresTermAndReturnsPromptly(t *testing.T) {
+	t.Setenv("TERM", "dumb")
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv(statuslineOrigTermEnv, "xterm-256color")
+	t.Setenv(statuslineOrigNoColorEnv, "")
+	got, err := runChainedStatusline(`printf '%s|%s' "$TERM" "${NO_COLOR-unset}"`, nil)
+	if err != nil || got != "xterm-256color|unset" {
+		t.Fatalf("chained env = %q (%v), want the terminal Claude Code gave the wrapper", got, err)
+	}
+
+	// A background job holding stdout open does not hold up the statusline
+	start := time.Now()
+	got, err = runChainedStatusline("echo mine; (sleep 10 &)", nil)
+	if err != nil || got != "mine" {
+		t.Fatalf("output = %q (%v), want %q", got, err, "mine")
+	}
+	if d := time.Since(start); d > statuslineChainTimeout {
+		t.Fatalf("chained statusline took %s", d)
+	}
+}
+
+func TestInstallClaudeChainsExistingStatusline(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+
+	settingsPath := filepath.Join(root, ".claude", "settings.json")
+	target := claudeTarget{Scope: claudeScopeUser, Path: settingsPath}
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"statusLine":{"type":"command","command":"~/bin/my-status","padding":2}}`), 0o600); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+
+	for i := 0; i < 2; i++ { // a second install keeps the chain
+		if err := installClaude(target, "/bin/true", false, false); err != nil {
+			t.Fatalf("installClaude error: %v", err)
+		}
+	}
+
+	b, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	var settings struct {
+		StatusLine map[string]any `json:"statusLine"`
+	}
+	if err := json.Unmarshal(b, &settings); err != nil {
+		t.Fatalf("parse settings: %v", err)
+	}
+	if cmd := asString(settings.StatusLine["command"]); !isAistatStatusline(cmd) {
+		t.Fatalf("statusLine command = %q, want aistat's wrapper", cmd)
+	}
+	if settings.StatusLine["padding"] != float64(2) {
+		t.Fatalf("padding not kept: %v", settings.StatusLine["padding"])
+	}
+	chain, err := loadClaudeStatuslineChain(settingsPath)
+	if err != nil {
+		t.Fatalf("load chain: %v", err)
+	}
+	if chain.Command != "~/bin/my-status" {
+		t.Fatalf("chain command = %q", chain.Command)
+	}
+
+	// --force over aistat's own wrapper keeps the chain
+	if err := installClaude(target, "/bin/true", true, false); err != nil {
+		t.Fatalf("installClaude --force error: %v", err)
+	}
+	if chain, _ := loadClaudeStatuslineChain(settingsPath); chain.Command != "~/bin/my-status" {
+		t.Fatalf("--force over the wrapper should keep the chain, got %q", chain.Command)
+	}
+
+	// --force over a statusLine of the user's own replaces it and drops the chain
+	if err := os.WriteFile(settingsPath, []byte(`{"statusLine":{"type":"command","command":"~/bin/other-status"}}`), 0o600); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+	if err := installClaude(target, "/bin/true", true, false); err != nil {
+		t.Fatalf("installClaude --force error: %v", err)
+	}
+	if chain, _ := loadClaudeStatuslineChain(settingsPath); chain.Command != "" {
+		t.Fatalf("--force should clear the chain, got %q", chain.Command)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
@@ -95,11 +96,30 @@ func writeSpoolBytes(provider Provider, kind, sid string, b []byte, overwrite bo
 	if overwrite {
 		final = filepath.Join(dir, safeSID+".json")
 	} else {
-		final = filepath.Join(dir, fmt.Sprintf("%s_%d_%d.json", safeSID, time.Now().UnixNano(), os.Getpid()))
+		final = uniqueSpoolPath(dir, safeSID)
 	}
 	return os.Rename(tmp.Name(), final)
 }
 
+func uniqueSpoolPath(dir, safeSID string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s_%d_%d.json", safeSID, time.Now().UnixNano(), os.Getpid()))
+}
+
+// sealSpoolOverwrite moves a session's overwrite file to a unique name, so
+// the next overwrite starts a new file instead of replacing it. The file keeps
+// its mtime, and with it its place in the drain order.
+func sealSpoolOverwrite(provider Provider, kind, sid string) error {
+	p, err := spoolPath(provider, kind, sid)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(p, uniqueSpoolPath(filepath.Dir(p), fileSafeRe.ReplaceAllString(sid, "_")))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
 func listSpoolFiles(provider Provider, kind string) ([]string, error) {
 	sd, err := spoolDir()
 	if err != nil {
@@ -113,7 +133,11 @@ func listSpoolFiles(provider Provider, kind string) ([]string, error) {
 		}
 		return nil, err
 	}
-	var out []string
+	type spoolFile struct {
+		path string
+		mod  time.Time
+	}
+	var files []spoolFile
 	for _, e := range entries {
 		if e.IsDir() {
 			continue
@@ -122,7 +146,22 @@ func listSpoolFiles(provider Provider, kind string) ([]string, error) {
 		if !strings.HasSuffix(name, ".json") {
 			continue
 		}
-		out = append(out, filepath.Join(dir, name))
+		var mod time.Time
+		if info, err := e.Info(); err == nil {
+			mod = info.ModTime()
+		}
+		files = append(files, spoolFile{path: filepath.Join(dir, name), mod: mod})
+	}
+	// Oldest first so patches are applied in the order they were written.
+	sort.SliceStable(files, func(i, j int) bool {
+		if !files[i].mod.Equal(files[j].mod) {
+			return files[i].mod.Before(files[j].mod)
+		}
+		return files[i].path < files[j].path
+	})
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		out = append(out, f.path)
 	}
 	return out, nil
 }
@@ -33,7 +33,7 @@ func newTailCmd() *cobra.Command {
 			if lines <= 0 {
 				lines = 50
 			}
-			path, _, err := resolveSourcePath(provider, id)
+			path, _, err := resolveSourcePath(loadConfig().redaction(), provider, id)
 			if err != nil {
 				return err
 			}
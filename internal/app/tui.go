@@ -598,7 +598,7 @@ func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "o":
 			if s, ok := m.selectedSession(); ok {
-				_ = openSourceForSession(s.Provider, stripANSI(s.ID))
+				_ = openSourceForSession(m.cfg.redaction(), s.Provider, stripANSI(s.ID))
 			}
 		case "s":
 			m.cycleSort()
@@ -814,7 +814,7 @@ func (m *tuiModel) executePaletteCommand(raw string) {
 		m.applyFilterAndUpdateRows()
 	case "open":
 		if s, ok := m.selectedSession(); ok {
-			_ = openSourceForSession(s.Provider, stripANSI(s.ID))
+			_ = openSourceForSession(m.cfg.redaction(), s.Provider, stripANSI(s.ID))
 			m.paletteMsg = "Opened log"
 		}
 	case "copy-id":
@@ -2323,8 +2323,8 @@ func copyToClipboard(text string) error {
 	return cmd.Wait()
 }
 
-func openSourceForSession(provider Provider, id string) error {
-	p, _, err := resolveSourcePath(string(provider), id)
+func openSourceForSession(rd *redactor, provider Provider, id string) error {
+	p, _, err := resolveSourcePath(rd, string(provider), id)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,492 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/components"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+	"github.com/vburojevic/aistat/internal/app/tui/widgets"
+)
+
+// The accessible view (--accessible, or TERM=dumb) is plain text for screen
+// readers and dumb terminals: no colors, icons, borders, spinners or cursor
+// animation. Every screen has the same sections in the same order (summary,
+// view, notice, prompt, sessions, current session, follower, keys), and
+// statuses are spelled out.
+
+// accessibleInline runs the accessible view inline instead of on the alt
+// screen: after each update it prints only the lines that changed, so a
+// screen reader announces what is new rather than rereading the screen.
+type accessibleInline struct {
+	*Model
+	printed []accessibleLine
+}
+
+func (a *accessibleInline) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	_, cmd := a.Model.Update(msg)
+	if a.width == 0 {
+		return a, cmd
+	}
+	lines := a.accessibleLines()
+	changed := changedLines(a.printed, lines)
+	a.printed = lines
+	if len(changed) == 0 {
+		return a, cmd
+	}
+	return a, tea.Sequence(tea.Println(strings.Join(changed, "\n")), cmd)
+}
+
+// View is empty: everything is printed by Update
+func (a *accessibleInline) View() string { return "" }
+
+// accessibleLine is one line of the accessible view. diff is what the inline
+// mode compares to tell whether the line changed: for session rows it is the
+// session, its status and reason, leaving out the ages that tick every
+// refresh.
+type accessibleLine struct {
+	text, diff string
+}
+
+// plainLines are lines that changed when their text did
+func plainLines(texts ...string) []accessibleLine {
+	lines := make([]accessibleLine, len(texts))
+	for i, t := range texts {
+		lines[i] = accessibleLine{text: t, diff: t}
+	}
+	return lines
+}
+
+// changedLines returns the text of the lines of next that were not on the
+// previous screen, in order; a line shown twice before counts twice.
+func changedLines(prev, next []accessibleLine) []string {
+	seen := map[string]int{}
+	for _, l := range prev {
+		seen[l.diff]++
+	}
+	var out []string
+	for _, l := range next {
+		if seen[l.diff] > 0 {
+			seen[l.diff]--
+			continue
+		}
+		out = append(out, l.text)
+	}
+	return out
+}
+
+// statusWord spells out a status
+func statusWord(s state.Status) string {
+	switch s {
+	case state.StatusRunning:
+		return "running"
+	case state.StatusWaiting:
+		return "waiting"
+	case state.StatusApproval:
+		return "needs approval"
+	case state.StatusNeedsAttn:
+		return "needs attention"
+	case state.StatusStale:
+		return "stale"
+	case state.StatusEnded:
+		return "ended"
+	default:
+		return "unknown"
+	}
+}
+
+// plainProvider names a provider
+func plainProvider(p state.Provider) string {
+	switch p {
+	case state.ProviderClaude:
+		return "Claude"
+	case state.ProviderCodex:
+		return "Codex"
+	default:
+		return widgets.Safe(string(p), "unknown")
+	}
+}
+
+// plainCount is "1 session" / "N sessions"
+func plainCount(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return widgets.FormatInt(n) + " " + noun + "s"
+}
+
+// renderAccessible renders the whole screen as plain lines
+func (m *Model) renderAccessible() string {
+	lines := m.accessibleLines()
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// accessibleLines builds the screen line by line
+func (m *Model) accessibleLines() []accessibleLine {
+	top := plainLines(m.accessibleSummary(), m.accessibleViewLine())
+	if m.notice != "" {
+		top = append(top, plainLines("Notice: "+m.notice)...)
+	}
+	if p := m.accessiblePrompt(); p != "" {
+		top = append(top, plainLines(p)...)
+	}
+
+	bottom := plainLines(m.accessibleKeys())
+	if m.ingest != "" {
+		// A healthy pipeline only reports how long ago its last event was
+		line := accessibleLine{text: "Ingest: " + strings.TrimPrefix(m.ingest, "ingest: "), diff: "Ingest"}
+		if m.ingestWarn {
+			line.text = "Ingest problem: " + strings.TrimPrefix(m.ingest, "ingest: ")
+			line.diff = line.text
+		}
+		bottom = append(bottom, line)
+	}
+	var body []accessibleLine
+	switch {
+	case m.showHelp:
+		body = plainLines(m.accessibleHelp()...)
+	case m.projectsOpen:
+		items := m.pickerItems()
+		m.projectIndex = clampIndex(m.projectIndex, len(items))
+		body = plainLines(m.accessibleProjects("Projects", items)...)
+	case m.showDashboard:
+		items := m.dashboardItems()
+		m.projectIndex = clampIndex(m.projectIndex, len(items))
+		body = plainLines(m.accessibleProjects("Active projects", items)...)
+	default:
+		var extra []accessibleLine
+		if m.showDetail {
+			extra = append(extra, plainLines(m.accessibleDetail()...)...)
+		}
+		if m.following {
+			extra = append(extra, plainLines(m.accessibleFollow()...)...)
+		}
+		// The session list keeps at least a few lines; the current session
+		// and follower give way first
+		room := m.height - len(top) - len(bottom)
+		if keep := room - 5; len(extra) > keep {
+			extra = extra[:widgets.MaxInt(keep, 0)]
+		}
+		body = append(m.accessibleList(room-len(extra)), extra...)
+	}
+
+	room := widgets.MaxInt(m.height-len(top)-len(bottom), 1)
+	if len(body) > room {
+		body = body[:room]
+	}
+	lines := append(append(top, body...), bottom...)
+	for i := range lines {
+		lines[i].text = widgets.TruncateString(lines[i].text, widgets.MaxInt(m.width, 20))
+	}
+	return lines
+}
+
+// accessibleSummary is the first line: how many sessions and their states
+func (m *Model) accessibleSummary() string {
+	if m.err != nil {
+		return "aistat: error: " + m.err.Error()
+	}
+	c := components.CountStatuses(m.filteredSessions)
+	line := fmt.Sprintf("aistat: %s shown, %d running, %d idle, %d need input.",
+		plainCount(len(m.filteredSessions), "session"), c.Running, c.Idle, c.NeedsInput)
+	if m.inbox {
+		line += " Inbox, " + plainCount(m.activeSnoozes(time.Now()), "snoozed session") + "."
+	}
+	return line
+}
+
+// accessibleViewLine describes sort, grouping, filters and selections
+func (m *Model) accessibleViewLine() string {
+	parts := []string{"sort " + m.sortBy, "group " + groupLabel(m.listGroupBy())}
+	if m.filterQuery != "" {
+		parts = append(parts, fmt.Sprintf("query %q", m.filterQuery))
+	}
+	for _, f := range []struct {
+		name   string
+		values []string
+	}{
+		{"provider", setKeys(m.filters.ProviderFilter)},
+		{"status", setKeys(m.filters.StatusFilter)},
+		{"project", setKeys(m.filters.ProjectFilter)},
+	} {
+		if len(f.values) > 0 {
+			parts = append(parts, f.name+" "+strings.Join(f.values, " or "))
+		}
+	}
+	if n := len(m.appState.SelectedIDs()); n > 0 {
+		parts = append(parts, widgets.FormatInt(n)+" selected")
+	}
+	if len(m.hidden) > 0 {
+		parts = append(parts, widgets.FormatInt(len(m.hidden))+" hidden")
+	}
+	return "View: " + strings.Join(parts, ", ") + "."
+}
+
+// setKeys returns the keys of a filter set, sorted
+func setKeys[K ~string](set map[K]bool) []string {
+	var out []string
+	for k, on := range set {
+		if on {
+			out = append(out, string(k))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// accessiblePrompt is the line for whatever is waiting for typed input
+func (m *Model) accessiblePrompt() string {
+	switch {
+	case m.confirm != nil:
+		return "Confirm: " + m.confirm.prompt + " Press y to confirm, any other key cancels."
+	case m.paletteOpen:
+		return "Command: " + m.palette.Value() + " (" + palettePreview(m.palette.Value()) + ")"
+	case m.filterActive:
+		return "Filter: " + m.filter.Value() + " (enter applies, esc clears)"
+	case m.projectsOpen || m.showDashboard:
+		return "Search projects: " + m.project.Value()
+	}
+	return ""
+}
+
+// accessibleList lists the sessions, one per line, keeping the cursor's line
+// in view
+func (m *Model) accessibleList(height int) []accessibleLine {
+	if len(m.filteredSessions) == 0 {
+		switch {
+		case len(m.sessions) == 0:
+			return plainLines("No sessions yet.")
+		case m.inbox:
+			return plainLines("Nothing needs you.")
+		default:
+			return plainLines("No sessions match the filters.")
+		}
+	}
+
+	var lines []accessibleLine
+	cursorLine, idx := 0, 0
+	for _, g := range m.groups {
+		if g.Label != "" {
+			n := 0
+			for _, b := range g.Branches {
+				n += len(b.Sessions)
+			}
+			lines = append(lines, plainLines(groupLabel(m.listGroupBy())+" "+g.Label+", "+plainCount(n, "session")+":")...)
+		}
+		for _, b := range g.Branches {
+			for _, s := range b.Sessions {
+				if idx == m.cursor {
+					cursorLine = len(lines)
+				}
+				lines = append(lines, m.accessibleRow(s, idx))
+				idx++
+			}
+		}
+	}
+
+	height = widgets.MaxInt(height-1, 1) // one line for the heading
+	heading := fmt.Sprintf("Sessions, %d of %d:", m.cursor+1, len(m.filteredSessions))
+	if len(lines) > height {
+		if cursorLine < m.listOffset {
+			m.listOffset = cursorLine
+		} else if cursorLine >= m.listOffset+height {
+			m.listOffset = cursorLine - height + 1
+		}
+		m.listOffset = widgets.ClampInt(m.listOffset, 0, len(lines)-height)
+		lines = lines[m.listOffset : m.listOffset+height]
+	} else {
+		m.listOffset = 0
+	}
+	return append(plainLines(heading), lines...)
+}
+
+// accessibleRow describes one session; the cursor's row starts with ">"
+func (m *Model) accessibleRow(s state.SessionView, idx int) accessibleLine {
+	mark := "  "
+	if idx == m.cursor {
+		mark = "> "
+	}
+	diff := []string{mark + sessionKey(s), statusWord(s.Status), s.Reason}
+	parts := []string{fmt.Sprintf("%d. %s, %s", idx+1, plainProvider(s.Provider), accessibleAge(s))}
+	if m.listGroupBy() != "project" && s.Project != "" {
+		parts = append(parts, "project "+s.Project)
+	} else if m.listGroupBy() == "project" && s.Branch != "" {
+		parts = append(parts, "branch "+s.Branch)
+	}
+	if s.Model != "" && m.viewMode != "ultra" {
+		parts = append(parts, "model "+s.Model)
+	}
+	if cost := widgets.FormatCost(s.Cost); cost != "" && m.viewMode == "full" {
+		parts = append(parts, "cost "+cost)
+	}
+	if m.showGauges {
+		if s.ContextWindow > 0 {
+			parts = append(parts, fmt.Sprintf("context %d%%", s.ContextTokens*100/s.ContextWindow))
+		}
+		if rate := widgets.FormatBurnRate(s.BurnRate); rate != "" {
+			parts = append(parts, rate)
+		}
+		if n := sumInts(s.Activity); n > 0 {
+			parts = append(parts, plainCount(n, "event")+" in 10 minutes")
+		}
+	}
+	if s.Pending != nil {
+		parts = append(parts, "permission prompt for "+s.Pending.Tool)
+		diff = append(diff, parts[len(parts)-1])
+	}
+	if m.isPinned(s) {
+		parts = append(parts, "pinned")
+		diff = append(diff, "pinned")
+	}
+	if m.appState.IsSelected(sessionKey(s)) {
+		parts = append(parts, "selected")
+		diff = append(diff, "selected")
+	}
+	if len(s.Tags) > 0 {
+		parts = append(parts, "tags "+strings.Join(s.Tags, ", "))
+	}
+	if m.showLastMsg {
+		if snippet := widgets.Safe(s.LastAssist, s.LastUser); snippet != "" {
+			parts = append(parts, "last message: "+strings.Join(strings.Fields(snippet), " "))
+		}
+	}
+	return accessibleLine{text: mark + strings.Join(parts, ", "), diff: strings.Join(diff, "|")}
+}
+
+// accessibleAge is the status word with how long it has held
+func accessibleAge(s state.SessionView) string {
+	switch {
+	case s.Status == state.StatusRunning && s.TurnElapsed > 0:
+		return "running for " + widgets.FormatAge(s.TurnElapsed)
+	case widgets.IsEnded(s.Status):
+		return statusWord(s.Status) + " " + widgets.FormatAge(s.Age) + " ago"
+	default:
+		return statusWord(s.Status) + ", last seen " + widgets.FormatAge(s.Age) + " ago"
+	}
+}
+
+func sumInts(values []int) int {
+	n := 0
+	for _, v := range values {
+		n += v
+	}
+	return n
+}
+
+// accessibleDetail describes the session under the cursor
+func (m *Model) accessibleDetail() []string {
+	s := m.selectedSession()
+	if s == nil {
+		return nil
+	}
+	lines := []string{"Current session: " + plainProvider(s.Provider) + " " + stripANSI(s.ID) + "."}
+	add := func(label, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	status := statusWord(s.Status)
+	if s.Reason != "" {
+		status += " (" + s.Reason + ")"
+	}
+	add("Status", status)
+	if p := s.Pending; p != nil {
+		action := p.Tool
+		if p.Summary != "" {
+			action += ": " + p.Summary
+		}
+		add("Permission prompt", action+". Press "+m.keys.First("approve")+" to approve, "+m.keys.First("deny")+" to deny")
+	}
+	add("Project", s.Project)
+	add("Branch", s.Branch)
+	add("Model", s.Model)
+	add("Cost", widgets.FormatCost(s.Cost))
+	add("Directory", s.Dir)
+	if !s.LastSeen.IsZero() {
+		add("Last seen", s.LastSeen.In(time.Local).Format("2006-01-02 15:04:05"))
+	}
+	add("Last prompt", strings.Join(strings.Fields(s.LastUser), " "))
+	add("Last reply", strings.Join(strings.Fields(s.LastAssist), " "))
+	return lines
+}
+
+// accessibleFollow lists the follower's newest turns
+func (m *Model) accessibleFollow() []string {
+	switch {
+	case m.cfg.FollowSession == nil:
+		return []string{"Following is not available."}
+	case m.followErr != nil:
+		return []string{"Following: error: " + m.followErr.Error()}
+	case len(m.followTurns) == 0:
+		return []string{"Following: waiting for new events."}
+	}
+	lines := []string{"Following, newest last:"}
+	turns := m.followTurns
+	if len(turns) > 5 {
+		turns = turns[len(turns)-5:]
+	}
+	for _, t := range turns {
+		who := map[string]string{"user": "You", "assistant": "AI"}[t.Role]
+		if who == "" {
+			who = "Tool"
+		}
+		line := who + ": " + strings.Join(strings.Fields(t.Text), " ")
+		if !t.At.IsZero() {
+			line = t.At.In(time.Local).Format("15:04:05") + " " + line
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// accessibleHelp lists every key binding
+func (m *Model) accessibleHelp() []string {
+	lines := []string{"Keyboard shortcuts (press any key to close):"}
+	for _, e := range m.keys.helpEntries() {
+		if e.Key != "" {
+			lines = append(lines, e.Key+": "+strings.TrimSuffix(e.Desc, " ★"))
+		}
+	}
+	return lines
+}
+
+// accessibleProjects lists the picker or dashboard entries
+func (m *Model) accessibleProjects(title string, items []state.ProjectItem) []string {
+	if len(items) == 0 {
+		return []string{title + ": none found."}
+	}
+	lines := []string{fmt.Sprintf("%s, %d of %d:", title, m.projectIndex+1, len(items))}
+	for i, it := range items {
+		mark := "  "
+		if i == m.projectIndex {
+			mark = "> "
+		}
+		line := fmt.Sprintf("%s%s, %s, %d running, %d idle, %d need input",
+			mark, it.Name, plainCount(it.Count, "session"),
+			it.StatusCount[state.StatusRunning],
+			it.StatusCount[state.StatusWaiting],
+			it.StatusCount[state.StatusApproval]+it.StatusCount[state.StatusNeedsAttn])
+		if m.filters.ProjectFilter[strings.ToLower(it.Name)] {
+			line += ", filtered"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// accessibleKeys is the last line: the keys for the current mode, in words
+func (m *Model) accessibleKeys() string {
+	arrows := strings.NewReplacer("↑", "up", "↓", "down")
+	var parts []string
+	for _, s := range m.footerShortcuts() {
+		parts = append(parts, arrows.Replace(s.Key)+" "+s.Desc)
+	}
+	return "Keys: " + strings.Join(parts, ", ") + "."
+}
@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+// newTestModel returns a sized model showing the given sessions
+func newTestModel(t *testing.T, cfg Config, sessions ...state.SessionView) *Model {
+	t.Helper()
+	if cfg.RefreshEvery == 0 {
+		cfg.RefreshEvery = time.Second
+	}
+	m := New(cfg)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Update(SessionsMsg{Sessions: sessions})
+	return m
+}
+
+// key is a key press as bubbletea reports it
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// typeKeys sends each rune of s as a key press
+func typeKeys(m tea.Model, s string) {
+	for _, r := range s {
+		m.Update(key(string(r)))
+	}
+}
+
+func testSessions() []state.SessionView {
+	return []state.SessionView{
+		{Provider: state.ProviderClaude, ID: "a1", Key: "claude:a1", Status: state.StatusRunning, Project: "api", Model: "opus", Age: time.Minute, TurnElapsed: 30 * time.Second},
+		{Provider: state.ProviderCodex, ID: "b2", Key: "codex:b2", Status: state.StatusApproval, Project: "web", Age: 2 * time.Minute},
+		{Provider: state.ProviderClaude, ID: "c3", Key: "claude:c3", Status: state.StatusWaiting, Project: "api", Age: 5 * time.Minute},
+	}
+}
+
+func TestRenderAccessibleIsPlainAndOrdered(t *testing.T) {
+	m := newTestModel(t, Config{Accessible: true}, testSessions()...)
+	out := m.View()
+	if strings.Contains(out, "\x1b[") {
+		t.Fatalf("accessible view has ANSI escapes:\n%s", out)
+	}
+	lines := strings.Split(out, "\n")
+	if !strings.HasPrefix(lines[0], "aistat: 3 sessions shown, 1 running") || !strings.HasPrefix(lines[1], "View: sort urgency") {
+		t.Fatalf("unexpected summary lines:\n%s", out)
+	}
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "Keys: ") || strings.ContainsAny(last, "↑↓") {
+		t.Fatalf("unexpected keys line %q", last)
+	}
+	for _, word := range []string{"running for 30s", "needs approval", "waiting, last seen", "Current session: "} {
+		if !strings.Contains(out, word) {
+			t.Fatalf("expected %q in:\n%s", word, out)
+		}
+	}
+	cursor := slices.IndexFunc(lines, func(l string) bool { return strings.HasPrefix(l, "> ") })
+	if cursor < 0 {
+		t.Fatalf("no cursor row:\n%s", out)
+	}
+
+	m.Update(key("j"))
+	next := strings.Split(m.View(), "\n")
+	if moved := slices.IndexFunc(next, func(l string) bool { return strings.HasPrefix(l, "> ") }); moved <= cursor {
+		t.Fatalf("cursor row did not move down: %d -> %d", cursor, moved)
+	}
+	if m.animating {
+		t.Fatalf("accessible mode should not animate the cursor")
+	}
+}
+
+func TestRenderAccessibleScreens(t *testing.T) {
+	m := newTestModel(t, Config{Accessible: true}, testSessions()...)
+
+	m.Update(key("?"))
+	if out := m.View(); !strings.Contains(out, "Keyboard shortcuts (press any key to close):") || !strings.Contains(out, "j / down: Move down") {
+		t.Fatalf("unexpected help screen:\n%s", out)
+	}
+	m.Update(key("x"))
+
+	m.Update(key("p"))
+	out := m.View()
+	if !strings.Contains(out, "Search projects: ") || !strings.Contains(out, "Projects, 1 of 2:") {
+		t.Fatalf("unexpected project picker:\n%s", out)
+	}
+	m.Update(key("esc"))
+
+	m.Update(key("/"))
+	typeKeys(m, "web")
+	if out := m.View(); !strings.Contains(out, "Filter: web (enter applies, esc clears)") {
+		t.Fatalf("unexpected filter prompt:\n%s", out)
+	}
+}
+
+func TestAccessibleInlinePrintsOnlyChanges(t *testing.T) {
+	m := New(Config{Accessible: true, RefreshEvery: time.Second})
+	a := &accessibleInline{Model: m}
+	if a.View() != "" {
+		t.Fatalf("inline view should be empty")
+	}
+	if _, cmd := a.Update(tea.WindowSizeMsg{Width: 120, Height: 40}); cmd == nil || len(a.printed) == 0 {
+		t.Fatalf("first screen should be printed")
+	}
+	a.Update(SessionsMsg{Sessions: testSessions()})
+	before := slices.Clone(a.printed)
+
+	if _, cmd := a.Update(SessionsMsg{Sessions: testSessions()}); cmd != nil {
+		t.Fatalf("an unchanged screen should print nothing")
+	}
+	a.Update(key("j"))
+	changed := changedLines(before, a.printed)
+	if len(changed) == 0 || len(changed) >= len(a.printed) {
+		t.Fatalf("expected only some lines to change, got %q", changed)
+	}
+}
+
+func TestAccessibleInlineIgnoresTickingAges(t *testing.T) {
+	m := New(Config{Accessible: true, RefreshEvery: time.Second})
+	a := &accessibleInline{Model: m}
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.Update(SessionsMsg{Sessions: testSessions()})
+
+	later := testSessions()
+	for i := range later {
+		later[i].Age += 3 * time.Second
+		later[i].TurnElapsed += 3 * time.Second
+	}
+	if _, cmd := a.Update(SessionsMsg{Sessions: later}); cmd != nil {
+		t.Fatalf("older ages alone should print nothing, printed %q", changedLines(nil, a.printed))
+	}
+	if out := a.renderAccessible(); !strings.Contains(out, "running for 33s") {
+		t.Fatalf("the screen should still show the current age:\n%s", out)
+	}
+
+	later[1].Status, later[1].Reason = state.StatusRunning, "approved"
+	if _, cmd := a.Update(SessionsMsg{Sessions: later}); cmd == nil {
+		t.Fatalf("expected the new status to be printed")
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	prev := plainLines("a", "b", "b", "c")
+	next := plainLines("a", "b", "x", "b", "b", "c")
+	if got := changedLines(prev, next); !slices.Equal(got, []string{"x", "b"}) {
+		t.Fatalf("changedLines = %q", got)
+	}
+	if got := changedLines(nil, plainLines("a")); !slices.Equal(got, []string{"a"}) {
+		t.Fatalf("changedLines from nothing = %q", got)
+	}
+	// Lines compare by their diff key, not the text shown
+	prev = []accessibleLine{{text: "a, 3s ago", diff: "a"}}
+	next = []accessibleLine{{text: "a, 4s ago", diff: "a"}}
+	if got := changedLines(prev, next); got != nil {
+		t.Fatalf("same diff key should not change, got %q", got)
+	}
+}
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"fmt"
 	"math"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/harmonica"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/vburojevic/aistat/internal/app/tui/components"
 	"github.com/vburojevic/aistat/internal/app/tui/state"
 	"github.com/vburojevic/aistat/internal/app/tui/theme"
@@ -23,11 +26,22 @@ type SessionFetcher func() ([]state.SessionView, error)
 
 // Run starts the TUI with the given config and session fetcher
 func Run(cfg Config, fetcher SessionFetcher) error {
+	if cfg.NoColor || cfg.Accessible {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
 	m := New(cfg)
 	m.sessionFetcher = fetcher
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	var p *tea.Program
+	if cfg.Accessible {
+		p = tea.NewProgram(&accessibleInline{Model: m})
+	} else {
+		p = tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
 	_, err := p.Run()
+	if cfg.SaveUIState != nil {
+		_ = cfg.SaveUIState(m.uiState())
+	}
 	return err
 }
 
@@ -36,6 +50,49 @@ type Config struct {
 	RefreshEvery time.Duration
 	MaxSessions  int
 	ShowEnded    bool // Toggle to show ended/stale sessions
+
+	// IncludeLastMsg reports whether sessions are fetched with last messages
+	IncludeLastMsg bool
+
+	// AnswerPermission approves/denies a held permission request (optional)
+	AnswerPermission func(id string, allow bool) error
+	// SetIncludeLastMsg switches last-message fetching on/off (optional)
+	SetIncludeLastMsg func(on bool)
+	// OpenSession opens a session's transcript/log by key (optional)
+	OpenSession func(key string) error
+	// EndSessions marks sessions ended by key (optional)
+	EndSessions func(keys []string) error
+	// ResumeCommand returns the shell command resuming a session (optional)
+	ResumeCommand func(key string) (string, error)
+	// IngestStatus returns the hook pipeline health for the footer, and
+	// whether it needs attention; polled with each refresh (optional)
+	IngestStatus func() (string, bool)
+	// Notice is shown in the filter bar until the first key (e.g. a config
+	// problem)
+	Notice string
+
+	// Theme is the color theme (zero value: dark)
+	Theme theme.Theme
+	// NoColor renders without colors (gauges and icons keep their glyphs)
+	NoColor bool
+	// Accessible renders plain, linear text for screen readers and dumb
+	// terminals: no colors, icons, animations or mouse (see accessible.go)
+	Accessible bool
+	// Keys rebinds normal-mode actions (action -> keys, see KeyActions)
+	Keys map[string][]string
+
+	// LoadDetail loads the detail tabs (conversation, usage, timeline) for
+	// one session by key (optional)
+	LoadDetail func(key string) (state.SessionDetail, error)
+	// FollowSession returns the turns appended to a session's transcript
+	// since offset and the offset to resume from; a negative offset starts
+	// from the tail (optional)
+	FollowSession func(key string, offset int64) ([]state.Turn, int64, error)
+
+	// UIState is the state restored from the last run (nil for defaults)
+	UIState *state.UIState
+	// SaveUIState persists the UI state on exit (optional)
+	SaveUIState func(state.UIState) error
 }
 
 // Model is the main TUI model - simplified single-view design
@@ -51,18 +108,85 @@ type Model struct {
 
 	// State
 	sessions         []state.SessionView
-	filteredSessions []state.SessionView
+	filteredSessions []state.SessionView // in display order
+	groups           []sessionGroup
 	cursor           int
-	pinned           map[string]bool // Pinned/bookmarked session IDs
+	pinned           map[string]bool     // Pinned/bookmarked session keys
+	appState         *state.AppState     // Multi-selection, by session key
+	hidden           map[string]bool     // Session keys hidden until :unhide
+	tags             map[string][]string // Session key -> tags
+
+	// Destructive bulk action waiting for y/n
+	confirm *confirmAction
 
 	// Filter
 	filter       textinput.Model
 	filterActive bool
 	filterQuery  string
+	filters      *state.FilterState
+
+	// Sort/group/view (cycled with s/g/v)
+	sortBy   string
+	groupBy  string
+	viewMode string
+
+	// Command palette
+	palette     textinput.Model
+	paletteOpen bool
+	notice      string // one-shot message shown in the filter bar
+
+	// Hook pipeline health (footer)
+	ingest     string
+	ingestWarn bool
+
+	// Project picker and dashboard
+	project       textinput.Model
+	projectsOpen  bool
+	showDashboard bool
+	projectIndex  int
+
+	// Triage inbox (i): only sessions needing the user, minus snoozed/seen
+	inbox   bool
+	snoozed map[string]time.Time      // session key -> snoozed until
+	seen    map[string]state.SeenMark // session key -> status when marked seen
+
+	// Detail pane tabs, loaded lazily for the selected session
+	detailTab     int
+	detailScroll  int
+	details       map[string]detailEntry
+	detailLoading string // key being loaded
+	detailKey     string // session the scroll offset belongs to
+
+	// Transcript follower (f): a pane beneath the list tailing the selected
+	// session
+	following     bool
+	followTicking bool // a FollowTickMsg is pending
+	followBusy    bool // a read is in flight
+	followKey     string
+	followOffset  int64
+	followTurns   []state.Turn
+	followErr     error
+
+	// List viewport: first visible line and the session index shown on each
+	// visible line (-1 for dividers), for mouse hit-testing
+	listOffset int
+	listRows   []int
+
+	// Last left click, to detect double-clicks
+	lastClickRow int
+	lastClickAt  time.Time
+
+	// Per-size-class layouts (detail/sidebar/view) and the current class
+	layouts   map[string]state.Layout
+	sizeClass string
 
 	// UI State
 	showHelp     bool
 	showEnded    bool
+	showDetail   bool
+	showSidebar  bool
+	showLastMsg  bool
+	showGauges   bool
 	refreshing   bool
 	spinnerFrame int
 	err          error
@@ -74,13 +198,19 @@ type Model struct {
 	targetCursor   int     // Target cursor position
 	animating      bool    // Whether animation is in progress
 
-	// Theme
+	// Theme and keymap
+	theme  theme.Theme
 	styles theme.Styles
+	keys   Keymap
 }
 
 // New creates a new TUI model
 func New(cfg Config) *Model {
-	styles := theme.DefaultStyles()
+	t := cfg.Theme
+	if t.Name == "" {
+		t = theme.Default
+	}
+	keys, keyErr := NewKeymap(cfg.Keys)
 
 	// Initialize filter input
 	f := textinput.New()
@@ -89,14 +219,51 @@ func New(cfg Config) *Model {
 	f.CharLimit = 128
 	f.Width = 40
 
-	return &Model{
+	pal := textinput.New()
+	pal.Placeholder = "command..."
+	pal.Prompt = ""
+	pal.CharLimit = 64
+	pal.Width = 40
+
+	proj := textinput.New()
+	proj.Placeholder = "filter projects..."
+	proj.Prompt = "› "
+	proj.CharLimit = 64
+	proj.Width = 30
+
+	m := &Model{
 		cfg:          cfg,
 		filter:       f,
-		styles:       styles,
+		palette:      pal,
+		project:      proj,
+		filters:      state.NewFilterState(),
+		theme:        t,
+		styles:       theme.NewStyles(t),
+		keys:         keys,
 		showEnded:    cfg.ShowEnded,
+		showDetail:   true,
+		showLastMsg:  cfg.IncludeLastMsg,
+		sortBy:       "urgency",
+		groupBy:      "project",
+		viewMode:     "compact",
 		pinned:       make(map[string]bool),
+		appState:     state.NewAppState(),
+		hidden:       make(map[string]bool),
+		tags:         make(map[string][]string),
+		layouts:      make(map[string]state.Layout),
+		details:      make(map[string]detailEntry),
+		snoozed:      make(map[string]time.Time),
+		seen:         make(map[string]state.SeenMark),
 		cursorSpring: harmonica.NewSpring(harmonica.FPS(60), 6.0, 0.5),
 	}
+	if cfg.UIState != nil {
+		m.restoreUIState(*cfg.UIState)
+	}
+	m.notice = cfg.Notice
+	if keyErr != nil {
+		m.notice = keyErr.Error()
+	}
+	return m
 }
 
 // Init initializes the model
@@ -112,13 +279,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
 		m.filter.Width = widgets.MinInt(60, widgets.MaxInt(20, m.width-20))
+		m.switchLayout(state.SizeClass(m.width))
 
 	case SessionsMsg:
 		m.refreshing = false
 		m.err = msg.Err
+		m.ingest, m.ingestWarn = msg.Ingest, msg.IngestWarn
 		if msg.Err == nil {
 			m.sessions = msg.Sessions
-			m.applyFilter()
+			if m.cfg.Accessible {
+				// Keep the cursor on the same session when rows reorder
+				m.applyFilterKeepCursor()
+			} else {
+				m.applyFilter()
+			}
 		}
 
 	case TickMsg:
@@ -148,6 +322,26 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case DetailMsg:
+		m.storeDetail(msg)
+
+	case FollowTickMsg:
+		if !m.following {
+			m.followTicking = false
+		} else {
+			cmds = append(cmds, m.followReadCmd(), followTickCmd())
+		}
+
+	case FollowMsg:
+		if cmd := m.storeFollow(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case tea.MouseMsg:
+		if cmd := m.handleMouse(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 	case tea.KeyMsg:
 		cmd := m.handleKeyMsg(msg)
 		if cmd != nil {
@@ -155,17 +349,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Load detail tabs and retarget the follower for whatever ended up selected
+	if cmd := m.ensureDetailCmd(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.ensureFollowCmd(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
 // handleKeyMsg handles keyboard input
 func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
+	m.notice = ""
+
 	// Help view - dismiss on any key
 	if m.showHelp {
 		m.showHelp = false
 		return nil
 	}
 
+	// Confirmation prompt for a bulk action
+	if m.confirm != nil {
+		return m.handleConfirmKeys(msg)
+	}
+
+	// Command palette
+	if m.paletteOpen {
+		return m.handlePaletteKeys(msg)
+	}
+
+	// Project picker / dashboard
+	if m.projectsOpen || m.showDashboard {
+		return m.handleProjectKeys(msg)
+	}
+
 	// Filter mode
 	if m.filterActive {
 		return m.handleFilterKeys(msg)
@@ -200,67 +419,203 @@ func (m *Model) handleFilterKeys(msg tea.KeyMsg) tea.Cmd {
 	}
 }
 
-// handleNormalKeys handles keys in normal mode (~10 essential shortcuts)
-func (m *Model) handleNormalKeys(msg tea.KeyMsg) tea.Cmd {
+// handlePaletteKeys handles keys when the command palette is open
+func (m *Model) handlePaletteKeys(msg tea.KeyMsg) tea.Cmd {
 	switch msg.String() {
-	case "q", "ctrl+c":
+	case "ctrl+c":
 		return tea.Quit
+	case "esc":
+		m.paletteOpen = false
+		m.palette.Blur()
+		m.palette.SetValue("")
+		return nil
+	case "enter":
+		raw := m.palette.Value()
+		m.paletteOpen = false
+		m.palette.Blur()
+		m.palette.SetValue("")
+		return m.executePaletteCommand(raw)
+	case "tab":
+		m.palette.SetValue(completePalette(m.palette.Value()))
+		m.palette.CursorEnd()
+		return nil
+	default:
+		var cmd tea.Cmd
+		m.palette, cmd = m.palette.Update(msg)
+		return cmd
+	}
+}
 
-	case "j", "down":
+// handleNormalKeys handles keys in normal mode via the (configurable) keymap
+func (m *Model) handleNormalKeys(msg tea.KeyMsg) tea.Cmd {
+	switch action := m.keys.Action(msg.String()); action {
+	case "quit":
+		return tea.Quit
+
+	case "down":
 		return m.moveCursor(1)
 
-	case "k", "up":
+	case "up":
 		return m.moveCursor(-1)
 
-	case "/":
+	case "filter":
 		m.filterActive = true
 		m.filter.Focus()
 
-	case "?":
+	case "palette":
+		m.paletteOpen = true
+		m.palette.SetValue("")
+		m.palette.Focus()
+
+	case "dashboard":
+		m.openDashboard()
+
+	case "projects":
+		m.openProjectPicker()
+
+	case "help":
 		m.showHelp = true
 
-	case "y":
-		// Copy selected session ID
-		if s := m.selectedSession(); s != nil {
-			copyToClipboard(s.ID)
-		}
+	case "copy_ids":
+		// Copy selected session IDs (or the one under the cursor)
+		m.copySelectedIDs()
+
+	case "copy_detail":
+		m.copyDetail()
+
+	case "open":
+		return m.openTargets()
+	case "end":
+		m.endTargets()
+	case "hide":
+		m.hideTargets()
+	case "tag":
+		m.paletteOpen = true
+		m.palette.SetValue("tag ")
+		m.palette.CursorEnd()
+		m.palette.Focus()
+	case "copy_resume":
+		m.copyResumeCommands()
+
+	case "sort":
+		m.sortBy = cycle(sortOrder, m.sortBy)
+		m.applyFilter()
+		m.notice = "Sort: " + m.sortBy
+
+	case "group":
+		m.groupBy = cycle(groupOrder, m.groupBy)
+		m.applyFilter()
+		m.notice = "Group: " + groupLabel(m.groupBy)
+
+	case "view":
+		m.viewMode = cycle(viewOrder, m.viewMode)
+		m.notice = "View: " + m.viewMode
+
+	case "detail":
+		m.showDetail = !m.showDetail
+
+	case "follow":
+		m.toggleFollow()
+
+	case "tab_prev":
+		m.cycleDetailTab(-1)
+	case "tab_next":
+		m.cycleDetailTab(1)
+	case "scroll_down":
+		m.scrollDetail(-5)
+	case "scroll_up":
+		m.scrollDetail(5)
+
+	case "inbox":
+		m.toggleInbox()
+	case "next":
+		return m.nextNeedingMe()
+	case "snooze":
+		m.snoozeSelected(defaultSnooze)
+	case "seen":
+		m.markSeen()
+
+	case "gauges":
+		m.showGauges = !m.showGauges
+
+	case "last_msg":
+		return m.setLastMsg(!m.showLastMsg)
+
+	case "theme":
+		m.cycleTheme()
+
+	case "provider_claude":
+		m.filters.ToggleProvider(state.ProviderClaude)
+		m.applyFilter()
+	case "provider_codex":
+		m.filters.ToggleProvider(state.ProviderCodex)
+		m.applyFilter()
 
-	case "r":
+	case "status_running", "status_waiting", "status_approval", "status_stale", "status_ended", "status_attention":
+		m.filters.ToggleStatus(statusActions[action])
+		m.applyFilter()
+
+	case "refresh":
 		m.refreshing = true
 		return m.fetchSessionsCmd()
 
-	case "a":
+	case "show_all":
 		// Toggle show all (including ended)
 		m.showEnded = !m.showEnded
 		m.applyFilter()
 
-	case "b":
-		// Toggle bookmark/pin on selected session
+	case "sidebar":
+		m.showSidebar = !m.showSidebar
+
+	case "pin":
+		// Toggle pin on selected session
 		if s := m.selectedSession(); s != nil {
-			m.togglePin(s.ID)
+			m.togglePin(*s)
 			m.applyFilter() // Re-sort to move pinned to top
 		}
 
-	case "enter":
-		// No-op for now, selection is just visual
+	case "select":
+		// Toggle multi-selection on the selected session
+		if s := m.selectedSession(); s != nil {
+			m.appState.ToggleSelect(sessionKey(*s))
+		}
 
-	case "esc":
-		// Clear filter if set
+	case "approve", "deny":
+		// Answer the selected session's pending permission prompt
+		return m.answerPending(action == "approve")
+
+	case "clear":
+		// Clear the text filter first, then the selection
 		if m.filterQuery != "" {
 			m.filterQuery = ""
 			m.filter.SetValue("")
 			m.applyFilter()
+		} else {
+			m.appState.ClearSelection()
 		}
 	}
 
 	return nil
 }
 
+// statusActions maps the status filter actions to statuses
+var statusActions = map[string]state.Status{
+	"status_running":   state.StatusRunning,
+	"status_waiting":   state.StatusWaiting,
+	"status_approval":  state.StatusApproval,
+	"status_stale":     state.StatusStale,
+	"status_ended":     state.StatusEnded,
+	"status_attention": state.StatusNeedsAttn,
+}
+
 // View renders the UI
 func (m *Model) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	if m.cfg.Accessible {
+		return m.renderAccessible()
+	}
 
 	var b strings.Builder
 
@@ -268,47 +623,171 @@ func (m *Model) View() string {
 	b.WriteString(components.RenderHeader(m.filteredSessions, m.styles, m.width))
 	b.WriteString("\n")
 
-	// Filter bar
-	b.WriteString(components.RenderFilterBar(m.filterQuery, m.filterActive, m.styles))
+	// Filter bar + active filters + sort/group/view (or the last notice)
+	b.WriteString(m.renderFilterLine())
 	b.WriteString("\n")
 
-	// Main content: split view (list | detail)
-	listWidth := m.width / 2
-	detailWidth := m.width - listWidth - 3 // 3 for gap
+	// Main content: [sidebar |] list [| detail]
+	l := m.layout()
+	var panes []string
+	if l.sidebar {
+		panes = append(panes, components.RenderSidebar(m.sessions, m.filters, m.keys.First, m.styles, l.bodyHeight), " ")
+	}
+	listPanel := m.styles.List.Width(l.listWidth).Height(l.listHeight).Render(m.renderSessionList(l.listWidth, l.listHeight))
+	if l.followHeight > 0 {
+		followPanel := m.styles.List.Width(l.listWidth).Height(l.followHeight).Padding(0, 1).Render(m.renderFollowPane(l.listWidth, l.followHeight))
+		listPanel = lipgloss.JoinVertical(lipgloss.Left, listPanel, followPanel)
+	}
+	panes = append(panes, listPanel)
+	if m.showDetail {
+		detailPanel := m.styles.Detail.Width(l.detailWidth).Height(l.bodyHeight).Render(m.renderDetailPane(l.detailWidth, l.bodyHeight))
+		panes = append(panes, " ", detailPanel)
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, panes...))
+	b.WriteString("\n")
 
-	listContent := m.renderSessionList(listWidth)
-	detailContent := components.RenderDetail(m.selectedSession(), m.styles, detailWidth)
+	// Footer
+	b.WriteString(components.RenderFooter(m.footerShortcuts(), m.ingest, m.ingestWarn, m.refreshing, m.spinnerFrame, SpinnerFrames, m.styles, m.width))
 
-	// Wrap in panels
-	listPanel := m.styles.List.Width(listWidth).Height(m.height - 6).Render(listContent)
-	detailPanel := m.styles.Detail.Width(detailWidth).Height(m.height - 6).Render(detailContent)
+	// Overlays (centered)
+	var overlay string
+	switch {
+	case m.confirm != nil:
+		overlay = views.RenderConfirm(m.confirm.prompt, m.styles)
+	case m.showHelp:
+		overlay = views.RenderHelpOverlay(m.keys.helpEntries(), m.styles)
+	case m.paletteOpen:
+		overlay = views.RenderPalette(m.palette.View(), palettePreview(m.palette.Value()), m.styles, widgets.MinInt(80, m.width-4))
+	case m.projectsOpen:
+		items := m.pickerItems()
+		m.projectIndex = clampIndex(m.projectIndex, len(items))
+		overlay = views.RenderProjectPicker(items, m.projectIndex, m.project.View(), m.filters, m.styles, m.height)
+	case m.showDashboard:
+		items := m.dashboardItems()
+		m.projectIndex = clampIndex(m.projectIndex, len(items))
+		overlay = views.RenderDashboard(items, m.projectIndex, m.project.View(), m.filters, m.styles, m.height)
+	}
+	if overlay != "" {
+		x := (m.width - lipgloss.Width(overlay)) / 2
+		y := (m.height - lipgloss.Height(overlay)) / 2
+		return placeOverlay(x, y, overlay, b.String())
+	}
 
-	gap := " "
-	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, listPanel, gap, detailPanel)
-	b.WriteString(mainContent)
-	b.WriteString("\n")
+	return b.String()
+}
 
-	// Footer
-	b.WriteString(components.RenderFooter(m.filterActive, m.refreshing, m.spinnerFrame, SpinnerFrames, m.styles, m.width))
+// paneLayout is the geometry of the main content, shared by View and the
+// mouse handler. Panels start below the header and filter line.
+type paneLayout struct {
+	bodyHeight   int
+	listHeight   int // list content lines (less than bodyHeight when following)
+	followHeight int // follower content lines, 0 when hidden
+	sidebar      bool
+	listX        int // left border of the list panel
+	listWidth    int
+	detailX      int // left border of the detail panel (when shown)
+	detailWidth  int
+}
 
-	// Help overlay
-	if m.showHelp {
-		// Render help on top (centered)
-		helpContent := views.RenderHelpOverlay(m.styles)
-		helpWidth := lipgloss.Width(helpContent)
-		helpHeight := lipgloss.Height(helpContent)
-		x := (m.width - helpWidth) / 2
-		y := (m.height - helpHeight) / 2
+// bodyTop is the first content line of the panels (header, filter line,
+// top border)
+const bodyTop = 3
 
-		// This is a simple overlay - in a real app you'd use a more sophisticated approach
-		return placeOverlay(x, y, helpContent, b.String())
+func (m *Model) layout() paneLayout {
+	l := paneLayout{bodyHeight: m.height - 6, sidebar: m.sidebarVisible()}
+	l.listHeight = l.bodyHeight
+	if m.following && l.bodyHeight >= 12 {
+		l.followHeight = l.bodyHeight * 2 / 5
+		l.listHeight = l.bodyHeight - l.followHeight - 2 // 2 for the follower border
 	}
+	available := m.width
+	if l.sidebar {
+		l.listX = components.SidebarWidth + 1
+		available -= components.SidebarWidth + 1
+	}
+	if m.showDetail {
+		l.listWidth = available / 2
+		l.detailWidth = available - l.listWidth - 3 // 3 for gap
+		l.detailX = l.listX + l.listWidth + 3
+	} else {
+		l.listWidth = available - 2
+	}
+	return l
+}
 
-	return b.String()
+// renderFilterLine renders the query, filter pills and sort/group/view state
+func (m *Model) renderFilterLine() string {
+	left := components.RenderFilterBar(m.filterQuery, m.filterActive, m.styles)
+	if pills := components.RenderFilterPills(m.filters, len(m.appState.SelectedIDs()), len(m.hidden), m.styles); pills != "" {
+		left += "  " + pills
+	}
+
+	right := m.styles.Muted.Render("sort:" + m.sortBy + " group:" + groupLabel(m.groupBy) + " view:" + m.viewMode)
+	if m.inbox {
+		right = m.styles.BadgeNeedsInput.Render(fmt.Sprintf("inbox %d", len(m.filteredSessions))) +
+			m.styles.Muted.Render(fmt.Sprintf(" %d snoozed", m.activeSnoozes(time.Now())))
+	}
+	if m.notice != "" {
+		right = m.styles.FilterText.Render(m.notice)
+	}
+
+	gap := m.width - lipgloss.Width(left) - lipgloss.Width(right) - 2
+	if gap < 2 {
+		return left
+	}
+	return left + strings.Repeat(" ", gap) + right
 }
 
-// renderSessionList renders the session list grouped by project and branch
-func (m *Model) renderSessionList(width int) string {
+// footerShortcuts returns the shortcuts for the current mode
+func (m *Model) footerShortcuts() []components.Shortcut {
+	switch {
+	case m.confirm != nil:
+		return components.ConfirmShortcuts
+	case m.filterActive:
+		return components.FilterShortcuts
+	case m.paletteOpen:
+		return components.PaletteShortcuts
+	case m.projectsOpen || m.showDashboard:
+		return components.ProjectShortcuts
+	case m.inbox:
+		return []components.Shortcut{
+			m.keys.shortcut("next", "next"),
+			m.keys.shortcut("snooze", "snooze 15m"),
+			m.keys.shortcut("seen", "seen"),
+			m.keys.shortcut("inbox", "all sessions"),
+			m.keys.shortcut("help", "help"),
+			m.keys.shortcut("quit", "quit"),
+		}
+	default:
+		return []components.Shortcut{
+			{Key: m.keys.First("down") + "/" + m.keys.First("up"), Desc: "navigate"},
+			m.keys.shortcut("filter", "filter"),
+			m.keys.shortcut("palette", "palette"),
+			m.keys.shortcut("dashboard", "projects"),
+			m.keys.shortcut("help", "help"),
+			m.keys.shortcut("quit", "quit"),
+		}
+	}
+}
+
+// listGroupBy is the grouping in effect (the inbox is always flat)
+func (m *Model) listGroupBy() string {
+	if m.inbox {
+		return ""
+	}
+	return m.groupBy
+}
+
+// sidebarVisible reports whether the filter sidebar fits and is enabled
+func (m *Model) sidebarVisible() bool {
+	return m.showSidebar && m.width >= 100
+}
+
+// renderSessionList renders the visible part of the session list in its
+// display groups and records which session each line shows
+func (m *Model) renderSessionList(width, height int) string {
+	m.listRows = nil
+
 	// Show error state if there was a fetch error
 	if m.err != nil {
 		return components.RenderError(m.err, m.styles)
@@ -318,41 +797,62 @@ func (m *Model) renderSessionList(width int) string {
 		if len(m.sessions) == 0 {
 			return components.RenderEmptyState(m.styles)
 		}
+		if m.inbox {
+			return components.RenderInboxEmpty(m.activeSnoozes(time.Now()), m.styles)
+		}
 		return components.RenderFilteredEmpty(m.filterQuery, m.styles)
 	}
 
 	var lines []string
-
-	// Group by project and branch
-	groups := m.groupByProjectAndBranch()
+	var rows []int // session index per line, -1 for headers
 
 	rowIdx := 0
-	for _, g := range groups {
-		// Count total sessions in this project
-		projectCount := 0
-		for _, b := range g.Branches {
-			projectCount += len(b.Sessions)
+	cursorLine := 0
+	for _, g := range m.groups {
+		// Group divider with count (none when ungrouped)
+		if g.Label != "" {
+			count := 0
+			for _, b := range g.Branches {
+				count += len(b.Sessions)
+			}
+			lines = append(lines, m.renderDivider(g.Label, count, width-4))
+			rows = append(rows, -1)
 		}
 
-		// Project divider with count
-		divider := m.renderDivider(g.Project, projectCount, width-4)
-		lines = append(lines, divider)
-
-		// Branches in this project
 		for _, b := range g.Branches {
-			// Branch sub-header with count
-			branchHeader := m.renderBranchHeader(b.Branch, len(b.Sessions))
-			lines = append(lines, branchHeader)
+			// Branch sub-header with count (project grouping only)
+			if m.listGroupBy() == "project" {
+				lines = append(lines, m.renderBranchHeader(b.Branch, len(b.Sessions)))
+				rows = append(rows, -1)
+			}
 
-			// Sessions in this branch
 			for _, s := range b.Sessions {
+				if rowIdx == m.cursor {
+					cursorLine = len(lines)
+				}
 				row := m.renderSessionRow(s, rowIdx == m.cursor, width-4)
 				lines = append(lines, row)
+				rows = append(rows, rowIdx)
 				rowIdx++
 			}
 		}
 	}
 
+	// Scroll just enough to keep the cursor in view
+	if height > 0 && len(lines) > height {
+		if cursorLine < m.listOffset {
+			m.listOffset = cursorLine
+		} else if cursorLine >= m.listOffset+height {
+			m.listOffset = cursorLine - height + 1
+		}
+		m.listOffset = widgets.ClampInt(m.listOffset, 0, len(lines)-height)
+		lines = lines[m.listOffset : m.listOffset+height]
+		rows = rows[m.listOffset : m.listOffset+height]
+	} else {
+		m.listOffset = 0
+	}
+	m.listRows = rows
+
 	return strings.Join(lines, "\n")
 }
 
@@ -390,25 +890,35 @@ func (m *Model) renderDivider(project string, count int, width int) string {
 	return m.styles.Divider.Render(line + label + line)
 }
 
-// renderSessionRow renders a single session row (under a branch header)
+// renderSessionRow renders a single session row; the view mode decides
+// which columns follow the status icon
 func (m *Model) renderSessionRow(s state.SessionView, selected bool, width int) string {
-	// Indentation for sessions under branch
-	indent := "    "
+	// Indentation for sessions under a branch header
+	indent := "  "
+	if m.listGroupBy() == "project" {
+		indent = "    "
+	}
 
-	// Arrow indicator for selection
+	// Arrow indicator for the cursor, dot for multi-selected rows
 	indicator := "  "
 	if selected {
 		indicator = m.styles.Selected.Render("❯ ")
+	} else if m.appState.IsSelected(sessionKey(s)) {
+		indicator = m.styles.Selected.Render("• ")
 	}
 
 	// Pin indicator (★) for bookmarked sessions
 	pinIndicator := " "
-	if m.isPinned(s.ID) {
+	if m.isPinned(s) {
 		pinIndicator = m.styles.DotNeedsInput.Render("★")
 	}
 
 	// Keep a spacer for column alignment; status icon already conveys urgency.
+	// A held permission prompt that can be answered here gets a marker.
 	urgency := " "
+	if s.Pending != nil {
+		urgency = m.styles.DotNeedsInput.Render("!")
+	}
 
 	// Status icon (keeps its color regardless of age)
 	var icon string
@@ -430,7 +940,40 @@ func (m *Model) renderSessionRow(s state.SessionView, selected bool, width int)
 	statusAge := m.formatStatusAgeStyled(s)
 
 	// Build row content: indent + indicator + pin + urgency + icon + model + status-age
-	rowContent := indent + indicator + pinIndicator + urgency + icon + " " + modelText + " " + statusAge
+	rowContent := indent + indicator + pinIndicator + urgency + icon + " "
+	if m.viewMode != "ultra" {
+		rowContent += modelText + " "
+	}
+	rowContent += statusAge
+
+	if m.viewMode == "full" {
+		rowContent += " " + widgets.ProviderLetterStyled(s.Provider, m.styles) +
+			" " + m.styles.Muted.Render(widgets.PadRight(widgets.TruncateString(stripANSI(s.ID), 12), 12))
+		if cost := widgets.FormatCost(s.Cost); cost != "" {
+			rowContent += " " + widgets.PadLeft(cost, 7)
+		}
+		if m.listGroupBy() != "project" && s.Project != "" {
+			rowContent += " " + widgets.TruncateString(s.Project, 18)
+		}
+	} else if m.inbox && s.Project != "" {
+		// The inbox is ungrouped, so rows name their project
+		rowContent += " " + widgets.TruncateString(s.Project, 18)
+	}
+	if len(s.Tags) > 0 && m.viewMode != "ultra" {
+		rowContent += " " + m.styles.FilterText.Render("#"+strings.Join(s.Tags, " #"))
+	}
+	if m.showGauges {
+		if g := m.rowGauges(s, width-lipgloss.Width(rowContent)-1); g != "" {
+			rowContent += " " + g
+		}
+	}
+	if m.showLastMsg {
+		if snippet := widgets.Safe(s.LastAssist, s.LastUser); snippet != "" {
+			snippet = strings.Join(strings.Fields(snippet), " ")
+			rowContent += " " + m.styles.Muted.Render(snippet)
+		}
+	}
+	rowContent = lipgloss.NewStyle().MaxWidth(widgets.MaxInt(width, 10)).Render(rowContent)
 
 	// Apply background highlight for selected row (no fixed width)
 	if selected {
@@ -476,6 +1019,9 @@ func (m *Model) formatStatusAgeStyled(s state.SessionView) string {
 	var style lipgloss.Style
 
 	switch {
+	case s.Status == state.StatusRunning && s.TurnElapsed > 0:
+		text = "running " + widgets.FormatAge(s.TurnElapsed)
+		style = m.styles.StatusRunning
 	case s.Status == state.StatusRunning:
 		text = "running " + age
 		style = m.styles.StatusRunning
@@ -507,6 +1053,8 @@ func formatStatusAge(s state.SessionView) string {
 	age := widgets.FormatAge(s.Age)
 
 	switch {
+	case s.Status == state.StatusRunning && s.TurnElapsed > 0:
+		return "running " + widgets.FormatAge(s.TurnElapsed)
 	case s.Status == state.StatusRunning:
 		return "running " + age
 	case s.Status == state.StatusApproval || s.Status == state.StatusNeedsAttn:
@@ -534,19 +1082,120 @@ func (m *Model) ageTextStyle(age time.Duration) lipgloss.Style {
 
 // Helper methods
 
+// answerPending approves or denies the selected session's held permission
+// request, then refreshes so the session's new state shows up.
+func (m *Model) answerPending(allow bool) tea.Cmd {
+	s := m.selectedSession()
+	if s == nil || s.Pending == nil || m.cfg.AnswerPermission == nil {
+		return nil
+	}
+	// An error means the request already expired or was answered elsewhere;
+	// either way the refresh shows the current state.
+	_ = m.cfg.AnswerPermission(s.Pending.ID, allow)
+	s.Pending = nil
+	return m.fetchSessionsCmd()
+}
+
+// copySelectedIDs copies the multi-selected IDs, or the cursor's ID
+func (m *Model) copySelectedIDs() {
+	var ids []string
+	for _, s := range m.filteredSessions {
+		if m.appState.IsSelected(sessionKey(s)) {
+			ids = append(ids, stripANSI(s.ID))
+		}
+	}
+	if len(ids) == 0 {
+		if s := m.selectedSession(); s != nil {
+			ids = append(ids, stripANSI(s.ID))
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := copyToClipboard(strings.Join(ids, "\n")); err == nil {
+		m.notice = "Copied " + widgets.FormatInt(len(ids)) + " id(s)"
+	}
+}
+
+// copyDetail copies the selected session's detail text
+func (m *Model) copyDetail() {
+	if s := m.selectedSession(); s != nil {
+		if err := copyToClipboard(stripANSI(s.Detail)); err == nil {
+			m.notice = "Copied detail"
+		}
+	}
+}
+
+// setLastMsg toggles last-message snippets and refetches so they show up
+func (m *Model) setLastMsg(on bool) tea.Cmd {
+	m.showLastMsg = on
+	m.notice = "Last message: " + onOff(on)
+	if m.cfg.SetIncludeLastMsg == nil {
+		return nil
+	}
+	m.cfg.SetIncludeLastMsg(on)
+	return m.fetchSessionsCmd()
+}
+
+// clearFilters clears provider/status/project filters and the text query
+func (m *Model) clearFilters() {
+	m.filters.Clear()
+	m.filterQuery = ""
+	m.filter.SetValue("")
+	m.applyFilter()
+}
+
+// resetView restores sort, group, view and pane defaults
+func (m *Model) resetView() {
+	m.sortBy = "urgency"
+	m.groupBy = "project"
+	m.viewMode = "compact"
+	m.showDetail = true
+	m.showSidebar = false
+	if m.following {
+		m.toggleFollow()
+	}
+	m.applyFilter()
+}
+
+// cycleTheme switches to the next built-in theme (a custom theme from the
+// config cycles back to the first built-in)
+func (m *Model) cycleTheme() {
+	next := theme.Builtins[0]
+	for i, t := range theme.Builtins {
+		if t.Name == m.theme.Name {
+			next = theme.Builtins[(i+1)%len(theme.Builtins)]
+			break
+		}
+	}
+	m.setTheme(next)
+}
+
+func (m *Model) setTheme(t theme.Theme) {
+	m.theme = t
+	m.styles = theme.NewStyles(t)
+	m.notice = "Theme: " + t.Name
+}
+
 func (m *Model) fetchSessionsCmd() tea.Cmd {
 	if m.sessionFetcher == nil {
 		return nil
 	}
 	m.refreshing = true
 	fetcher := m.sessionFetcher
-	return tea.Batch(
-		func() tea.Msg {
-			sessions, err := fetcher()
-			return SessionsMsg{Sessions: sessions, Err: err}
-		},
-		m.spinnerTickCmd(),
-	)
+	ingestStatus := m.cfg.IngestStatus
+	fetch := func() tea.Msg {
+		sessions, err := fetcher()
+		msg := SessionsMsg{Sessions: sessions, Err: err}
+		if ingestStatus != nil {
+			msg.Ingest, msg.IngestWarn = ingestStatus()
+		}
+		return msg
+	}
+	if m.cfg.Accessible {
+		return fetch
+	}
+	return tea.Batch(fetch, m.spinnerTickCmd())
 }
 
 func (m *Model) spinnerTickCmd() tea.Cmd {
@@ -587,6 +1236,10 @@ func (m *Model) moveCursor(delta int) tea.Cmd {
 	}
 
 	m.targetCursor = newTarget
+	if m.cfg.Accessible {
+		m.cursor = newTarget
+		return nil
+	}
 
 	// Initialize animation if not already running
 	if !m.animating {
@@ -598,18 +1251,25 @@ func (m *Model) moveCursor(delta int) tea.Cmd {
 	return m.animationTickCmd()
 }
 
-func (m *Model) togglePin(id string) {
-	// Strip ANSI codes from ID for consistent key
-	cleanID := stripANSI(id)
-	if m.pinned[cleanID] {
-		delete(m.pinned, cleanID)
+// sessionKey identifies a session across refreshes and restarts
+func sessionKey(s state.SessionView) string {
+	if s.Key != "" {
+		return s.Key
+	}
+	return stripANSI(s.ID)
+}
+
+func (m *Model) togglePin(s state.SessionView) {
+	key := sessionKey(s)
+	if m.pinned[key] {
+		delete(m.pinned, key)
 	} else {
-		m.pinned[cleanID] = true
+		m.pinned[key] = true
 	}
 }
 
-func (m *Model) isPinned(id string) bool {
-	return m.pinned[stripANSI(id)]
+func (m *Model) isPinned(s state.SessionView) bool {
+	return m.pinned[sessionKey(s)]
 }
 
 func (m *Model) applyPinnedFirst(list []state.SessionView) []state.SessionView {
@@ -619,7 +1279,7 @@ func (m *Model) applyPinnedFirst(list []state.SessionView) []state.SessionView {
 	pinned := make([]state.SessionView, 0, len(list))
 	rest := make([]state.SessionView, 0, len(list))
 	for _, sess := range list {
-		if m.isPinned(sess.ID) {
+		if m.isPinned(sess) {
 			pinned = append(pinned, sess)
 		} else {
 			rest = append(rest, sess)
@@ -651,52 +1311,92 @@ func stripANSI(s string) string {
 func (m *Model) applyFilter() {
 	filtered := make([]state.SessionView, 0, len(m.sessions))
 	recentWindow := 24 * time.Hour
+	m.pruneSeen()
+
+	// Explicit ended/stale status filters count as "show all"
+	showEnded := m.showEnded || m.filters.StatusFilter[state.StatusEnded] || m.filters.StatusFilter[state.StatusStale]
 
 	for _, s := range m.sessions {
+		key := sessionKey(s)
+		if m.hidden[key] {
+			continue
+		}
+		s.Tags = m.tags[key]
+
 		// Recent sessions (last 24h) are always shown
 		// Older ended sessions are hidden unless showEnded is true
 		isRecent := s.Age < recentWindow
 		isEnded := widgets.IsEnded(s.Status)
 
-		if !isRecent && isEnded && !m.showEnded {
+		if !isRecent && isEnded && !showEnded {
 			continue
 		}
+		filtered = append(filtered, s)
+	}
 
-		// Apply text filter
-		if m.filterQuery != "" {
-			query := strings.ToLower(m.filterQuery)
+	// Provider/status/project filters and the text query (p:, s: prefixes)
+	m.filters.TextQuery = m.filterQuery
+	m.filters.ParseQueryMode()
+	filtered = m.filters.ApplyToSessions(filtered)
 
-			// Smart filter: check for provider names
-			if query == "claude" && s.Provider != state.ProviderClaude {
-				continue
-			}
-			if query == "codex" && s.Provider != state.ProviderCodex {
-				continue
-			}
+	// The inbox is a flat list ordered by urgency and wait time
+	if m.inbox {
+		filtered = m.inboxSessions(filtered, time.Now())
+		sortInbox(filtered)
+		m.groups = groupSessions(filtered, "")
+	} else {
+		sortSessions(filtered, m.sortBy)
+		// Move pinned sessions to the top, then lay out in display order so
+		// the cursor index matches what is drawn
+		m.groups = groupSessions(m.applyPinnedFirst(filtered), m.groupBy)
+	}
+	m.filteredSessions = make([]state.SessionView, 0, len(filtered))
+	for _, g := range m.groups {
+		for _, b := range g.Branches {
+			m.filteredSessions = append(m.filteredSessions, b.Sessions...)
+		}
+	}
 
-			// Otherwise fuzzy match on project/ID
-			if !strings.Contains(strings.ToLower(s.Project), query) &&
-				!strings.Contains(strings.ToLower(s.ID), query) {
-				continue
+	// Forget selections that are no longer visible
+	for _, id := range m.appState.SelectedIDs() {
+		found := false
+		for _, s := range m.filteredSessions {
+			if sessionKey(s) == id {
+				found = true
+				break
 			}
 		}
-
-		filtered = append(filtered, s)
+		if !found {
+			m.appState.ToggleSelect(id)
+		}
 	}
 
-	// Sort: needs input first, then active, then idle, then by recency
-	sortByUrgency(filtered)
-
-	// Move pinned sessions to the top
-	m.filteredSessions = m.applyPinnedFirst(filtered)
-
 	// Adjust cursor if out of bounds
-	if m.cursor >= len(filtered) {
-		m.cursor = len(filtered) - 1
+	if m.cursor >= len(m.filteredSessions) {
+		m.cursor = len(m.filteredSessions) - 1
 	}
 	if m.cursor < 0 {
 		m.cursor = 0
 	}
+	if m.targetCursor >= len(m.filteredSessions) || m.targetCursor < 0 {
+		m.targetCursor = m.cursor
+	}
+}
+
+// applyFilterKeepCursor re-applies the filters, then moves the cursor back to
+// the session it was on (if still listed)
+func (m *Model) applyFilterKeepCursor() {
+	key := ""
+	if s := m.selectedSession(); s != nil {
+		key = sessionKey(*s)
+	}
+	m.applyFilter()
+	for i, s := range m.filteredSessions {
+		if key != "" && sessionKey(s) == key {
+			m.cursor, m.targetCursor = i, i
+			return
+		}
+	}
 }
 
 type branchGroup struct {
@@ -704,56 +1404,101 @@ type branchGroup struct {
 	Sessions []state.SessionView
 }
 
-type projectGroup struct {
-	Project  string
+// sessionGroup is one group in the list; only project grouping has more
+// than one (branch) sub-group
+type sessionGroup struct {
+	Label    string
 	Branches []branchGroup
 }
 
-func (m *Model) groupByProjectAndBranch() []projectGroup {
-	// First level: group by project
-	projectOrder := []string{}
-	projectMap := make(map[string]map[string][]state.SessionView) // project -> branch -> sessions
+// groupSessions groups sessions (preserving first-seen order) by project and
+// branch, or by provider/status/day/hour; "" yields a single unlabeled group
+func groupSessions(sessions []state.SessionView, groupBy string) []sessionGroup {
+	if groupBy == "" {
+		return []sessionGroup{{Branches: []branchGroup{{Sessions: sessions}}}}
+	}
 
-	for _, s := range m.filteredSessions {
-		proj := s.Project
-		if proj == "" {
-			proj = "unknown"
+	var order []string
+	byLabel := make(map[string]*sessionGroup)
+	for _, s := range sessions {
+		label := groupKey(s, groupBy)
+		g := byLabel[label]
+		if g == nil {
+			g = &sessionGroup{Label: label}
+			byLabel[label] = g
+			order = append(order, label)
 		}
-		branch := s.Branch
-		if branch == "" {
-			branch = "unknown"
+		branch := ""
+		if groupBy == "project" {
+			branch = widgets.Safe(s.Branch, "unknown")
 		}
-
-		if _, ok := projectMap[proj]; !ok {
-			projectOrder = append(projectOrder, proj)
-			projectMap[proj] = make(map[string][]state.SessionView)
+		idx := -1
+		for i := range g.Branches {
+			if g.Branches[i].Branch == branch {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			g.Branches = append(g.Branches, branchGroup{Branch: branch})
+			idx = len(g.Branches) - 1
 		}
-		projectMap[proj][branch] = append(projectMap[proj][branch], s)
+		g.Branches[idx].Sessions = append(g.Branches[idx].Sessions, s)
 	}
 
-	// Build result with branch ordering preserved
-	result := make([]projectGroup, 0, len(projectOrder))
-	for _, proj := range projectOrder {
-		branches := projectMap[proj]
-		branchOrder := []string{}
-		for branch := range branches {
-			branchOrder = append(branchOrder, branch)
-		}
+	result := make([]sessionGroup, 0, len(order))
+	for _, label := range order {
+		result = append(result, *byLabel[label])
+	}
+	return result
+}
 
-		branchGroups := make([]branchGroup, 0, len(branchOrder))
-		for _, branch := range branchOrder {
-			branchGroups = append(branchGroups, branchGroup{
-				Branch:   branch,
-				Sessions: branches[branch],
-			})
-		}
+func groupKey(s state.SessionView, groupBy string) string {
+	var key string
+	switch groupBy {
+	case "project":
+		key = s.Project
+	case "provider":
+		key = string(s.Provider)
+	case "status":
+		key = string(s.Status)
+	case "day":
+		key = s.LastSeen.In(time.Local).Format("2006-01-02")
+	case "hour":
+		key = s.LastSeen.In(time.Local).Format("2006-01-02 15:00")
+	}
+	return widgets.Safe(key, "unknown")
+}
 
-		result = append(result, projectGroup{
-			Project:  proj,
-			Branches: branchGroups,
-		})
+// sortSessions sorts by the given key; "urgency" puts sessions needing input
+// first. Ties fall back to most recent activity.
+func sortSessions(sessions []state.SessionView, sortBy string) {
+	if sortBy == "urgency" || sortBy == "" {
+		sortByUrgency(sessions)
+		return
 	}
-	return result
+	sort.SliceStable(sessions, func(i, j int) bool {
+		a, b := sessions[i], sessions[j]
+		switch sortBy {
+		case "status":
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+		case "provider":
+			if a.Provider != b.Provider {
+				return a.Provider < b.Provider
+			}
+		case "cost":
+			if a.Cost != b.Cost {
+				return a.Cost > b.Cost
+			}
+		case "project":
+			if pa, pb := strings.ToLower(a.Project), strings.ToLower(b.Project); pa != pb {
+				return pa < pb
+			}
+		}
+		return a.LastSeen.After(b.LastSeen)
+	})
 }
 
 // sortByUrgency sorts sessions: needs input first, then active, then idle
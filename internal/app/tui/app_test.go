@@ -0,0 +1,355 @@
+package tui
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/components"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+// settle runs the cursor animation to its end
+func settle(m *Model) {
+	for i := 0; m.animating && i < 1000; i++ {
+		m.Update(AnimationTickMsg{})
+	}
+}
+
+// shownKeys lists the keys of the sessions in display order
+func shownKeys(m *Model) []string {
+	var keys []string
+	for _, s := range m.filteredSessions {
+		keys = append(keys, s.Key)
+	}
+	return keys
+}
+
+func TestPaletteRunsCommands(t *testing.T) {
+	m := newTestModel(t, Config{}, testSessions()...)
+
+	m.Update(key(":"))
+	if !m.paletteOpen {
+		t.Fatalf("':' should open the palette")
+	}
+	typeKeys(m, "gro")
+	m.Update(key("tab"))
+	if got := m.palette.Value(); got != "group " {
+		t.Fatalf("tab completed to %q, want %q", got, "group ")
+	}
+	typeKeys(m, "provider")
+	m.Update(key("enter"))
+	if m.paletteOpen || m.groupBy != "provider" || m.notice != "Group: provider" {
+		t.Fatalf("after :group provider: open=%v group=%q notice=%q", m.paletteOpen, m.groupBy, m.notice)
+	}
+
+	m.Update(key(":"))
+	typeKeys(m, "sort nope")
+	m.Update(key("enter"))
+	if m.sortBy != "urgency" || m.notice != "Unknown sort: nope" {
+		t.Fatalf("an unknown sort should be reported: sort=%q notice=%q", m.sortBy, m.notice)
+	}
+
+	m.Update(key(":"))
+	typeKeys(m, "dash")
+	m.Update(key("esc"))
+	if m.paletteOpen || m.showDashboard || m.palette.Value() != "" {
+		t.Fatalf("esc should close the palette without running it")
+	}
+	m.Update(key(":"))
+	typeKeys(m, "dash")
+	m.Update(key("enter"))
+	if !m.showDashboard {
+		t.Fatalf(":dash should open the dashboard")
+	}
+}
+
+func TestSortGroupViewCycling(t *testing.T) {
+	m := newTestModel(t, Config{}, testSessions()...)
+	for i, want := range []string{"last_seen", "status", "provider", "cost", "project", "urgency"} {
+		m.Update(key("s"))
+		if m.sortBy != want {
+			t.Fatalf("sort press %d = %q, want %q", i+1, m.sortBy, want)
+		}
+	}
+	for i, want := range []string{"", "provider", "status", "day", "hour", "project"} {
+		m.Update(key("g"))
+		if m.groupBy != want {
+			t.Fatalf("group press %d = %q, want %q", i+1, m.groupBy, want)
+		}
+	}
+	for i, want := range []string{"full", "ultra", "compact"} {
+		m.Update(key("v"))
+		if m.viewMode != want {
+			t.Fatalf("view press %d = %q, want %q", i+1, m.viewMode, want)
+		}
+	}
+
+	m.Update(key("g"))
+	m.Update(key("s"))
+	m.Update(key("s"))
+	m.Update(key("s"))
+	if got := shownKeys(m); len(got) != 3 || m.groupBy != "" || m.sortBy != "provider" || got[2] != "codex:b2" {
+		t.Fatalf("ungrouped by provider, codex should come last: %v", got)
+	}
+}
+
+func TestFilterAndHotkeys(t *testing.T) {
+	m := newTestModel(t, Config{}, testSessions()...)
+
+	m.Update(key("/"))
+	typeKeys(m, "web")
+	if got := shownKeys(m); len(got) != 1 || got[0] != "codex:b2" {
+		t.Fatalf("typing filters as you go: %v", got)
+	}
+	m.Update(key("enter"))
+	if m.filterActive || m.filterQuery != "web" {
+		t.Fatalf("enter should keep the query: active=%v query=%q", m.filterActive, m.filterQuery)
+	}
+	m.Update(key("esc"))
+	if m.filterQuery != "" || len(m.filteredSessions) != 3 {
+		t.Fatalf("esc should clear the query: %q %v", m.filterQuery, shownKeys(m))
+	}
+
+	m.Update(key("/"))
+	typeKeys(m, "s:running")
+	m.Update(key("enter"))
+	if got := shownKeys(m); len(got) != 1 || got[0] != "claude:a1" {
+		t.Fatalf("s:running = %v", got)
+	}
+	m.Update(key("esc"))
+
+	m.Update(key("1"))
+	if got := shownKeys(m); len(got) != 2 {
+		t.Fatalf("1 should keep only claude: %v", got)
+	}
+	m.Update(key("W"))
+	if got := shownKeys(m); len(got) != 1 || got[0] != "claude:c3" {
+		t.Fatalf("1 then W should keep waiting claude sessions: %v", got)
+	}
+	m.Update(key("1"))
+	m.Update(key("W"))
+	if len(m.filteredSessions) != 3 {
+		t.Fatalf("pressing the hotkeys again should clear them: %v", shownKeys(m))
+	}
+}
+
+func TestProjectPickerAndDashboard(t *testing.T) {
+	m := newTestModel(t, Config{}, testSessions()...)
+
+	m.Update(key("p"))
+	if !m.projectsOpen {
+		t.Fatalf("p should open the picker")
+	}
+	typeKeys(m, "we")
+	if items := m.pickerItems(); len(items) != 1 || items[0].Name != "web" {
+		t.Fatalf("picker query: %+v", items)
+	}
+	m.Update(key("space"))
+	m.Update(key("esc"))
+	if m.projectsOpen {
+		t.Fatalf("esc should close the picker")
+	}
+	if got := shownKeys(m); len(got) != 1 || got[0] != "codex:b2" {
+		t.Fatalf("project filter from the picker: %v", got)
+	}
+
+	// Plain "a" in the picker clears the project filters
+	m.Update(key("p"))
+	m.Update(key("a"))
+	m.Update(key("esc"))
+	if len(m.filteredSessions) != 3 {
+		t.Fatalf("a should clear project filters: %v", shownKeys(m))
+	}
+
+	m.Update(key("tab"))
+	if !m.showDashboard {
+		t.Fatalf("tab should open the dashboard")
+	}
+	items := m.dashboardItems()
+	if len(items) != 2 {
+		t.Fatalf("dashboard items: %+v", items)
+	}
+	m.Update(key("down"))
+	want := items[1].Name
+	m.Update(key("enter"))
+	if m.showDashboard || !m.filters.ProjectFilter[want] {
+		t.Fatalf("enter should focus %q and close: open=%v filter=%v", want, m.showDashboard, m.filters.ProjectFilter)
+	}
+	m.Update(key("tab"))
+	m.Update(key("tab"))
+	if m.showDashboard {
+		t.Fatalf("tab should close the dashboard")
+	}
+}
+
+func TestSelectionUsesAppState(t *testing.T) {
+	m := newTestModel(t, Config{}, testSessions()...)
+
+	m.Update(key("space"))
+	m.Update(key("j"))
+	settle(m)
+	m.Update(key("space"))
+	if ids := m.appState.SelectedIDs(); len(ids) != 2 {
+		t.Fatalf("selected = %v", ids)
+	}
+	if targets := m.actionTargets(); len(targets) != 2 || targets[0].Key != m.filteredSessions[0].Key {
+		t.Fatalf("action targets should follow display order: %+v", targets)
+	}
+
+	// Selections that are filtered out are forgotten
+	first := m.filteredSessions[0].Key
+	m.Update(key("/"))
+	typeKeys(m, first[len("codex:"):])
+	m.Update(key("enter"))
+	if ids := m.appState.SelectedIDs(); len(ids) != 1 || ids[0] != first {
+		t.Fatalf("selected after filtering = %v", ids)
+	}
+
+	// esc clears the query first, then the selection
+	m.Update(key("esc"))
+	if len(m.appState.SelectedIDs()) != 1 {
+		t.Fatalf("the first esc should only clear the query")
+	}
+	m.Update(key("esc"))
+	if ids := m.appState.SelectedIDs(); ids != nil {
+		t.Fatalf("the second esc should clear the selection: %v", ids)
+	}
+}
+
+func TestMouseSelectsRowsAndTogglesFilters(t *testing.T) {
+	m := newTestModel(t, Config{}, testSessions()...)
+	m.View() // records which line shows which session
+
+	l := m.layout()
+	line := -1
+	for i, idx := range m.listRows {
+		if idx == 2 {
+			line = i
+		}
+	}
+	if line < 0 {
+		t.Fatalf("third session not on screen: %v", m.listRows)
+	}
+	click := tea.MouseMsg{X: l.listX + 2, Y: bodyTop + line, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+
+	m.Update(key("d"))
+	m.Update(click)
+	if m.cursor != 2 || m.showDetail {
+		t.Fatalf("a click should move the cursor: cursor=%d detail=%v", m.cursor, m.showDetail)
+	}
+	m.Update(click)
+	if !m.showDetail {
+		t.Fatalf("a double-click should open the detail pane")
+	}
+
+	m.Update(tea.MouseMsg{X: l.listX + 2, Y: bodyTop, Button: tea.MouseButtonWheelUp})
+	settle(m)
+	if m.cursor != 1 {
+		t.Fatalf("the wheel should move the cursor up, cursor=%d", m.cursor)
+	}
+
+	// Header counts toggle status filters
+	for x := 0; x < m.width; x++ {
+		if badge, ok := components.HeaderBadgeAt(m.filteredSessions, m.styles, m.width, x); ok && badge == components.BadgeRunning {
+			m.Update(tea.MouseMsg{X: x, Y: 0, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+			break
+		}
+	}
+	if !m.filters.StatusFilter[state.StatusRunning] || len(m.filteredSessions) != 1 {
+		t.Fatalf("clicking the running count should filter: %v", m.filters.StatusFilter)
+	}
+	m.filters.Clear()
+	m.applyFilter()
+
+	// Sidebar entries toggle provider filters
+	m.Update(key("b"))
+	m.Update(tea.MouseMsg{X: 1, Y: bodyTop + 2, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if !m.filters.ProviderFilter[state.ProviderCodex] {
+		t.Fatalf("clicking Codex in the sidebar should filter: %v", m.filters.ProviderFilter)
+	}
+
+	// Clicks are ignored while the palette has the focus
+	m.Update(key(":"))
+	m.Update(click)
+	if !m.paletteOpen {
+		t.Fatalf("a click should not close the palette")
+	}
+}
+
+func TestTagQueryIgnoresCase(t *testing.T) {
+	f := state.NewFilterState()
+	f.TextQuery = "t:urgent"
+	f.ParseQueryMode()
+	if !f.MatchesQuery(state.SessionView{Tags: []string{"Urgent"}}) {
+		t.Fatalf("t:urgent should match the tag Urgent")
+	}
+}
+
+func TestSeenMarkLapsesWhenStatusStartsAgain(t *testing.T) {
+	m := newTestModel(t, Config{}, testSessions()...)
+	m.Update(key("i"))
+	for i := 0; i < 3 && m.selectedSession().Key != "claude:c3"; i++ {
+		m.Update(key("j"))
+		settle(m)
+	}
+	m.Update(key("x"))
+	if got := shownKeys(m); len(got) != 1 || got[0] != "codex:b2" {
+		t.Fatalf("a seen session should leave the inbox: %v", got)
+	}
+
+	// Same status on the next refresh: the mark holds
+	m.Update(SessionsMsg{Sessions: testSessions()})
+	if len(m.seen) != 1 {
+		t.Fatalf("the mark should hold while the status does")
+	}
+
+	// Running and waiting again between refreshes, with the inbox off
+	m.Update(key("i"))
+	again := testSessions()
+	again[2].StatusSince = time.Now()
+	m.Update(SessionsMsg{Sessions: again})
+	if len(m.seen) != 0 {
+		t.Fatalf("a status that started again should drop the mark: %v", m.seen)
+	}
+	m.Update(key("i"))
+	if got := shownKeys(m); len(got) != 2 {
+		t.Fatalf("the session waiting again should be back in the inbox: %v", got)
+	}
+}
+
+func TestExportAsksBeforeOverwriting(t *testing.T) {
+	m := newTestModel(t, Config{}, testSessions()...)
+	t.Chdir(t.TempDir()) // the palette input is too short for a temp dir path
+	path := "sessions.json"
+	export := func() {
+		m.Update(key(":"))
+		typeKeys(m, "export json "+path)
+		m.Update(key("enter"))
+	}
+
+	export()
+	if b, err := os.ReadFile(path); err != nil || !strings.Contains(string(b), `"provider"`) {
+		t.Fatalf("a new file should be written right away: %q %v", b, err)
+	}
+
+	if err := os.WriteFile(path, []byte("keep me"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	export()
+	if m.confirm == nil {
+		t.Fatalf("an existing file should ask before it is overwritten")
+	}
+	m.Update(key("n"))
+	if b, _ := os.ReadFile(path); string(b) != "keep me" || m.notice != "Cancelled" {
+		t.Fatalf("cancelling should keep the file: %q notice=%q", b, m.notice)
+	}
+
+	export()
+	m.Update(key("y"))
+	if b, _ := os.ReadFile(path); !strings.Contains(string(b), `"provider"`) || !strings.HasPrefix(m.notice, "Exported 1 session") {
+		t.Fatalf("confirming should overwrite: %q notice=%q", b, m.notice)
+	}
+}
@@ -0,0 +1,341 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+	"github.com/vburojevic/aistat/internal/app/tui/widgets"
+)
+
+// confirmAction is a destructive bulk action waiting for y/n
+type confirmAction struct {
+	prompt string
+	run    func() tea.Cmd
+}
+
+// actionTargets returns the multi-selected sessions in display order, or the
+// session under the cursor when nothing is selected
+func (m *Model) actionTargets() []state.SessionView {
+	var out []state.SessionView
+	for _, s := range m.filteredSessions {
+		if m.appState.IsSelected(sessionKey(s)) {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		if s := m.selectedSession(); s != nil {
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+func countLabel(n int) string {
+	if n == 1 {
+		return "1 session"
+	}
+	return widgets.FormatInt(n) + " sessions"
+}
+
+// handleConfirmKeys runs the pending action on y/enter; any other key cancels
+func (m *Model) handleConfirmKeys(msg tea.KeyMsg) tea.Cmd {
+	c := m.confirm
+	m.confirm = nil
+	switch msg.String() {
+	case "y", "Y", "enter":
+		return c.run()
+	}
+	m.notice = "Cancelled"
+	return nil
+}
+
+// endTargets marks the targets ended after confirmation
+func (m *Model) endTargets() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	if m.cfg.EndSessions == nil {
+		m.notice = "Marking sessions ended is not available"
+		return
+	}
+	m.confirm = &confirmAction{
+		prompt: "Mark " + countLabel(len(targets)) + " ended?",
+		run: func() tea.Cmd {
+			keys := make([]string, len(targets))
+			for i, s := range targets {
+				keys[i] = s.Key
+			}
+			if err := m.cfg.EndSessions(keys); err != nil {
+				m.notice = err.Error()
+			} else {
+				m.notice = "Marked " + countLabel(len(targets)) + " ended"
+			}
+			m.appState.ClearSelection()
+			m.refreshing = true
+			return m.fetchSessionsCmd()
+		},
+	}
+}
+
+// hideTargets hides the targets from every view (until :unhide) after
+// confirmation
+func (m *Model) hideTargets() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	m.confirm = &confirmAction{
+		prompt: "Hide " + countLabel(len(targets)) + "?",
+		run: func() tea.Cmd {
+			for _, s := range targets {
+				m.hidden[sessionKey(s)] = true
+				if m.appState.IsSelected(sessionKey(s)) {
+					m.appState.ToggleSelect(sessionKey(s))
+				}
+			}
+			m.applyFilter()
+			m.notice = "Hid " + countLabel(len(targets)) + " (:unhide to restore)"
+			return nil
+		},
+	}
+}
+
+// unhideAll brings back every hidden session
+func (m *Model) unhideAll() {
+	n := len(m.hidden)
+	m.hidden = make(map[string]bool)
+	m.applyFilter()
+	m.notice = "Unhid " + countLabel(n)
+}
+
+// tagTargets adds a tag to the targets
+func (m *Model) tagTargets(tag string) {
+	tag = normalizeTag(tag)
+	if tag == "" {
+		m.notice = "Usage: tag <name>"
+		return
+	}
+	targets := m.actionTargets()
+	for _, s := range targets {
+		key := sessionKey(s)
+		if indexOf(m.tags[key], tag) < 0 {
+			m.tags[key] = append(m.tags[key], tag)
+			sort.Strings(m.tags[key])
+		}
+	}
+	m.applyFilter()
+	m.notice = "Tagged " + countLabel(len(targets)) + " #" + tag
+}
+
+// untagTargets removes a tag (or all tags when empty) from the targets
+func (m *Model) untagTargets(tag string) {
+	tag = normalizeTag(tag)
+	targets := m.actionTargets()
+	for _, s := range targets {
+		key := sessionKey(s)
+		if tag == "" {
+			delete(m.tags, key)
+			continue
+		}
+		if i := indexOf(m.tags[key], tag); i >= 0 {
+			m.tags[key] = append(m.tags[key][:i], m.tags[key][i+1:]...)
+		}
+		if len(m.tags[key]) == 0 {
+			delete(m.tags, key)
+		}
+	}
+	m.applyFilter()
+	m.notice = "Untagged " + countLabel(len(targets))
+}
+
+// normalizeTag keeps the first word of a tag, without a leading #
+func normalizeTag(tag string) string {
+	fields := strings.Fields(tag)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimLeft(fields[0], "#"))
+}
+
+// openTargets opens the transcript/log of every target
+func (m *Model) openTargets() tea.Cmd {
+	targets := m.actionTargets()
+	if len(targets) == 0 || m.cfg.OpenSession == nil {
+		return nil
+	}
+	opened := 0
+	for _, s := range targets {
+		if err := m.cfg.OpenSession(s.Key); err != nil {
+			m.notice = "Open failed: " + err.Error()
+			continue
+		}
+		opened++
+	}
+	if opened == len(targets) {
+		m.notice = "Opened " + widgets.FormatInt(opened) + " log(s)"
+	}
+	return nil
+}
+
+// copyResumeCommands copies a resume command line per target
+func (m *Model) copyResumeCommands() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	if m.cfg.ResumeCommand == nil {
+		m.notice = "Resume commands are not available"
+		return
+	}
+	var lines []string
+	for _, s := range targets {
+		cmd, err := m.cfg.ResumeCommand(s.Key)
+		if err != nil {
+			m.notice = err.Error()
+			return
+		}
+		lines = append(lines, cmd)
+	}
+	if err := copyToClipboard(strings.Join(lines, "\n")); err != nil {
+		m.notice = "Copy failed: " + err.Error()
+		return
+	}
+	m.notice = "Copied " + widgets.FormatInt(len(lines)) + " resume command(s)"
+}
+
+// exportRow is one session in a JSON export
+type exportRow struct {
+	Provider      string    `json:"provider"`
+	ID            string    `json:"id"`
+	Status        string    `json:"status"`
+	Reason        string    `json:"reason,omitempty"`
+	Project       string    `json:"project,omitempty"`
+	Dir           string    `json:"dir,omitempty"`
+	Branch        string    `json:"branch,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	CostUSD       float64   `json:"cost_usd,omitempty"`
+	ContextTokens int       `json:"context_tokens,omitempty"`
+	ContextWindow int       `json:"context_window,omitempty"`
+	LastSeen      time.Time `json:"last_seen"`
+	Tags          []string  `json:"tags,omitempty"`
+}
+
+// exportTargets writes the targets to a JSON or Markdown file (default:
+// aistat-export-<time>.<ext> in the current directory)
+func (m *Model) exportTargets(format, path string) {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	var (
+		b   []byte
+		err error
+	)
+	switch format {
+	case "json":
+		b, err = exportJSON(targets)
+	case "md", "markdown":
+		format = "md"
+		b = exportMarkdown(targets)
+	default:
+		m.notice = "Usage: export json|md [path]"
+		return
+	}
+	if err != nil {
+		m.notice = "Export failed: " + err.Error()
+		return
+	}
+	if path == "" {
+		path = "aistat-export-" + time.Now().Format("20060102-150405") + "." + format
+	}
+	exported := func(err error) {
+		if err != nil {
+			m.notice = "Export failed: " + err.Error()
+			return
+		}
+		m.notice = "Exported " + countLabel(len(targets)) + " to " + path
+	}
+	err = writeExport(path, b, false)
+	if errors.Is(err, os.ErrExist) {
+		m.confirm = &confirmAction{
+			prompt: path + " exists. Overwrite it?",
+			run: func() tea.Cmd {
+				exported(writeExport(path, b, true))
+				return nil
+			},
+		}
+		return
+	}
+	exported(err)
+}
+
+// writeExport writes an export file, replacing an existing one only when
+// overwrite is set
+func writeExport(path string, b []byte, overwrite bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func exportJSON(sessions []state.SessionView) ([]byte, error) {
+	rows := make([]exportRow, len(sessions))
+	for i, s := range sessions {
+		rows[i] = exportRow{
+			Provider:      string(s.Provider),
+			ID:            stripANSI(s.ID),
+			Status:        string(s.Status),
+			Reason:        s.Reason,
+			Project:       s.Project,
+			Dir:           s.Dir,
+			Branch:        s.Branch,
+			Model:         s.Model,
+			CostUSD:       s.Cost,
+			ContextTokens: s.ContextTokens,
+			ContextWindow: s.ContextWindow,
+			LastSeen:      s.LastSeen,
+			Tags:          s.Tags,
+		}
+	}
+	b, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func exportMarkdown(sessions []state.SessionView) []byte {
+	var b strings.Builder
+	b.WriteString("| Provider | Session | Status | Project | Branch | Model | Cost | Last seen | Tags |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|---|\n")
+	cell := func(s string) string {
+		return strings.ReplaceAll(strings.TrimSpace(s), "|", `\|`)
+	}
+	for _, s := range sessions {
+		tags := ""
+		if len(s.Tags) > 0 {
+			tags = "#" + strings.Join(s.Tags, " #")
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			s.Provider, cell(stripANSI(s.ID)), s.Status, cell(s.Project), cell(s.Branch), cell(s.Model),
+			widgets.FormatCost(s.Cost), s.LastSeen.In(time.Local).Format("2006-01-02 15:04"), tags)
+	}
+	return []byte(b.String())
+}
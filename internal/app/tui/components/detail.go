@@ -26,11 +26,31 @@ func RenderDetail(s *state.SessionView, styles theme.Styles, width int) string {
 		b.WriteString(renderRow("Reason", s.Reason, styles))
 	}
 
+	// Held permission prompt
+	if p := s.Pending; p != nil {
+		action := p.Tool
+		if p.Summary != "" {
+			action += ": " + truncate(p.Summary, 120)
+		}
+		left := time.Until(p.ExpiresAt)
+		if left < 0 {
+			left = 0
+		}
+		b.WriteString(renderRow("Pending", styles.DotNeedsInput.Render(action), styles))
+		b.WriteString(renderRow("", styles.HelpKey.Render("Y")+" approve  "+styles.HelpKey.Render("X")+" deny  "+
+			styles.Muted.Render("("+widgets.FormatAge(left)+" left)"), styles))
+	}
+
 	// Project
 	if s.Project != "" {
 		b.WriteString(renderRow("Project", s.Project, styles))
 	}
 
+	// Tags
+	if len(s.Tags) > 0 {
+		b.WriteString(renderRow("Tags", styles.FilterText.Render("#"+strings.Join(s.Tags, " #")), styles))
+	}
+
 	// Branch
 	if s.Branch != "" {
 		b.WriteString(renderRow("Branch", s.Branch, styles))
@@ -164,6 +184,16 @@ func RenderFilteredEmpty(query string, styles theme.Styles) string {
 	return strings.Join(lines, "\n")
 }
 
+// RenderInboxEmpty renders when nothing in the inbox needs attention
+func RenderInboxEmpty(snoozed int, styles theme.Styles) string {
+	lines := []string{styles.Title.Render("Inbox zero"), "", "Nothing is waiting on you."}
+	if snoozed > 0 {
+		lines = append(lines, "", styles.Muted.Render(fmt.Sprintf("%d snoozed (:unsnooze to bring them back)", snoozed)))
+	}
+	lines = append(lines, "", styles.Muted.Render("Press i for all sessions"))
+	return strings.Join(lines, "\n")
+}
+
 // statusLabel returns a human-readable label for a status
 func statusLabel(s state.Status) string {
 	ui := widgets.ToUIStatus(s)
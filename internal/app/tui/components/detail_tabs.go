@@ -0,0 +1,174 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
+	"github.com/vburojevic/aistat/internal/app/tui/widgets"
+)
+
+// Detail pane tabs
+const (
+	TabOverview = iota
+	TabConversation
+	TabUsage
+	TabTimeline
+)
+
+// DetailTabNames are the tab titles in display order
+var DetailTabNames = []string{"Overview", "Conversation", "Usage", "Timeline"}
+
+// RenderDetailTabs renders the tab strip above the detail pane
+func RenderDetailTabs(active int, styles theme.Styles) string {
+	parts := make([]string, len(DetailTabNames))
+	for i, name := range DetailTabNames {
+		if i == active {
+			parts[i] = styles.Title.Render(name)
+		} else {
+			parts[i] = styles.Muted.Render(name)
+		}
+	}
+	return strings.Join(parts, styles.Muted.Render(" │ "))
+}
+
+// RenderConversation renders recent turns, newest at the bottom. scroll is the
+// number of lines scrolled up from the end.
+func RenderConversation(d *state.SessionDetail, scroll int, styles theme.Styles, width, height int) string {
+	if d == nil || len(d.Conversation) == 0 {
+		return styles.Muted.Render("No conversation found in the transcript tail")
+	}
+
+	wrap := lipgloss.NewStyle().Width(widgets.MaxInt(10, width-2))
+	var lines []string
+	for _, t := range d.Conversation {
+		head := styles.Label.Render(turnLabel(t.Role))
+		if !t.At.IsZero() {
+			head += styles.Muted.Render(t.At.In(time.Local).Format("15:04:05"))
+		}
+		lines = append(lines, head)
+		body := truncate(t.Text, 2000)
+		if t.Role == "tool" {
+			body = styles.Muted.Render(truncate(t.Text, 200))
+		}
+		lines = append(lines, strings.Split(wrap.Render(body), "\n")...)
+		lines = append(lines, "")
+	}
+	if d.Redacted {
+		lines = append(lines, styles.Muted.Render("Messages are redacted (run with --redact=false to read them)"))
+	}
+
+	if height <= 0 || len(lines) <= height {
+		return strings.Join(lines, "\n")
+	}
+	maxScroll := len(lines) - height
+	scroll = widgets.ClampInt(scroll, 0, maxScroll)
+	end := len(lines) - scroll
+	return strings.Join(lines[end-height:end], "\n")
+}
+
+// RenderUsage renders tokens, the context-window gauge, cost and turn timing
+func RenderUsage(s *state.SessionView, d *state.SessionDetail, styles theme.Styles, width int) string {
+	if s == nil {
+		return RenderEmptyDetail(styles)
+	}
+	var u state.Usage
+	if d != nil {
+		u = d.Usage
+	}
+
+	var b strings.Builder
+	if u.ContextWindow > 0 {
+		ratio := float64(u.ContextTokens) / float64(u.ContextWindow)
+		b.WriteString(renderRow("Context", fmt.Sprintf("%s / %s (%.0f%%)",
+			widgets.FormatTokens(u.ContextTokens), widgets.FormatTokens(u.ContextWindow), ratio*100), styles))
+		b.WriteString(renderRow("", widgets.Gauge(ratio, widgets.ClampInt(width-14, 10, 40), styles), styles))
+	} else if u.ContextTokens > 0 {
+		b.WriteString(renderRow("Context", widgets.FormatTokens(u.ContextTokens), styles))
+	}
+	if u.InputTokens+u.OutputTokens > 0 {
+		b.WriteString(renderRow("Input", widgets.FormatTokens(u.InputTokens), styles))
+		b.WriteString(renderRow("Output", widgets.FormatTokens(u.OutputTokens), styles))
+	}
+	if u.CacheReadTokens+u.CacheCreateTokens > 0 {
+		b.WriteString(renderRow("Cache", fmt.Sprintf("%s read, %s written",
+			widgets.FormatTokens(u.CacheReadTokens), widgets.FormatTokens(u.CacheCreateTokens)), styles))
+	}
+
+	cost := u.Cost
+	if cost == 0 {
+		cost = s.Cost
+	}
+	if cost > 0 {
+		line := fmt.Sprintf("$%.2f", cost)
+		if u.Duration >= time.Minute {
+			line += styles.Muted.Render(fmt.Sprintf("  ($%.2f/hour)", cost/u.Duration.Hours()))
+		}
+		b.WriteString(renderRow("Cost", line, styles))
+	}
+	if u.Duration > 0 {
+		line := widgets.FormatAge(u.Duration)
+		if u.APIDuration > 0 {
+			line += styles.Muted.Render("  (API " + widgets.FormatAge(u.APIDuration) + ")")
+		}
+		b.WriteString(renderRow("Duration", line, styles))
+	}
+	if u.LinesAdded+u.LinesRemoved > 0 {
+		b.WriteString(renderRow("Lines", styles.DotActive.Render(fmt.Sprintf("+%d", u.LinesAdded))+" "+
+			styles.ErrorText.Render(fmt.Sprintf("-%d", u.LinesRemoved)), styles))
+	}
+	if s.TurnCount > 0 {
+		b.WriteString(renderRow("Turns", fmt.Sprintf("%d", s.TurnCount), styles))
+		if s.LastTurn > 0 {
+			b.WriteString(renderRow("Turn time", fmt.Sprintf("last %s, mean %s, p95 %s",
+				widgets.FormatAge(s.LastTurn), widgets.FormatAge(s.MeanTurn), widgets.FormatAge(s.P95Turn)), styles))
+		}
+	}
+
+	if b.Len() == 0 {
+		return styles.Muted.Render("No usage reported for this session yet")
+	}
+	return b.String()
+}
+
+// RenderTimeline renders status transitions, newest first, with the time
+// spent in each status
+func RenderTimeline(d *state.SessionDetail, styles theme.Styles, height int, now time.Time) string {
+	if d == nil || len(d.Timeline) == 0 {
+		return styles.Muted.Render("No status transitions recorded")
+	}
+
+	var lines []string
+	for i := len(d.Timeline) - 1; i >= 0; i-- {
+		t := d.Timeline[i]
+		until := now
+		if i+1 < len(d.Timeline) {
+			until = d.Timeline[i+1].At
+		}
+		line := styles.Muted.Render(t.At.In(time.Local).Format("15:04:05")) + " " +
+			widgets.StatusDot(t.Status, styles) + " " + widgets.PadRight(string(t.Status), 15) +
+			styles.Muted.Render(widgets.PadLeft(widgets.FormatAge(until.Sub(t.At)), 7))
+		if t.Reason != "" {
+			line += "  " + t.Reason
+		}
+		lines = append(lines, line)
+		if height > 0 && len(lines) >= height {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func turnLabel(role string) string {
+	switch role {
+	case "user":
+		return "You "
+	case "assistant":
+		return "AI "
+	default:
+		return "Tool "
+	}
+}
@@ -1,9 +1,18 @@
 package components
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vburojevic/aistat/internal/app/tui/state"
 	"github.com/vburojevic/aistat/internal/app/tui/theme"
+	"github.com/vburojevic/aistat/internal/app/tui/widgets"
 )
 
+// SidebarWidth is the width of the filter sidebar (including border)
+const SidebarWidth = 26
+
 // RenderFilterBar renders the filter input bar
 // Shows "/ " prompt followed by the current filter text
 func RenderFilterBar(query string, active bool, styles theme.Styles) string {
@@ -16,3 +25,138 @@ func RenderFilterBar(query string, active bool, styles theme.Styles) string {
 	}
 	return prompt + styles.FilterText.Render(query)
 }
+
+// RenderFilterPills renders the active provider/status/project filters and
+// the selection and hidden counts as compact pills (empty when nothing is
+// active)
+func RenderFilterPills(filters *state.FilterState, selected, hidden int, styles theme.Styles) string {
+	var pills []string
+	if selected > 0 {
+		pills = append(pills, styles.Selected.Render(fmt.Sprintf("selected:%d", selected)))
+	}
+	if hidden > 0 {
+		pills = append(pills, styles.Muted.Render(fmt.Sprintf("hidden:%d", hidden)))
+	}
+
+	var providers []string
+	for _, p := range filters.ActiveProviders() {
+		providers = append(providers, string(p))
+	}
+	sort.Strings(providers)
+	for _, p := range providers {
+		pills = append(pills, styles.FilterText.Render("provider:"+p))
+	}
+
+	var statuses []string
+	for _, s := range filters.ActiveStatuses() {
+		statuses = append(statuses, string(s))
+	}
+	sort.Strings(statuses)
+	for _, s := range statuses {
+		pills = append(pills, styles.FilterText.Render("status:"+s))
+	}
+
+	projects := filters.ActiveProjects()
+	limit := widgets.MinInt(3, len(projects))
+	for _, p := range projects[:limit] {
+		pills = append(pills, styles.FilterText.Render("project:"+p))
+	}
+	if len(projects) > limit {
+		pills = append(pills, styles.Muted.Render(fmt.Sprintf("+%d more", len(projects)-limit)))
+	}
+
+	return strings.Join(pills, " ")
+}
+
+// sidebarStatuses maps status filter actions to statuses (in display order)
+var sidebarStatuses = []struct {
+	Action string
+	Label  string
+	Status state.Status
+}{
+	{"status_running", "running", state.StatusRunning},
+	{"status_waiting", "waiting", state.StatusWaiting},
+	{"status_approval", "approval", state.StatusApproval},
+	{"status_stale", "stale", state.StatusStale},
+	{"status_ended", "ended", state.StatusEnded},
+	{"status_attention", "attn", state.StatusNeedsAttn},
+}
+
+// RenderSidebar renders the filter sidebar: providers, statuses and the
+// busiest projects with their hotkeys and counts. keyFor returns the key
+// bound to a keymap action.
+func RenderSidebar(sessions []state.SessionView, filters *state.FilterState, keyFor func(action string) string, styles theme.Styles, height int) string {
+	providerCounts := map[state.Provider]int{}
+	statusCounts := map[state.Status]int{}
+	for _, s := range sessions {
+		providerCounts[s.Provider]++
+		statusCounts[s.Status]++
+	}
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Providers"))
+	lines = append(lines, sidebarLine(keyFor("provider_claude"), "claude", providerCounts[state.ProviderClaude], filters.ProviderFilter[state.ProviderClaude], styles))
+	lines = append(lines, sidebarLine(keyFor("provider_codex"), "codex", providerCounts[state.ProviderCodex], filters.ProviderFilter[state.ProviderCodex], styles))
+	lines = append(lines, "")
+
+	lines = append(lines, styles.Title.Render("Status"))
+	for _, st := range sidebarStatuses {
+		lines = append(lines, sidebarLine(keyFor(st.Action), st.Label, statusCounts[st.Status], filters.StatusFilter[st.Status], styles))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, styles.Title.Render("Projects"))
+	for _, it := range sidebarProjects(sessions) {
+		name := widgets.TruncateString(it.Name, 12)
+		lines = append(lines, sidebarLine(keyFor("projects"), name, it.Count, filters.ProjectFilter[strings.ToLower(it.Name)], styles))
+	}
+
+	return styles.List.Width(SidebarWidth-2).Height(height).Padding(0, 1).Render(strings.Join(lines, "\n"))
+}
+
+// sidebarProjects returns the busiest projects shown in the sidebar
+func sidebarProjects(sessions []state.SessionView) []state.ProjectItem {
+	items := state.BuildProjectItems(sessions)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Count > items[j].Count })
+	if len(items) > 6 {
+		items = items[:6]
+	}
+	return items
+}
+
+// SidebarTarget is the filter a sidebar line toggles (one field is set)
+type SidebarTarget struct {
+	Provider state.Provider
+	Status   state.Status
+	Project  string
+}
+
+// SidebarTargetAt maps a sidebar content line (0 = "Providers") to the
+// filter it shows, following RenderSidebar's layout
+func SidebarTargetAt(sessions []state.SessionView, line int) (SidebarTarget, bool) {
+	const (
+		providersAt = 1                   // after the "Providers" title
+		statusesAt  = providersAt + 2 + 2 // two providers, blank, title
+		projectsAt  = statusesAt + 6 + 2  // six statuses, blank, title
+	)
+	switch {
+	case line == providersAt:
+		return SidebarTarget{Provider: state.ProviderClaude}, true
+	case line == providersAt+1:
+		return SidebarTarget{Provider: state.ProviderCodex}, true
+	case line >= statusesAt && line < statusesAt+len(sidebarStatuses):
+		return SidebarTarget{Status: sidebarStatuses[line-statusesAt].Status}, true
+	case line >= projectsAt:
+		if items := sidebarProjects(sessions); line-projectsAt < len(items) {
+			return SidebarTarget{Project: items[line-projectsAt].Name}, true
+		}
+	}
+	return SidebarTarget{}, false
+}
+
+func sidebarLine(key, label string, count int, active bool, styles theme.Styles) string {
+	if active {
+		return styles.Selected.Render(fmt.Sprintf("%s ● %-12s %3d", key, label, count))
+	}
+	return styles.HelpKey.Width(0).Render(key) + fmt.Sprintf("   %-12s %3d", label, count)
+}
@@ -0,0 +1,49 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
+	"github.com/vburojevic/aistat/internal/app/tui/widgets"
+)
+
+// RenderFollow renders the live transcript follower: a title line, then one
+// line per prompt, tool call or reply, newest at the bottom
+func RenderFollow(s *state.SessionView, turns []state.Turn, err error, styles theme.Styles, width, height int) string {
+	if s == nil {
+		return styles.Muted.Render("Select a session to follow its transcript")
+	}
+	title := styles.Title.Render("Following ") + styles.Muted.Render(widgets.TruncateString(string(s.Provider)+" "+s.ID, widgets.MaxInt(10, width-12)))
+	if height <= 1 {
+		return title
+	}
+
+	var lines []string
+	switch {
+	case err != nil:
+		lines = append(lines, styles.ErrorText.Render(err.Error()))
+	case len(turns) == 0:
+		lines = append(lines, styles.Muted.Render("Waiting for new events…"))
+	default:
+		label := styles.Label.Width(6)
+		textWidth := widgets.MaxInt(10, width-15) // time, label and spacing
+		for _, t := range turns {
+			stamp := strings.Repeat(" ", 8)
+			if !t.At.IsZero() {
+				stamp = t.At.In(time.Local).Format("15:04:05")
+			}
+			text := widgets.TruncateString(strings.Join(strings.Fields(t.Text), " "), textWidth)
+			if t.Role == "tool" {
+				text = styles.Muted.Render(text)
+			}
+			lines = append(lines, styles.Muted.Render(stamp)+" "+label.Render(turnLabel(t.Role))+text)
+		}
+	}
+
+	if len(lines) > height-1 {
+		lines = lines[len(lines)-(height-1):]
+	}
+	return title + "\n" + strings.Join(lines, "\n")
+}
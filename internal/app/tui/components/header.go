@@ -32,53 +32,83 @@ func CountStatuses(sessions []state.SessionView) StatusCounts {
 	return c
 }
 
-// RenderHeader renders the header: title + status counts + urgent badge
-func RenderHeader(sessions []state.SessionView, styles theme.Styles, width int) string {
-	title := styles.Title.Render("aistat")
+// HeaderBadge identifies a clickable status count in the header
+type HeaderBadge string
+
+const (
+	BadgeRunning    HeaderBadge = "running"
+	BadgeIdle       HeaderBadge = "idle"
+	BadgeNeedsInput HeaderBadge = "needs_input"
+)
+
+type headerPart struct {
+	text  string
+	badge HeaderBadge
+}
+
+// headerLayout returns the header pieces and the gaps between them; gap < 0
+// means only the title fits
+func headerLayout(sessions []state.SessionView, styles theme.Styles, width int) (title string, parts []headerPart, gap1, gap2 int) {
+	title = styles.Title.Render("aistat")
 	counts := CountStatuses(sessions)
 
 	// Status counts with icons: ▶3 ⏸2 ⚡1
-	var statusParts []string
 	if counts.Running > 0 {
-		statusParts = append(statusParts, styles.DotActive.Render("▶")+fmt.Sprintf("%d", counts.Running))
+		parts = append(parts, headerPart{styles.DotActive.Render("▶") + fmt.Sprintf("%d", counts.Running), BadgeRunning})
 	}
 	if counts.Idle > 0 {
-		statusParts = append(statusParts, styles.DotIdle.Render("⏸")+fmt.Sprintf("%d", counts.Idle))
+		parts = append(parts, headerPart{styles.DotIdle.Render("⏸") + fmt.Sprintf("%d", counts.Idle), BadgeIdle})
 	}
 	if counts.NeedsInput > 0 {
-		statusParts = append(statusParts, styles.DotNeedsInput.Render("⚡")+fmt.Sprintf("%d", counts.NeedsInput))
+		parts = append(parts, headerPart{styles.DotNeedsInput.Render("⚡") + fmt.Sprintf("%d", counts.NeedsInput), BadgeNeedsInput})
 	}
 
-	statusStr := ""
-	for i, p := range statusParts {
+	statusWidth := 0
+	for i, p := range parts {
 		if i > 0 {
-			statusStr += " "
+			statusWidth++
 		}
-		statusStr += p
+		statusWidth += lipgloss.Width(p.text)
 	}
 
 	// Urgent badge (if any sessions need input)
-	var badge string
+	badgeWidth := 0
 	if counts.NeedsInput > 0 {
-		badge = styles.BadgeNeedsInput.Render(fmt.Sprintf("● %d need input", counts.NeedsInput))
+		badgeWidth = lipgloss.Width(urgentBadge(counts.NeedsInput, styles))
 	}
 
 	// Layout: title | status counts | badge
-	titleWidth := lipgloss.Width(title)
-	statusWidth := lipgloss.Width(statusStr)
-	badgeWidth := lipgloss.Width(badge)
+	gap := width - lipgloss.Width(title) - statusWidth - badgeWidth - 6 // 6 for padding
+	if gap < 2 {
+		return title, parts, -1, -1
+	}
+	gap1 = gap / 2
+	return title, parts, gap1, gap - gap1
+}
 
-	totalContent := titleWidth + statusWidth + badgeWidth
-	gap := width - totalContent - 6 // 6 for padding
+func urgentBadge(n int, styles theme.Styles) string {
+	return styles.BadgeNeedsInput.Render(fmt.Sprintf("● %d need input", n))
+}
 
-	if gap < 2 {
+// RenderHeader renders the header: title + status counts + urgent badge
+func RenderHeader(sessions []state.SessionView, styles theme.Styles, width int) string {
+	title, parts, gap1, gap2 := headerLayout(sessions, styles, width)
+	if gap1 < 0 {
 		// Too narrow - just show title
 		return styles.Header.Width(width).Render(title)
 	}
 
-	// Distribute gaps
-	gap1 := gap / 2
-	gap2 := gap - gap1
+	statusStr := ""
+	for i, p := range parts {
+		if i > 0 {
+			statusStr += " "
+		}
+		statusStr += p.text
+	}
+	var badge string
+	if n := CountStatuses(sessions).NeedsInput; n > 0 {
+		badge = urgentBadge(n, styles)
+	}
 
 	spacer1 := lipgloss.NewStyle().Width(gap1).Render("")
 	spacer2 := lipgloss.NewStyle().Width(gap2).Render("")
@@ -86,3 +116,27 @@ func RenderHeader(sessions []state.SessionView, styles theme.Styles, width int)
 	row := lipgloss.JoinHorizontal(lipgloss.Center, title, spacer1, statusStr, spacer2, badge)
 	return styles.Header.Width(width).Render(row)
 }
+
+// HeaderBadgeAt reports which status count (or the urgent badge) is at
+// column x of the header
+func HeaderBadgeAt(sessions []state.SessionView, styles theme.Styles, width, x int) (HeaderBadge, bool) {
+	title, parts, gap1, gap2 := headerLayout(sessions, styles, width)
+	if gap1 < 0 {
+		return "", false
+	}
+	pos := 1 + lipgloss.Width(title) + gap1 // 1 for header padding
+	for i, p := range parts {
+		if i > 0 {
+			pos++
+		}
+		w := lipgloss.Width(p.text)
+		if x >= pos && x < pos+w {
+			return p.badge, true
+		}
+		pos += w
+	}
+	if len(parts) > 0 && parts[len(parts)-1].badge == BadgeNeedsInput && x >= pos+gap2 {
+		return BadgeNeedsInput, true
+	}
+	return "", false
+}
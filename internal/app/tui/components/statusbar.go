@@ -13,29 +13,36 @@ type Shortcut struct {
 	Desc string
 }
 
-// MainShortcuts - the ~10 essential shortcuts for normal mode
-var MainShortcuts = []Shortcut{
-	{"j/k", "navigate"},
-	{"/", "filter"},
-	{"b", "bookmark"},
-	{"?", "help"},
-	{"q", "quit"},
-}
-
 // FilterShortcuts - shown when filter is active
 var FilterShortcuts = []Shortcut{
 	{"esc", "clear"},
 	{"enter", "apply"},
 }
 
-// RenderFooter renders the context-aware footer with shortcuts and icon legend
-func RenderFooter(filterActive bool, refreshing bool, spinnerFrame int, spinnerFrames []string, styles theme.Styles, width int) string {
-	var shortcuts []Shortcut
-	if filterActive {
-		shortcuts = FilterShortcuts
-	} else {
-		shortcuts = MainShortcuts
-	}
+// PaletteShortcuts - shown when the command palette is open
+var PaletteShortcuts = []Shortcut{
+	{"enter", "run"},
+	{"tab", "complete"},
+	{"esc", "close"},
+}
+
+// ConfirmShortcuts - shown while a bulk action waits for confirmation
+var ConfirmShortcuts = []Shortcut{
+	{"y", "confirm"},
+	{"any key", "cancel"},
+}
+
+// ProjectShortcuts - shown in the project picker and dashboard
+var ProjectShortcuts = []Shortcut{
+	{"↑/↓", "move"},
+	{"space", "toggle"},
+	{"enter", "select"},
+	{"esc", "close"},
+}
+
+// RenderFooter renders the context-aware footer with shortcuts, the hook
+// pipeline status (highlighted when warn is set) and icon legend
+func RenderFooter(shortcuts []Shortcut, ingest string, warn bool, refreshing bool, spinnerFrame int, spinnerFrames []string, styles theme.Styles, width int) string {
 
 	// Build shortcut string with fixed-width key column
 	keyStyle := lipgloss.NewStyle().
@@ -65,13 +72,27 @@ func RenderFooter(filterActive bool, refreshing bool, spinnerFrame int, spinnerF
 		indicator = " " + styles.DotActive.Render(spinnerFrames[frame])
 	}
 
+	// Pipeline status before the legend; it outlasts the legend when space is short
+	var status string
+	if ingest != "" {
+		if warn {
+			status = styles.ErrorText.Render("⚠ "+ingest) + "   "
+		} else {
+			status = descStyle.Render(ingest) + "   "
+		}
+	}
+
 	// Calculate remaining space and right-align legend + indicator
 	leftContent := shortcutStr
-	rightContent := legend + indicator
+	rightContent := status + legend + indicator
 
 	contentWidth := lipgloss.Width(leftContent) + lipgloss.Width(rightContent)
 	if contentWidth >= width-4 {
-		return styles.Footer.Width(width).Render(shortcutStr)
+		rightContent = strings.TrimSuffix(status, "   ")
+		contentWidth = lipgloss.Width(leftContent) + lipgloss.Width(rightContent)
+		if status == "" || contentWidth >= width-4 {
+			return styles.Footer.Width(width).Render(shortcutStr)
+		}
 	}
 
 	gap := width - contentWidth - 4
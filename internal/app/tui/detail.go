@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/components"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+// detailEntry caches loaded tab data for one session; it is reloaded when the
+// session's LastSeen moves
+type detailEntry struct {
+	detail   state.SessionDetail
+	err      error
+	lastSeen time.Time
+}
+
+// cycleDetailTab moves to the next/previous detail tab
+func (m *Model) cycleDetailTab(delta int) {
+	n := len(components.DetailTabNames)
+	m.detailTab = ((m.detailTab+delta)%n + n) % n
+	m.detailScroll = 0
+	m.showDetail = true
+}
+
+// scrollDetail scrolls the conversation tab (positive = further back)
+func (m *Model) scrollDetail(delta int) {
+	m.detailScroll += delta
+	if m.detailScroll < 0 {
+		m.detailScroll = 0
+	}
+}
+
+// ensureDetailCmd loads the selected session's tab data when a tab other than
+// Overview is visible and the cached copy is missing or stale. Only the
+// selected session is ever loaded.
+func (m *Model) ensureDetailCmd() tea.Cmd {
+	// A new selection starts the conversation at its latest turn
+	if s := m.selectedSession(); s != nil && sessionKey(*s) != m.detailKey {
+		m.detailKey = sessionKey(*s)
+		m.detailScroll = 0
+	}
+	if m.cfg.LoadDetail == nil || !m.showDetail || m.detailTab == components.TabOverview {
+		return nil
+	}
+	s := m.selectedSession()
+	if s == nil {
+		return nil
+	}
+	key := sessionKey(*s)
+	if key == m.detailLoading {
+		return nil
+	}
+	if e, ok := m.details[key]; ok && e.lastSeen.Equal(s.LastSeen) {
+		return nil
+	}
+
+	m.detailLoading = key
+	load := m.cfg.LoadDetail
+	lastSeen := s.LastSeen
+	return func() tea.Msg {
+		d, err := load(key)
+		return DetailMsg{Key: key, LastSeen: lastSeen, Detail: d, Err: err}
+	}
+}
+
+func (m *Model) storeDetail(msg DetailMsg) {
+	if m.detailLoading == msg.Key {
+		m.detailLoading = ""
+	}
+	// Keep the cache to the sessions still listed
+	if len(m.details) > 64 {
+		m.details = make(map[string]detailEntry)
+	}
+	m.details[msg.Key] = detailEntry{detail: msg.Detail, err: msg.Err, lastSeen: msg.LastSeen}
+}
+
+// renderDetailPane renders the tab strip and the active tab
+func (m *Model) renderDetailPane(width, height int) string {
+	s := m.selectedSession()
+	tabs := components.RenderDetailTabs(m.detailTab, m.styles)
+	bodyHeight := height - 2
+	if s == nil {
+		return tabs + "\n\n" + components.RenderEmptyDetail(m.styles)
+	}
+	if m.detailTab == components.TabOverview {
+		return tabs + "\n\n" + components.RenderDetail(s, m.styles, width)
+	}
+
+	if m.cfg.LoadDetail == nil {
+		return tabs + "\n\n" + m.styles.Muted.Render("Not available")
+	}
+	e, ok := m.details[sessionKey(*s)]
+	if !ok {
+		return tabs + "\n\n" + m.styles.Muted.Render("Loading…")
+	}
+	if e.err != nil {
+		return tabs + "\n\n" + m.styles.ErrorText.Render(e.err.Error())
+	}
+
+	var body string
+	switch m.detailTab {
+	case components.TabConversation:
+		body = components.RenderConversation(&e.detail, m.detailScroll, m.styles, width, bodyHeight)
+	case components.TabUsage:
+		body = components.RenderUsage(s, &e.detail, m.styles, width)
+	case components.TabTimeline:
+		body = components.RenderTimeline(&e.detail, m.styles, bodyHeight, time.Now())
+	}
+	return tabs + "\n\n" + body
+}
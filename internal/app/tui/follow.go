@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/components"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+// followEvery is how often the follower polls the transcript for new lines
+const followEvery = 500 * time.Millisecond
+
+// maxFollowTurns bounds the follower's scrollback
+const maxFollowTurns = 200
+
+// toggleFollow opens/closes the transcript follower beneath the list
+func (m *Model) toggleFollow() {
+	m.following = !m.following
+	if !m.following {
+		m.followKey = ""
+		m.followTurns = nil
+		m.followErr = nil
+	}
+}
+
+func followTickCmd() tea.Cmd {
+	return tea.Tick(followEvery, func(time.Time) tea.Msg { return FollowTickMsg{} })
+}
+
+// ensureFollowCmd starts the poll loop and switches the follower to the
+// selected session, starting again from its transcript tail
+func (m *Model) ensureFollowCmd() tea.Cmd {
+	if !m.following || m.cfg.FollowSession == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if !m.followTicking {
+		m.followTicking = true
+		cmds = append(cmds, followTickCmd())
+	}
+
+	key := ""
+	if s := m.selectedSession(); s != nil {
+		key = sessionKey(*s)
+	}
+	if key != m.followKey {
+		m.followKey = key
+		m.followOffset = -1
+		m.followTurns = nil
+		m.followErr = nil
+		// If a read for the old session is in flight, storeFollow starts it
+		cmds = append(cmds, m.followReadCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+// followReadCmd reads what was appended since the last read (one at a time)
+func (m *Model) followReadCmd() tea.Cmd {
+	if m.followBusy || m.followKey == "" || m.cfg.FollowSession == nil {
+		return nil
+	}
+	m.followBusy = true
+	follow := m.cfg.FollowSession
+	key, offset := m.followKey, m.followOffset
+	return func() tea.Msg {
+		turns, next, err := follow(key, offset)
+		return FollowMsg{Key: key, Offset: next, Turns: turns, Err: err}
+	}
+}
+
+// storeFollow appends new turns. A read for a session no longer followed is
+// dropped, and the read for the new selection that waited on it starts.
+func (m *Model) storeFollow(msg FollowMsg) tea.Cmd {
+	m.followBusy = false
+	if msg.Key != m.followKey {
+		return m.followReadCmd()
+	}
+	m.followErr = msg.Err
+	if msg.Err != nil {
+		return nil
+	}
+	if msg.Offset < m.followOffset {
+		// The file was rewritten; start over from its tail
+		m.followTurns = nil
+	}
+	m.followOffset = msg.Offset
+	m.followTurns = append(m.followTurns, msg.Turns...)
+	if len(m.followTurns) > maxFollowTurns {
+		m.followTurns = append([]state.Turn(nil), m.followTurns[len(m.followTurns)-maxFollowTurns:]...)
+	}
+	return nil
+}
+
+// renderFollowPane renders the follower for the selected session
+func (m *Model) renderFollowPane(width, height int) string {
+	var s *state.SessionView
+	if m.followKey != "" {
+		s = m.selectedSession()
+	}
+	if m.cfg.FollowSession == nil {
+		return m.styles.Muted.Render("Following is not available")
+	}
+	return components.RenderFollow(s, m.followTurns, m.followErr, m.styles, width-2, height)
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+	"github.com/vburojevic/aistat/internal/app/tui/widgets"
+)
+
+// Row gauge column widths: context bar + percentage, burn rate, sparkline
+const (
+	gaugeBarWidth   = 6
+	gaugeCtxWidth   = gaugeBarWidth + 5 // " 100%"
+	gaugeBurnWidth  = 8
+	gaugeSparkWidth = 10
+)
+
+// rowGauges renders the context fill, $/hour and activity columns in at most
+// room cells, dropping the sparkline, then the burn rate, then the bar (keeping
+// the percentage) as space runs out. Missing values leave blank columns so
+// rows stay aligned.
+func (m *Model) rowGauges(s state.SessionView, room int) string {
+	pct := ""
+	var ratio float64
+	if s.ContextWindow > 0 {
+		ratio = widgets.ClampFloat(float64(s.ContextTokens)/float64(s.ContextWindow), 0, 1)
+		pct = widgets.FormatInt(int(ratio*100+0.5)) + "%"
+	}
+	ctx := strings.Repeat(" ", gaugeCtxWidth)
+	if pct != "" {
+		ctx = widgets.Gauge(ratio, gaugeBarWidth, m.styles) + widgets.PadLeft(pct, gaugeCtxWidth-gaugeBarWidth)
+	}
+	burn := m.styles.Muted.Render(widgets.PadLeft(widgets.FormatBurnRate(s.BurnRate), gaugeBurnWidth))
+	spark := strings.Repeat(" ", gaugeSparkWidth)
+	if len(s.Activity) > 0 {
+		values := lastInts(s.Activity, gaugeSparkWidth)
+		spark = strings.Repeat(" ", gaugeSparkWidth-len(values)) + widgets.Sparkline(values, m.styles)
+	}
+
+	switch {
+	case room >= gaugeCtxWidth+gaugeBurnWidth+gaugeSparkWidth+2:
+		return ctx + " " + burn + " " + spark
+	case room >= gaugeCtxWidth+gaugeBurnWidth+1:
+		return ctx + " " + burn
+	case room >= gaugeCtxWidth:
+		return ctx
+	case room >= 4:
+		return widgets.PadLeft(pct, 4)
+	}
+	return ""
+}
+
+func lastInts(values []int, n int) []int {
+	if len(values) > n {
+		return values[len(values)-n:]
+	}
+	return values
+}
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+// defaultSnooze is how long z snoozes a session
+const defaultSnooze = 15 * time.Minute
+
+// needsMe reports whether a session is waiting on the user
+func needsMe(s state.SessionView) bool {
+	switch s.Status {
+	case state.StatusApproval, state.StatusWaiting, state.StatusNeedsAttn:
+		return true
+	}
+	return false
+}
+
+// inboxSessions keeps sessions that need the user and are neither snoozed
+// nor marked seen (see pruneSeen for when a mark lapses).
+func (m *Model) inboxSessions(sessions []state.SessionView, now time.Time) []state.SessionView {
+	out := make([]state.SessionView, 0, len(sessions))
+	for _, s := range sessions {
+		key := sessionKey(s)
+		if _, ok := m.seen[key]; ok {
+			continue
+		}
+		if until, ok := m.snoozed[key]; ok {
+			if now.Before(until) {
+				continue
+			}
+			delete(m.snoozed, key)
+		}
+		if needsMe(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// seenMark is what markSeen records for a session. Without a recorded
+// transition the last event stands in for when the status started.
+func seenMark(s state.SessionView) state.SeenMark {
+	since := s.StatusSince
+	if since.IsZero() {
+		since = s.LastSeen
+	}
+	return state.SeenMark{Status: s.Status, Since: since}
+}
+
+// pruneSeen drops the seen marks of sessions whose status changed or
+// started again since they were marked. It checks every session on each
+// refresh, inbox or not, so a change is not missed while the inbox is off.
+// Marks of sessions not in the list are kept.
+func (m *Model) pruneSeen() {
+	if len(m.seen) == 0 {
+		return
+	}
+	for _, s := range m.sessions {
+		key := sessionKey(s)
+		mark, ok := m.seen[key]
+		if !ok {
+			continue
+		}
+		if cur := seenMark(s); mark.Status != cur.Status || !mark.Since.Equal(cur.Since) {
+			delete(m.seen, key)
+		}
+	}
+}
+
+// sortInbox orders by urgency, then by how long the session has been waiting
+func sortInbox(sessions []state.SessionView) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		ui, uj := urgencyScore(sessions[i]), urgencyScore(sessions[j])
+		if ui != uj {
+			return ui > uj
+		}
+		return sessions[i].Age > sessions[j].Age
+	})
+}
+
+// toggleInbox switches between the inbox and the full list
+func (m *Model) toggleInbox() {
+	m.inbox = !m.inbox
+	m.cursor, m.targetCursor = 0, 0
+	m.applyFilter()
+	m.notice = "Inbox: " + onOff(m.inbox)
+}
+
+// nextNeedingMe moves the cursor to the next session that needs the user,
+// wrapping around
+func (m *Model) nextNeedingMe() tea.Cmd {
+	n := len(m.filteredSessions)
+	for i := 1; i <= n; i++ {
+		idx := (m.targetCursor + i) % n
+		if needsMe(m.filteredSessions[idx]) {
+			return m.moveCursor(idx - m.targetCursor)
+		}
+	}
+	m.notice = "Nothing needs you"
+	return nil
+}
+
+// snoozeSelected hides the selected session from the inbox for d
+func (m *Model) snoozeSelected(d time.Duration) {
+	s := m.selectedSession()
+	if s == nil {
+		return
+	}
+	m.snoozed[sessionKey(*s)] = time.Now().Add(d)
+	m.notice = fmt.Sprintf("Snoozed %s for %s", s.Project, d)
+	m.applyFilter()
+	m.saveUIState()
+}
+
+// markSeen hides the selected session from the inbox until its status changes
+func (m *Model) markSeen() {
+	s := m.selectedSession()
+	if s == nil {
+		return
+	}
+	m.seen[sessionKey(*s)] = seenMark(*s)
+	m.notice = "Marked seen: " + s.Project
+	m.applyFilter()
+	m.saveUIState()
+}
+
+// unsnoozeAll clears every snooze and seen mark
+func (m *Model) unsnoozeAll() {
+	m.snoozed = make(map[string]time.Time)
+	m.seen = make(map[string]state.SeenMark)
+	m.notice = "Inbox restored"
+	m.applyFilter()
+	m.saveUIState()
+}
+
+// activeSnoozes counts snoozes that have not expired
+func (m *Model) activeSnoozes(now time.Time) int {
+	n := 0
+	for _, until := range m.snoozed {
+		if now.Before(until) {
+			n++
+		}
+	}
+	return n
+}
+
+// saveUIState persists immediately so snoozes survive a crash
+func (m *Model) saveUIState() {
+	if m.cfg.SaveUIState == nil {
+		return
+	}
+	if err := m.cfg.SaveUIState(m.uiState()); err != nil {
+		m.notice = "Save failed: " + err.Error()
+	}
+}
@@ -0,0 +1,255 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vburojevic/aistat/internal/app/tui/components"
+	"github.com/vburojevic/aistat/internal/app/tui/views"
+)
+
+// KeyAction is a rebindable normal-mode action
+type KeyAction struct {
+	Name string
+	Keys []string // default bindings
+	Desc string
+}
+
+// KeyActions lists every normal-mode action with its default keys, in help
+// order. Config can rebind any of them by name.
+var KeyActions = []KeyAction{
+	{"down", []string{"j", "down"}, "Move down"},
+	{"up", []string{"k", "up"}, "Move up"},
+	{"filter", []string{"/"}, "Filter (p:project, s:status, t:tag)"},
+	{"clear", []string{"esc"}, "Clear filter / selection"},
+	{"palette", []string{":"}, "Command palette"},
+	{"dashboard", []string{"tab"}, "Projects dashboard"},
+	{"projects", []string{"p"}, "Project picker"},
+	{"help", []string{"?"}, "Toggle help"},
+	{"quit", []string{"q", "ctrl+c"}, "Quit"},
+
+	{"sort", []string{"s"}, "Cycle sort"},
+	{"group", []string{"g"}, "Cycle group"},
+	{"view", []string{"v"}, "Cycle view"},
+	{"detail", []string{"d"}, "Toggle detail pane"},
+	{"sidebar", []string{"b"}, "Toggle sidebar"},
+	{"follow", []string{"f"}, "Follow transcript"},
+	{"tab_prev", []string{"["}, "Previous detail tab"},
+	{"tab_next", []string{"]"}, "Next detail tab"},
+	{"scroll_down", []string{"J", "pgdown"}, "Scroll conversation down"},
+	{"scroll_up", []string{"K", "pgup"}, "Scroll conversation up"},
+	{"last_msg", []string{"m"}, "Toggle last message"},
+	{"gauges", []string{"u"}, "Toggle row gauges"},
+	{"show_all", []string{"a"}, "Toggle show all"},
+	{"theme", []string{"t"}, "Cycle theme"},
+
+	{"provider_claude", []string{"1"}, "Filter claude"},
+	{"provider_codex", []string{"2"}, "Filter codex"},
+	{"status_running", []string{"R"}, "Filter running"},
+	{"status_waiting", []string{"W"}, "Filter waiting"},
+	{"status_approval", []string{"E"}, "Filter approval"},
+	{"status_stale", []string{"S"}, "Filter stale"},
+	{"status_ended", []string{"Z"}, "Filter ended"},
+	{"status_attention", []string{"N"}, "Filter needs attention"},
+
+	{"inbox", []string{"i"}, "Triage inbox"},
+	{"next", []string{"n"}, "Next session needing you"},
+	{"snooze", []string{"z"}, "Snooze 15m"},
+	{"seen", []string{"x"}, "Mark seen"},
+
+	{"pin", []string{"P"}, "Toggle pin ★"},
+	{"select", []string{"space"}, "Select session"},
+	{"copy_ids", []string{"y"}, "Copy IDs"},
+	{"copy_detail", []string{"D"}, "Copy detail"},
+	{"open", []string{"o"}, "Open log(s)"},
+	{"end", []string{"e"}, "Mark ended"},
+	{"hide", []string{"H"}, "Hide from view"},
+	{"tag", []string{"T"}, "Tag"},
+	{"copy_resume", []string{"C"}, "Copy resume commands"},
+	{"refresh", []string{"r"}, "Refresh now"},
+	{"approve", []string{"Y"}, "Approve pending permission"},
+	{"deny", []string{"X"}, "Deny pending permission"},
+}
+
+// Keymap maps key strings (as reported by bubbletea) to action names
+type Keymap struct {
+	byKey    map[string]string
+	byAction map[string][]string
+}
+
+// NewKeymap starts from the defaults and applies overrides (action -> keys).
+// An override replaces all default keys of that action, and a key bound by an
+// override is taken away from whatever action had it by default.
+func NewKeymap(overrides map[string][]string) (Keymap, error) {
+	km := Keymap{byKey: map[string]string{}, byAction: map[string][]string{}}
+	known := map[string]bool{}
+	for _, a := range KeyActions {
+		known[a.Name] = true
+	}
+	var unknown []string
+	for name := range overrides {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return defaultKeymap(), fmt.Errorf("unknown key action(s): %s", strings.Join(unknown, ", "))
+	}
+
+	taken := map[string]bool{}
+	for _, keys := range overrides {
+		for _, k := range keys {
+			taken[normalizeKey(k)] = true
+		}
+	}
+	for _, a := range KeyActions {
+		keys, overridden := overrides[a.Name]
+		if !overridden {
+			keys = a.Keys
+		}
+		for _, k := range keys {
+			k = normalizeKey(k)
+			if k == "" || (!overridden && taken[k]) {
+				continue
+			}
+			km.byKey[k] = a.Name
+			km.byAction[a.Name] = append(km.byAction[a.Name], k)
+		}
+	}
+	return km, nil
+}
+
+func defaultKeymap() Keymap {
+	km, _ := NewKeymap(nil)
+	return km
+}
+
+// Action returns the action bound to a key ("" if none)
+func (k Keymap) Action(key string) string {
+	return k.byKey[normalizeKey(key)]
+}
+
+// Keys returns the keys bound to an action
+func (k Keymap) Keys(action string) []string {
+	return k.byAction[action]
+}
+
+// Label renders an action's keys for help and footer text ("j / down")
+func (k Keymap) Label(action string) string {
+	keys := k.byAction[action]
+	if len(keys) == 0 {
+		return "-"
+	}
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = key
+		if key == " " {
+			labels[i] = "space"
+		}
+	}
+	return strings.Join(labels, " / ")
+}
+
+// normalizeKey maps config spellings onto bubbletea's key strings
+func normalizeKey(k string) string {
+	if k == " " {
+		return k
+	}
+	switch strings.ToLower(strings.TrimSpace(k)) {
+	case "space", "spacebar":
+		return " "
+	case "escape":
+		return "esc"
+	case "return":
+		return "enter"
+	}
+	return strings.TrimSpace(k)
+}
+
+// helpLayout groups actions into help overlay lines (nil = blank line)
+var helpLayout = []struct {
+	actions []string
+	desc    string
+}{
+	{[]string{"down"}, "Move down"},
+	{[]string{"up"}, "Move up"},
+	{[]string{"filter"}, "Filter (p:project, s:status, t:tag)"},
+	{[]string{"clear"}, "Clear filter / selection"},
+	{[]string{"palette"}, "Command palette"},
+	{[]string{"dashboard"}, "Projects dashboard"},
+	{[]string{"projects"}, "Project picker"},
+	{[]string{"help"}, "Toggle help"},
+	{[]string{"quit"}, "Quit"},
+	{nil, ""},
+	{[]string{"sort", "group", "view"}, "Cycle sort / group / view"},
+	{[]string{"detail", "sidebar"}, "Toggle detail / sidebar"},
+	{[]string{"follow"}, "Follow the selected transcript live"},
+	{[]string{"tab_prev", "tab_next"}, "Detail tab (overview/conversation/usage/timeline)"},
+	{[]string{"scroll_down", "scroll_up"}, "Scroll conversation down / up"},
+	{[]string{"last_msg"}, "Toggle last message"},
+	{[]string{"gauges"}, "Toggle row gauges (context, $/hour, activity)"},
+	{[]string{"show_all"}, "Toggle show all"},
+	{[]string{"theme"}, "Cycle theme"},
+	{[]string{"provider_claude", "provider_codex"}, "Filter claude / codex"},
+	{[]string{"status_running", "status_waiting", "status_approval", "status_stale", "status_ended", "status_attention"}, "Filter run/wait/appr/stale/end/attn"},
+	{nil, ""},
+	{[]string{"inbox"}, "Triage inbox (sessions waiting on you)"},
+	{[]string{"next"}, "Next session needing you"},
+	{[]string{"snooze", "seen"}, "Snooze 15m / mark seen"},
+	{nil, ""},
+	{[]string{"pin"}, "Toggle pin ★"},
+	{[]string{"select"}, "Select session"},
+	{[]string{"copy_ids", "copy_detail"}, "Copy IDs / detail"},
+	{nil, ""},
+	{[]string{"open"}, "Open log(s) of the selection"},
+	{[]string{"end", "hide"}, "Mark ended / hide (asks first)"},
+	{[]string{"tag"}, "Tag the selection (:untag, t: to filter)"},
+	{[]string{"copy_resume"}, "Copy resume commands"},
+	{nil, ""},
+	{[]string{"refresh"}, "Refresh now"},
+	{[]string{"approve", "deny"}, "Approve / deny pending permission"},
+}
+
+// helpEntries renders helpLayout with the keys currently bound
+func (k Keymap) helpEntries() []views.HelpEntry {
+	var out []views.HelpEntry
+	for _, l := range helpLayout {
+		if len(l.actions) == 0 {
+			out = append(out, views.HelpEntry{})
+			continue
+		}
+		if len(l.actions) == 1 {
+			out = append(out, views.HelpEntry{Key: k.Label(l.actions[0]), Desc: l.desc})
+			continue
+		}
+		sep := " / "
+		if len(l.actions) > 2 {
+			sep = " "
+		}
+		keys := make([]string, len(l.actions))
+		for i, a := range l.actions {
+			keys[i] = k.First(a)
+		}
+		out = append(out, views.HelpEntry{Key: strings.Join(keys, sep), Desc: l.desc})
+	}
+	return out
+}
+
+// First returns the primary key of an action for compact labels
+func (k Keymap) First(action string) string {
+	keys := k.byAction[action]
+	if len(keys) == 0 {
+		return "-"
+	}
+	if keys[0] == " " {
+		return "space"
+	}
+	return keys[0]
+}
+
+// shortcut builds a footer entry from an action's primary key
+func (k Keymap) shortcut(action, desc string) components.Shortcut {
+	return components.Shortcut{Key: k.First(action), Desc: desc}
+}
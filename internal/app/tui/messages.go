@@ -10,6 +10,9 @@ import (
 type SessionsMsg struct {
 	Sessions []state.SessionView
 	Err      error
+
+	Ingest     string // hook pipeline health (Config.IngestStatus)
+	IngestWarn bool
 }
 
 // TickMsg is sent on each refresh tick
@@ -23,3 +26,22 @@ type AnimationTickMsg struct{}
 
 // SpinnerFrames are braille spinner characters
 var SpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// DetailMsg is sent when a session's detail tabs finish loading
+type DetailMsg struct {
+	Key      string
+	LastSeen time.Time // the session's LastSeen when the load started
+	Detail   state.SessionDetail
+	Err      error
+}
+
+// FollowTickMsg polls the followed transcript for new lines
+type FollowTickMsg struct{}
+
+// FollowMsg carries turns read by the transcript follower
+type FollowMsg struct {
+	Key    string
+	Offset int64 // where the next read resumes
+	Turns  []state.Turn
+	Err    error
+}
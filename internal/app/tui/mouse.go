@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/components"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+// doubleClickWindow is how close two clicks on the same row must be to open
+// the detail pane
+const doubleClickWindow = 400 * time.Millisecond
+
+// wheelStep is how many lines one wheel notch scrolls the detail pane
+const wheelStep = 3
+
+// handleMouse handles clicks and the wheel: rows select (double-click opens
+// the detail pane), sidebar entries and header counts toggle filters
+func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	// Overlays and text inputs keep the keyboard focus; a click only closes help
+	if m.confirm != nil || m.filterActive || m.paletteOpen || m.projectsOpen || m.showDashboard {
+		return nil
+	}
+	if m.showHelp {
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			m.showHelp = false
+		}
+		return nil
+	}
+
+	l := m.layout()
+	inList := msg.X >= l.listX && msg.X < l.listX+l.listWidth+2
+	inDetail := m.showDetail && msg.X >= l.detailX
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		if inDetail {
+			m.scrollDetail(wheelStep)
+			return nil
+		}
+		if inList {
+			return m.moveCursor(-1)
+		}
+		return nil
+	case tea.MouseButtonWheelDown:
+		if inDetail {
+			m.scrollDetail(-wheelStep)
+			return nil
+		}
+		if inList {
+			return m.moveCursor(1)
+		}
+		return nil
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	m.notice = ""
+	line := msg.Y - bodyTop
+	switch {
+	case msg.Y == 0:
+		m.clickHeader(msg.X)
+	case line < 0 || line >= l.bodyHeight:
+		// filter line, borders and footer
+	case l.sidebar && msg.X < components.SidebarWidth:
+		m.clickSidebar(line)
+	case inList && line < l.listHeight:
+		m.clickRow(line)
+	}
+	return nil
+}
+
+// clickRow selects the session on a visible list line; a second click on the
+// same session opens the detail pane
+func (m *Model) clickRow(line int) {
+	if line >= len(m.listRows) || m.listRows[line] < 0 {
+		return
+	}
+	idx := m.listRows[line]
+	now := time.Now()
+	if idx == m.lastClickRow && now.Sub(m.lastClickAt) < doubleClickWindow {
+		m.showDetail = true
+		m.lastClickAt = time.Time{}
+	} else {
+		m.lastClickRow, m.lastClickAt = idx, now
+	}
+
+	m.cursor, m.targetCursor = idx, idx
+	m.cursorY, m.cursorVelocity = float64(idx), 0
+	m.animating = false
+}
+
+// clickSidebar toggles the provider, status or project filter on a sidebar
+// line
+func (m *Model) clickSidebar(line int) {
+	target, ok := components.SidebarTargetAt(m.sessions, line)
+	if !ok {
+		return
+	}
+	switch {
+	case target.Provider != "":
+		m.filters.ToggleProvider(target.Provider)
+	case target.Status != "":
+		m.filters.ToggleStatus(target.Status)
+	default:
+		m.filters.ToggleProject(target.Project)
+	}
+	m.applyFilter()
+}
+
+// clickHeader toggles the status filters behind a header count
+func (m *Model) clickHeader(x int) {
+	badge, ok := components.HeaderBadgeAt(m.filteredSessions, m.styles, m.width, x)
+	if !ok {
+		return
+	}
+	switch badge {
+	case components.BadgeRunning:
+		m.toggleStatuses(state.StatusRunning)
+	case components.BadgeIdle:
+		m.toggleStatuses(state.StatusWaiting)
+	case components.BadgeNeedsInput:
+		m.toggleStatuses(state.StatusApproval, state.StatusNeedsAttn)
+	}
+	m.applyFilter()
+}
+
+// toggleStatuses switches a group of status filters together: off if all are
+// on, otherwise all on
+func (m *Model) toggleStatuses(statuses ...state.Status) {
+	allOn := true
+	for _, st := range statuses {
+		allOn = allOn && m.filters.StatusFilter[st]
+	}
+	for _, st := range statuses {
+		if m.filters.StatusFilter[st] == allOn {
+			m.filters.ToggleStatus(st)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/components"
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
+)
+
+// Sort, group and view orders cycled by s, g and v
+var (
+	sortOrder  = []string{"urgency", "last_seen", "status", "provider", "cost", "project"}
+	groupOrder = []string{"project", "", "provider", "status", "day", "hour"}
+	viewOrder  = []string{"compact", "full", "ultra"}
+)
+
+// paletteCommands are the commands understood by the command palette (:)
+var paletteCommands = []string{
+	"dashboard", "projects", "inbox", "next", "snooze", "seen", "unsnooze",
+	"clear-filters", "reset-view",
+	"detail", "tab", "sidebar", "follow", "open", "copy-id", "copy-detail",
+	"end", "hide", "unhide", "tag", "untag", "export", "copy-resume",
+	"sort", "group", "view", "theme", "last-msg", "gauges", "all", "refresh", "help",
+}
+
+// resolvePaletteCommand matches exact names first, then prefixes, then fuzzy
+func resolvePaletteCommand(input string) string {
+	for _, c := range paletteCommands {
+		if c == input {
+			return c
+		}
+	}
+	for _, c := range paletteCommands {
+		if strings.HasPrefix(c, input) {
+			return c
+		}
+	}
+	for _, c := range paletteCommands {
+		if fuzzyMatch(input, c) {
+			return c
+		}
+	}
+	return input
+}
+
+// palettePreview describes what the current palette input will do
+func palettePreview(raw string) string {
+	parts := strings.Fields(strings.ToLower(raw))
+	if len(parts) == 0 {
+		return "Commands: " + strings.Join(paletteCommands, ", ")
+	}
+	switch resolvePaletteCommand(parts[0]) {
+	case "dashboard":
+		return "Open the projects dashboard"
+	case "projects":
+		return "Open the project picker"
+	case "inbox":
+		return "Toggle the triage inbox (sessions waiting on you)"
+	case "next":
+		return "Jump to the next session that needs you"
+	case "snooze":
+		return "Snooze the selected session (snooze [minutes], default 15)"
+	case "seen":
+		return "Hide the selected session until its status changes"
+	case "unsnooze":
+		return "Clear all snoozes and seen marks"
+	case "clear-filters":
+		return "Clear provider, status, project and text filters"
+	case "reset-view":
+		return "Reset sort, group, view and panes to defaults"
+	case "detail":
+		return "Toggle the detail pane (detail on|off)"
+	case "tab":
+		return "Detail tab: " + strings.ToLower(strings.Join(components.DetailTabNames, " | "))
+	case "sidebar":
+		return "Toggle the filter sidebar (sidebar on|off)"
+	case "follow":
+		return "Follow the selected transcript beneath the list (follow on|off)"
+	case "open":
+		return "Open the transcript/log of the selected sessions"
+	case "end":
+		return "Mark the selected sessions ended (asks first)"
+	case "hide":
+		return "Hide the selected sessions until :unhide (asks first)"
+	case "unhide":
+		return "Show all hidden sessions again"
+	case "tag":
+		return "Tag the selected sessions (tag <name>; filter with t:<name>)"
+	case "untag":
+		return "Remove a tag from the selected sessions (untag [name], all when empty)"
+	case "export":
+		return "Export the selected sessions (export json|md [path])"
+	case "copy-resume":
+		return "Copy commands that resume the selected sessions"
+	case "copy-id":
+		return "Copy selected IDs"
+	case "copy-detail":
+		return "Copy the selected session's detail"
+	case "sort":
+		return "Sort by: " + strings.Join(sortOrder, " | ")
+	case "group":
+		return "Group by: project | none | provider | status | day | hour"
+	case "view":
+		return "View: " + strings.Join(viewOrder, " | ")
+	case "theme":
+		return "Theme: " + strings.Join(builtinThemeNames(), " | ")
+	case "last-msg":
+		return "Toggle last message snippets (last-msg on|off)"
+	case "gauges":
+		return "Toggle row gauges: context fill, $/hour, activity (gauges on|off)"
+	case "all":
+		return "Toggle showing older ended sessions"
+	case "refresh":
+		return "Refresh now"
+	case "help":
+		return "Show keyboard shortcuts"
+	}
+	return "Unknown command"
+}
+
+// completePalette expands the command word to its resolved name
+func completePalette(raw string) string {
+	parts := strings.Fields(raw)
+	if len(parts) == 0 {
+		return raw
+	}
+	parts[0] = resolvePaletteCommand(strings.ToLower(parts[0]))
+	out := strings.Join(parts, " ")
+	if len(parts) == 1 {
+		out += " "
+	}
+	return out
+}
+
+// executePaletteCommand runs a palette command and leaves a notice
+func (m *Model) executePaletteCommand(raw string) tea.Cmd {
+	parts := strings.Fields(strings.ToLower(strings.TrimSpace(raw)))
+	if len(parts) == 0 {
+		return nil
+	}
+	cmd := resolvePaletteCommand(parts[0])
+	arg := strings.Join(parts[1:], " ")
+	// Paths keep their case
+	rawArgs := strings.Fields(strings.TrimSpace(raw))[1:]
+
+	switch cmd {
+	case "dashboard":
+		m.openDashboard()
+		m.notice = "Dashboard"
+	case "projects":
+		m.openProjectPicker()
+		m.notice = "Projects"
+	case "inbox":
+		if toggleArg(arg, m.inbox) != m.inbox {
+			m.toggleInbox()
+		} else {
+			m.notice = "Inbox: " + onOff(m.inbox)
+		}
+	case "next":
+		return m.nextNeedingMe()
+	case "snooze":
+		d := defaultSnooze
+		if arg != "" {
+			mins, err := strconv.Atoi(arg)
+			if err != nil || mins <= 0 {
+				m.notice = "Usage: snooze [minutes]"
+				return nil
+			}
+			d = time.Duration(mins) * time.Minute
+		}
+		m.snoozeSelected(d)
+	case "seen":
+		m.markSeen()
+	case "unsnooze":
+		m.unsnoozeAll()
+	case "clear-filters":
+		m.clearFilters()
+		m.notice = "Filters cleared"
+	case "reset-view":
+		m.resetView()
+		m.notice = "View reset"
+	case "detail":
+		m.showDetail = toggleArg(arg, m.showDetail)
+		m.notice = "Detail: " + onOff(m.showDetail)
+	case "tab":
+		if arg == "" {
+			m.cycleDetailTab(1)
+		} else {
+			idx := indexOf(detailTabOptions(), resolveOption(detailTabOptions(), arg))
+			if idx < 0 {
+				m.notice = "Unknown tab: " + arg
+				return nil
+			}
+			m.cycleDetailTab(idx - m.detailTab)
+		}
+		m.notice = "Tab: " + components.DetailTabNames[m.detailTab]
+	case "sidebar":
+		m.showSidebar = toggleArg(arg, m.showSidebar)
+		m.notice = "Sidebar: " + onOff(m.showSidebar)
+	case "follow":
+		if toggleArg(arg, m.following) != m.following {
+			m.toggleFollow()
+		}
+		m.notice = "Follow: " + onOff(m.following)
+	case "open":
+		return m.openTargets()
+	case "end":
+		m.endTargets()
+	case "hide":
+		m.hideTargets()
+	case "unhide":
+		m.unhideAll()
+	case "tag":
+		m.tagTargets(arg)
+	case "untag":
+		m.untagTargets(arg)
+	case "export":
+		if len(rawArgs) == 0 {
+			m.notice = "Usage: export json|md [path]"
+			return nil
+		}
+		m.exportTargets(strings.ToLower(rawArgs[0]), strings.Join(rawArgs[1:], " "))
+	case "copy-resume":
+		m.copyResumeCommands()
+	case "copy-id":
+		m.copySelectedIDs()
+	case "copy-detail":
+		m.copyDetail()
+	case "sort":
+		if arg != "" {
+			key := resolveOption(sortOrder, arg)
+			if key == "" {
+				m.notice = "Unknown sort: " + arg
+				return nil
+			}
+			m.sortBy = key
+		} else {
+			m.sortBy = cycle(sortOrder, m.sortBy)
+		}
+		m.applyFilter()
+		m.notice = "Sort: " + m.sortBy
+	case "group":
+		switch arg {
+		case "":
+			m.groupBy = cycle(groupOrder, m.groupBy)
+		case "none", "off":
+			m.groupBy = ""
+		default:
+			key := resolveOption(groupOrder, arg)
+			if key == "" {
+				m.notice = "Unknown group: " + arg
+				return nil
+			}
+			m.groupBy = key
+		}
+		m.applyFilter()
+		m.notice = "Group: " + groupLabel(m.groupBy)
+	case "view":
+		if arg != "" {
+			key := resolveOption(viewOrder, arg)
+			if key == "" {
+				m.notice = "Unknown view: " + arg
+				return nil
+			}
+			m.viewMode = key
+		} else {
+			m.viewMode = cycle(viewOrder, m.viewMode)
+		}
+		m.notice = "View: " + m.viewMode
+	case "theme":
+		if arg == "" {
+			m.cycleTheme()
+			return nil
+		}
+		name := resolveOption(builtinThemeNames(), arg)
+		if name == "" {
+			m.notice = "Unknown theme: " + arg
+			return nil
+		}
+		for _, t := range theme.Builtins {
+			if t.Name == name {
+				m.setTheme(t)
+			}
+		}
+	case "last-msg":
+		return m.setLastMsg(toggleArg(arg, m.showLastMsg))
+	case "gauges":
+		m.showGauges = toggleArg(arg, m.showGauges)
+		m.notice = "Gauges: " + onOff(m.showGauges)
+	case "all":
+		m.showEnded = toggleArg(arg, m.showEnded)
+		m.applyFilter()
+		m.notice = "Show all: " + onOff(m.showEnded)
+	case "refresh":
+		return m.fetchSessionsCmd()
+	case "help":
+		m.showHelp = true
+	default:
+		m.notice = "Unknown command: " + parts[0]
+	}
+	return nil
+}
+
+func builtinThemeNames() []string {
+	names := make([]string, len(theme.Builtins))
+	for i, t := range theme.Builtins {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// detailTabOptions are the lowercase tab names accepted by "tab <name>"
+func detailTabOptions() []string {
+	out := make([]string, len(components.DetailTabNames))
+	for i, n := range components.DetailTabNames {
+		out[i] = strings.ToLower(n)
+	}
+	return out
+}
+
+func toggleArg(arg string, cur bool) bool {
+	switch arg {
+	case "on", "true", "yes":
+		return true
+	case "off", "false", "no":
+		return false
+	}
+	return !cur
+}
+
+func onOff(v bool) string {
+	if v {
+		return "on"
+	}
+	return "off"
+}
+
+func groupLabel(g string) string {
+	if g == "" {
+		return "none"
+	}
+	return g
+}
+
+func cycle(order []string, cur string) string {
+	idx := indexOf(order, cur)
+	return order[(idx+1)%len(order)]
+}
+
+// resolveOption matches arg exactly or as a unique prefix ("" if neither)
+func resolveOption(options []string, arg string) string {
+	match := ""
+	for _, o := range options {
+		if o == arg {
+			return o
+		}
+		if o != "" && strings.HasPrefix(o, arg) {
+			if match != "" {
+				return ""
+			}
+			match = o
+		}
+	}
+	return match
+}
+
+func indexOf(list []string, val string) int {
+	for i, v := range list {
+		if v == val {
+			return i
+		}
+	}
+	return -1
+}
+
+// fuzzyMatch reports whether needle's runes appear in hay in order
+func fuzzyMatch(needle, hay string) bool {
+	if needle == "" {
+		return true
+	}
+	n := []rune(needle)
+	idx := 0
+	for _, r := range hay {
+		if r == n[idx] {
+			idx++
+			if idx == len(n) {
+				return true
+			}
+		}
+	}
+	return false
+}
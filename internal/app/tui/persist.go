@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+// uiState captures what is persisted across restarts
+func (m *Model) uiState() state.UIState {
+	s := state.UIState{
+		Version:     state.UIStateVersion,
+		Query:       m.filterQuery,
+		SortBy:      m.sortBy,
+		GroupBy:     m.groupBy,
+		ShowEnded:   m.showEnded,
+		ShowLastMsg: m.showLastMsg,
+		ShowGauges:  m.showGauges,
+		Layouts:     make(map[string]state.Layout, len(m.layouts)+1),
+	}
+	m.filters.Snapshot(&s)
+	for key := range m.pinned {
+		s.Pinned = append(s.Pinned, key)
+	}
+	sort.Strings(s.Pinned)
+	for key := range m.hidden {
+		s.Hidden = append(s.Hidden, key)
+	}
+	sort.Strings(s.Hidden)
+	for key, tags := range m.tags {
+		if s.Tags == nil {
+			s.Tags = make(map[string][]string)
+		}
+		s.Tags[key] = tags
+	}
+
+	// Expired snoozes are dropped; seen marks are kept until the status moves
+	now := time.Now()
+	s.Inbox = m.inbox
+	for key, until := range m.snoozed {
+		if now.Before(until) {
+			if s.Snoozed == nil {
+				s.Snoozed = make(map[string]time.Time)
+			}
+			s.Snoozed[key] = until
+		}
+	}
+	for key, st := range m.seen {
+		if s.Seen == nil {
+			s.Seen = make(map[string]state.SeenMark)
+		}
+		s.Seen[key] = st
+	}
+	for class, l := range m.layouts {
+		s.Layouts[class] = l
+	}
+	if m.sizeClass != "" {
+		s.Layouts[m.sizeClass] = m.currentLayout()
+	}
+	return s
+}
+
+// restoreUIState applies a persisted state, ignoring unknown sort/group/view
+// values so an older or hand-edited file can't wedge the UI
+func (m *Model) restoreUIState(s state.UIState) {
+	m.filters.Restore(s)
+	m.filterQuery = s.Query
+	m.filter.SetValue(s.Query)
+	if indexOf(sortOrder, s.SortBy) >= 0 {
+		m.sortBy = s.SortBy
+	}
+	if indexOf(groupOrder, s.GroupBy) >= 0 {
+		m.groupBy = s.GroupBy
+	}
+	m.showEnded = m.showEnded || s.ShowEnded
+	m.showGauges = s.ShowGauges
+	if s.ShowLastMsg && !m.showLastMsg {
+		m.showLastMsg = true
+		if m.cfg.SetIncludeLastMsg != nil {
+			m.cfg.SetIncludeLastMsg(true)
+		}
+	}
+	for _, key := range s.Pinned {
+		m.pinned[key] = true
+	}
+	for _, key := range s.Hidden {
+		m.hidden[key] = true
+	}
+	for key, tags := range s.Tags {
+		m.tags[key] = tags
+	}
+	m.inbox = s.Inbox
+	for key, until := range s.Snoozed {
+		m.snoozed[key] = until
+	}
+	for key, st := range s.Seen {
+		m.seen[key] = st
+	}
+	for class, l := range s.Layouts {
+		m.layouts[class] = l
+	}
+}
+
+func (m *Model) currentLayout() state.Layout {
+	return state.Layout{ShowDetail: m.showDetail, ShowSidebar: m.showSidebar, ViewMode: m.viewMode, Follow: m.following}
+}
+
+// switchLayout stores the layout of the previous size class and applies the
+// one saved for the new class (if any) when the terminal is resized
+func (m *Model) switchLayout(class string) {
+	if class == m.sizeClass {
+		return
+	}
+	if m.sizeClass != "" {
+		m.layouts[m.sizeClass] = m.currentLayout()
+	}
+	m.sizeClass = class
+	l, ok := m.layouts[class]
+	if !ok {
+		return
+	}
+	m.showDetail = l.ShowDetail
+	m.showSidebar = l.ShowSidebar
+	if l.Follow != m.following {
+		m.toggleFollow()
+	}
+	if indexOf(viewOrder, l.ViewMode) >= 0 {
+		m.viewMode = l.ViewMode
+	}
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+// openProjectPicker opens the project picker (p) with a fresh query
+func (m *Model) openProjectPicker() {
+	m.closeOverlays()
+	m.projectsOpen = true
+	m.projectIndex = 0
+	m.project.SetValue("")
+	m.project.Focus()
+}
+
+// openDashboard opens the projects dashboard (tab) with a fresh query
+func (m *Model) openDashboard() {
+	m.closeOverlays()
+	m.showDashboard = true
+	m.projectIndex = 0
+	m.project.SetValue("")
+	m.project.Focus()
+}
+
+// closeOverlays closes the picker, dashboard and palette
+func (m *Model) closeOverlays() {
+	m.projectsOpen = false
+	m.showDashboard = false
+	m.paletteOpen = false
+	m.project.Blur()
+	m.palette.Blur()
+}
+
+// pickerItems returns all projects matching the picker query
+func (m *Model) pickerItems() []state.ProjectItem {
+	return state.FilterProjectItems(state.BuildProjectItems(m.sessions), m.project.Value())
+}
+
+// dashboardItems returns active projects (respecting provider/status filters)
+// matching the dashboard query
+func (m *Model) dashboardItems() []state.ProjectItem {
+	return state.FilterProjectItems(state.FilterDashboardItems(m.sessions, m.filters), m.project.Value())
+}
+
+// handleProjectKeys handles keys while the picker or dashboard is open
+func (m *Model) handleProjectKeys(msg tea.KeyMsg) tea.Cmd {
+	items := m.pickerItems()
+	if m.showDashboard {
+		items = m.dashboardItems()
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit
+	case "esc":
+		m.closeOverlays()
+		return nil
+	case "tab":
+		if m.showDashboard {
+			m.closeOverlays()
+			return nil
+		}
+	case "up", "ctrl+k":
+		m.projectIndex--
+	case "down", "ctrl+j":
+		m.projectIndex++
+	case "enter":
+		if m.showDashboard {
+			// Focus the highlighted project and go back to the list
+			if it, ok := itemAt(items, m.projectIndex); ok {
+				m.filters.SetProject(it.Name)
+				m.closeOverlays()
+				m.applyFilter()
+			}
+			return nil
+		}
+		m.toggleProjectAt(items)
+	case " ":
+		m.toggleProjectAt(items)
+	case "ctrl+a":
+		m.filters.ProjectFilter = make(map[string]bool)
+		m.applyFilter()
+	default:
+		// Plain "a" clears project filters while the query is empty; otherwise
+		// it is typed into the query.
+		if msg.String() == "a" && m.project.Value() == "" {
+			m.filters.ProjectFilter = make(map[string]bool)
+			m.applyFilter()
+			return nil
+		}
+		var cmd tea.Cmd
+		m.project, cmd = m.project.Update(msg)
+		m.projectIndex = 0
+		return cmd
+	}
+
+	m.projectIndex = clampIndex(m.projectIndex, len(items))
+	return nil
+}
+
+func (m *Model) toggleProjectAt(items []state.ProjectItem) {
+	if it, ok := itemAt(items, m.projectIndex); ok {
+		m.filters.ToggleProject(it.Name)
+		m.applyFilter()
+	}
+}
+
+func itemAt(items []state.ProjectItem, idx int) (state.ProjectItem, bool) {
+	if idx < 0 || idx >= len(items) {
+		return state.ProjectItem{}, false
+	}
+	return items[idx], true
+}
+
+func clampIndex(idx, size int) int {
+	if size <= 0 || idx < 0 {
+		return 0
+	}
+	if idx >= size {
+		return size - 1
+	}
+	return idx
+}
@@ -0,0 +1,42 @@
+package state
+
+import "time"
+
+// SessionDetail is the lazily loaded data behind the detail pane tabs
+type SessionDetail struct {
+	Conversation []Turn
+	Redacted     bool // message text was redacted
+	Usage        Usage
+	Timeline     []Transition
+}
+
+// Turn is one message or tool call in the Conversation tab
+type Turn struct {
+	Role string // user | assistant | tool
+	Text string
+	At   time.Time
+}
+
+// Usage holds token, context-window and cost figures for the Usage tab
+type Usage struct {
+	InputTokens       int
+	OutputTokens      int
+	CacheReadTokens   int
+	CacheCreateTokens int
+
+	ContextTokens int // tokens currently in the context window
+	ContextWindow int // window size (0 if unknown)
+
+	Cost         float64
+	Duration     time.Duration // wall time reported by the provider
+	APIDuration  time.Duration
+	LinesAdded   int
+	LinesRemoved int
+}
+
+// Transition is one status change in the Timeline tab
+type Transition struct {
+	At     time.Time
+	Status Status
+	Reason string
+}
@@ -118,10 +118,12 @@ func (f *FilterState) MatchesQuery(s SessionView) bool {
 		hay = strings.ToLower(s.Project)
 	case "status":
 		hay = strings.ToLower(string(s.Status))
+	case "tag":
+		hay = strings.ToLower(strings.Join(s.Tags, " "))
 	default:
-		hay = strings.ToLower(strings.Join([]string{
+		hay = strings.ToLower(strings.Join(append([]string{
 			string(s.Provider), s.ID, s.Project, s.Dir, s.Model,
-		}, " "))
+		}, s.Tags...), " "))
 	}
 
 	return fuzzyMatch(needle, hay)
@@ -136,6 +138,9 @@ func (f *FilterState) ParseQueryMode() {
 	} else if strings.HasPrefix(strings.ToLower(q), "s:") {
 		f.QueryMode = "status"
 		f.TextQuery = strings.TrimSpace(q[2:])
+	} else if strings.HasPrefix(strings.ToLower(q), "t:") {
+		f.QueryMode = "tag"
+		f.TextQuery = strings.TrimPrefix(strings.TrimSpace(q[2:]), "#")
 	} else {
 		f.QueryMode = "all"
 	}
@@ -31,6 +31,7 @@ const (
 type SessionView struct {
 	Provider Provider
 	ID       string
+	Key      string // provider:id, never redacted (for actions, not display)
 	Status   Status
 	Reason   string
 
@@ -41,12 +42,37 @@ type SessionView struct {
 	Cost    float64
 	Age     time.Duration
 
-	LastSeen time.Time
+	LastSeen    time.Time
+	StatusSince time.Time // when the session entered Status (zero if unknown)
 
 	SourcePath string
 	Detail     string
 	LastUser   string
 	LastAssist string
+
+	TurnCount   int
+	TurnElapsed time.Duration // in-flight turn duration, 0 when idle
+	LastTurn    time.Duration
+	MeanTurn    time.Duration
+	P95Turn     time.Duration
+
+	ContextTokens int     // tokens in the context window
+	ContextWindow int     // window size (0 if unknown)
+	BurnRate      float64 // USD per hour (0 if unknown)
+	Activity      []int   // events per minute, oldest first (nil when idle)
+
+	Tags []string // user tags (set by the TUI from its saved state)
+
+	// Pending is a held permission request the TUI can answer (nil if none).
+	Pending *PendingPermission
+}
+
+// PendingPermission is a tool-use permission prompt waiting for a decision
+type PendingPermission struct {
+	ID        string
+	Tool      string
+	Summary   string
+	ExpiresAt time.Time
 }
 
 // RowKind distinguishes between session rows and group header rows
@@ -172,6 +198,16 @@ func (s *AppState) ToggleSelect(id string) {
 	}
 }
 
+// ClearSelection deselects every session
+func (s *AppState) ClearSelection() {
+	clear(s.Selected)
+}
+
+// IsSelected reports whether a session is selected
+func (s *AppState) IsSelected(id string) bool {
+	return s.Selected[id]
+}
+
 // SelectedIDs returns the list of selected session IDs
 func (s *AppState) SelectedIDs() []string {
 	if len(s.Selected) == 0 {
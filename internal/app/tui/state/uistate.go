@@ -0,0 +1,89 @@
+package state
+
+import (
+	"sort"
+	"time"
+)
+
+// UIStateVersion is bumped when the persisted layout changes incompatibly
+const UIStateVersion = 1
+
+// UIState is the TUI state persisted across restarts
+type UIState struct {
+	Version int `json:"version"`
+
+	Pinned []string            `json:"pinned,omitempty"` // session keys (provider:id)
+	Hidden []string            `json:"hidden,omitempty"` // session keys hidden until :unhide
+	Tags   map[string][]string `json:"tags,omitempty"`   // session key -> tags
+
+	Providers []Provider `json:"providers,omitempty"`
+	Statuses  []Status   `json:"statuses,omitempty"`
+	Projects  []string   `json:"projects,omitempty"`
+	Query     string     `json:"query,omitempty"`
+
+	SortBy      string `json:"sort_by,omitempty"`
+	GroupBy     string `json:"group_by"` // "" means ungrouped
+	ShowEnded   bool   `json:"show_ended,omitempty"`
+	ShowLastMsg bool   `json:"show_last_msg,omitempty"`
+	ShowGauges  bool   `json:"show_gauges,omitempty"`
+
+	// Triage inbox: snoozed sessions (until when) and sessions marked seen
+	// (hidden until their status differs from the one recorded)
+	Inbox   bool                 `json:"inbox,omitempty"`
+	Snoozed map[string]time.Time `json:"snoozed,omitempty"`
+	Seen    map[string]SeenMark  `json:"seen,omitempty"`
+
+	// Layouts are kept per terminal size class (see SizeClass)
+	Layouts map[string]Layout `json:"layouts,omitempty"`
+}
+
+// SeenMark is the status a session was marked seen in and when it entered
+// that status; the mark lapses once either differs, so a session that went
+// back to work and is waiting again shows up again
+type SeenMark struct {
+	Status Status    `json:"status"`
+	Since  time.Time `json:"since"`
+}
+
+// Layout holds pane visibility and row style for one terminal size class
+type Layout struct {
+	ShowDetail  bool   `json:"show_detail"`
+	ShowSidebar bool   `json:"show_sidebar"`
+	ViewMode    string `json:"view_mode,omitempty"`
+	Follow      bool   `json:"follow,omitempty"`
+}
+
+// SizeClass buckets a terminal width so layouts follow the window size
+func SizeClass(width int) string {
+	switch {
+	case width < 100:
+		return "narrow"
+	case width < 160:
+		return "normal"
+	default:
+		return "wide"
+	}
+}
+
+// Snapshot captures the filter state for persistence
+func (f *FilterState) Snapshot(s *UIState) {
+	s.Providers = f.ActiveProviders()
+	sort.Slice(s.Providers, func(i, j int) bool { return s.Providers[i] < s.Providers[j] })
+	s.Statuses = f.ActiveStatuses()
+	sort.Slice(s.Statuses, func(i, j int) bool { return s.Statuses[i] < s.Statuses[j] })
+	s.Projects = f.ActiveProjects()
+}
+
+// Restore replaces the filter state with a persisted one
+func (f *FilterState) Restore(s UIState) {
+	f.Clear()
+	for _, p := range s.Providers {
+		f.ProviderFilter[p] = true
+	}
+	for _, st := range s.Statuses {
+		f.StatusFilter[st] = true
+	}
+	for _, p := range s.Projects {
+		f.ToggleProject(p)
+	}
+}
@@ -0,0 +1,98 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Auto picks dark or light from the terminal background
+const Auto = "auto"
+
+// Builtins are the themes compiled into aistat
+var Builtins = []Theme{Default, Light, HighContrast}
+
+// Info describes an available theme
+type Info struct {
+	Name string
+	Path string // "" for built-ins
+}
+
+// List returns the built-in themes followed by *.json files in dir
+func List(dir string) []Info {
+	var out []Info
+	for _, t := range Builtins {
+		out = append(out, Info{Name: t.Name})
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	sort.Strings(files)
+	for _, f := range files {
+		out = append(out, Info{Name: strings.TrimSuffix(filepath.Base(f), ".json"), Path: f})
+	}
+	return out
+}
+
+// Load resolves a theme by name: "auto" (or ""), a built-in, or <dir>/<name>.json.
+//
+// A theme file holds any of the Theme color keys plus an optional "base"
+// (a built-in name, default "dark"); missing colors come from the base:
+//
+//	{"base": "light", "needs_input": "#c2410c", "highlight": "#fde68a"}
+func Load(name, dir string) (Theme, error) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	if name == "" || name == Auto {
+		return Detect(), nil
+	}
+	if t, ok := builtin(name); ok {
+		return t, nil
+	}
+
+	p := filepath.Join(dir, name+".json")
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return Default, fmt.Errorf("unknown theme %q (no built-in and %s: %w)", name, p, err)
+	}
+	var hdr struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(b, &hdr); err != nil {
+		return Default, fmt.Errorf("theme %s: %w", p, err)
+	}
+	base := Default
+	if hdr.Base != "" {
+		t, ok := builtin(strings.ToLower(hdr.Base))
+		if !ok {
+			return Default, fmt.Errorf("theme %s: unknown base %q", p, hdr.Base)
+		}
+		base = t
+	}
+	t := base
+	if err := json.Unmarshal(b, &t); err != nil {
+		return Default, fmt.Errorf("theme %s: %w", p, err)
+	}
+	t.Name = name
+	return t, nil
+}
+
+// Detect returns Light on light terminal backgrounds and Default otherwise.
+// It queries the terminal, so call it before the TUI takes over the screen.
+func Detect() Theme {
+	if lipgloss.HasDarkBackground() {
+		return Default
+	}
+	return Light
+}
+
+func builtin(name string) (Theme, bool) {
+	for _, t := range Builtins {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
@@ -2,40 +2,44 @@ package theme
 
 import "github.com/charmbracelet/lipgloss"
 
-// Theme defines the color scheme for the TUI (single dark theme)
+// Theme defines the color scheme for the TUI. The JSON names are the keys
+// accepted in theme files (see Load).
 type Theme struct {
+	Name string `json:"-"`
+
 	// Background layers
-	Background lipgloss.Color
-	Surface    lipgloss.Color
-	Highlight  lipgloss.Color
-	Border     lipgloss.Color
+	Background lipgloss.Color `json:"background,omitempty"`
+	Surface    lipgloss.Color `json:"surface,omitempty"`
+	Highlight  lipgloss.Color `json:"highlight,omitempty"`
+	Border     lipgloss.Color `json:"border,omitempty"`
 
 	// Text hierarchy
-	Text  lipgloss.Color
-	Dim80 lipgloss.Color // 1-6h age
-	Dim60 lipgloss.Color // 6-24h age
-	Muted lipgloss.Color
-	Dim40 lipgloss.Color // >24h age
-	Faint lipgloss.Color
+	Text  lipgloss.Color `json:"text,omitempty"`
+	Dim80 lipgloss.Color `json:"dim80,omitempty"` // 1-6h age
+	Dim60 lipgloss.Color `json:"dim60,omitempty"` // 6-24h age
+	Muted lipgloss.Color `json:"muted,omitempty"`
+	Dim40 lipgloss.Color `json:"dim40,omitempty"` // >24h age
+	Faint lipgloss.Color `json:"faint,omitempty"`
 
 	// Status colors (3 states only)
-	Active     lipgloss.Color // Running, healthy
-	Idle       lipgloss.Color // Waiting
-	NeedsInput lipgloss.Color // Approval/attention required
-	Error      lipgloss.Color // Error state
+	Active     lipgloss.Color `json:"active,omitempty"`      // Running, healthy
+	Idle       lipgloss.Color `json:"idle,omitempty"`        // Waiting
+	NeedsInput lipgloss.Color `json:"needs_input,omitempty"` // Approval/attention required
+	Error      lipgloss.Color `json:"error,omitempty"`       // Error state
 
 	// Provider colors
-	Claude lipgloss.Color
-	Codex  lipgloss.Color
+	Claude lipgloss.Color `json:"claude,omitempty"`
+	Codex  lipgloss.Color `json:"codex,omitempty"`
 
 	// Model colors
-	ModelOpus   lipgloss.Color
-	ModelSonnet lipgloss.Color
-	ModelHaiku  lipgloss.Color
+	ModelOpus   lipgloss.Color `json:"model_opus,omitempty"`
+	ModelSonnet lipgloss.Color `json:"model_sonnet,omitempty"`
+	ModelHaiku  lipgloss.Color `json:"model_haiku,omitempty"`
 }
 
-// Default is the single dark theme
+// Default is the dark theme
 var Default = Theme{
+	Name:       "dark",
 	Background: ColorBackground,
 	Surface:    ColorSurface,
 	Highlight:  ColorHighlight,
@@ -58,7 +62,57 @@ var Default = Theme{
 	ModelHaiku:  ColorModelHaiku,
 }
 
-// Current returns the current theme (always Default since we only have one)
+// Light is tuned for light terminal backgrounds
+var Light = Theme{
+	Name:       "light",
+	Background: lipgloss.Color("#eff1f5"),
+	Surface:    lipgloss.Color("#e6e9ef"),
+	Highlight:  lipgloss.Color("#dce0e8"),
+	Border:     lipgloss.Color("#bcc0cc"),
+	Text:       lipgloss.Color("#4c4f69"),
+	Dim80:      lipgloss.Color("#5c5f77"),
+	Dim60:      lipgloss.Color("#6c6f85"),
+	Muted:      lipgloss.Color("#7c7f93"),
+	Dim40:      lipgloss.Color("#9ca0b0"),
+	Faint:      lipgloss.Color("#acb0be"),
+	Active:     lipgloss.Color("#40a02b"),
+	Idle:       lipgloss.Color("#1e66f5"),
+	NeedsInput: lipgloss.Color("#d35400"),
+	Error:      lipgloss.Color("#d20f39"),
+	Claude:     lipgloss.Color("#8839ef"),
+	Codex:      lipgloss.Color("#179299"),
+
+	ModelOpus:   lipgloss.Color("#8839ef"),
+	ModelSonnet: lipgloss.Color("#1e66f5"),
+	ModelHaiku:  lipgloss.Color("#179299"),
+}
+
+// HighContrast uses saturated colors on black for low-vision use
+var HighContrast = Theme{
+	Name:       "high-contrast",
+	Background: lipgloss.Color("#000000"),
+	Surface:    lipgloss.Color("#000000"),
+	Highlight:  lipgloss.Color("#303030"),
+	Border:     lipgloss.Color("#ffffff"),
+	Text:       lipgloss.Color("#ffffff"),
+	Dim80:      lipgloss.Color("#ffffff"),
+	Dim60:      lipgloss.Color("#e0e0e0"),
+	Muted:      lipgloss.Color("#c0c0c0"),
+	Dim40:      lipgloss.Color("#a0a0a0"),
+	Faint:      lipgloss.Color("#808080"),
+	Active:     lipgloss.Color("#00ff00"),
+	Idle:       lipgloss.Color("#00ffff"),
+	NeedsInput: lipgloss.Color("#ffff00"),
+	Error:      lipgloss.Color("#ff5555"),
+	Claude:     lipgloss.Color("#ff87ff"),
+	Codex:      lipgloss.Color("#5fffd7"),
+
+	ModelOpus:   lipgloss.Color("#ff87ff"),
+	ModelSonnet: lipgloss.Color("#87afff"),
+	ModelHaiku:  lipgloss.Color("#5fffd7"),
+}
+
+// Current returns the dark theme (kept for callers that don't pick a theme)
 func Current() Theme {
 	return Default
 }
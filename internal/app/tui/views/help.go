@@ -6,38 +6,25 @@ import (
 	"github.com/vburojevic/aistat/internal/app/tui/theme"
 )
 
+// HelpEntry is one line of the help overlay; an empty Key is a blank line
+type HelpEntry struct {
+	Key  string
+	Desc string
+}
+
 // RenderHelpOverlay renders the help overlay modal
-func RenderHelpOverlay(styles theme.Styles) string {
+func RenderHelpOverlay(entries []HelpEntry, styles theme.Styles) string {
 	lines := []string{
 		styles.HelpTitle.Render("Keyboard Shortcuts"),
 		"",
 	}
 
-	// Navigation
-	shortcuts := []struct {
-		key  string
-		desc string
-	}{
-		{"j / ↓", "Move down"},
-		{"k / ↑", "Move up"},
-		{"enter", "Select session"},
-		{"/", "Filter sessions"},
-		{"esc", "Clear filter"},
-		{"?", "Toggle help"},
-		{"q", "Quit"},
-		{"", ""},
-		{"b", "Toggle bookmark ★"},
-		{"y", "Copy session ID"},
-		{"r", "Refresh now"},
-		{"a", "Toggle show all"},
-	}
-
-	for _, s := range shortcuts {
-		if s.key == "" {
+	for _, e := range entries {
+		if e.Key == "" {
 			lines = append(lines, "")
 			continue
 		}
-		lines = append(lines, styles.HelpKey.Render(s.key)+styles.HelpDesc.Render(s.desc))
+		lines = append(lines, styles.HelpKey.Render(e.Key)+styles.HelpDesc.Render(e.Desc))
 	}
 
 	lines = append(lines, "")
@@ -0,0 +1,31 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
+)
+
+// RenderPalette renders the command palette overlay (:)
+func RenderPalette(input, preview string, styles theme.Styles, width int) string {
+	lines := []string{
+		styles.HelpTitle.Render("Command Palette"),
+		styles.FilterPrompt.Render(": ") + input,
+		"",
+		styles.Muted.Render(preview),
+		"",
+		styles.Muted.Render("enter run • tab complete • esc close"),
+	}
+	return styles.HelpOverlay.Width(width).Render(strings.Join(lines, "\n"))
+}
+
+// RenderConfirm renders a y/n prompt for a destructive bulk action
+func RenderConfirm(prompt string, styles theme.Styles) string {
+	lines := []string{
+		styles.HelpTitle.Render("Confirm"),
+		prompt,
+		"",
+		styles.HelpKey.Render("y") + styles.Muted.Render(" confirm • any other key cancels"),
+	}
+	return styles.HelpOverlay.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,151 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
+	"github.com/vburojevic/aistat/internal/app/tui/widgets"
+)
+
+// RenderProjectPicker renders the project picker overlay (p)
+func RenderProjectPicker(items []state.ProjectItem, cursor int, input string, filters *state.FilterState, styles theme.Styles, height int) string {
+	lines := []string{
+		styles.HelpTitle.Render("Projects"),
+		styles.Muted.Render("enter/space toggle • a clear • esc close"),
+		"",
+		input,
+		"",
+	}
+
+	if len(items) == 0 {
+		lines = append(lines, styles.Muted.Render("No projects found."))
+		return styles.HelpOverlay.Render(strings.Join(lines, "\n"))
+	}
+
+	start, end := visibleRange(len(items), cursor, height-14)
+	for i := start; i < end; i++ {
+		it := items[i]
+		line := fmt.Sprintf("%s %-20s %4d  %-11s  ▶%d ⏸%d ⚡%d",
+			check(filters.ProjectFilter[strings.ToLower(it.Name)]),
+			widgets.TruncateString(it.Name, 20),
+			it.Count,
+			lastSeen(it.LastSeen),
+			it.StatusCount[state.StatusRunning],
+			it.StatusCount[state.StatusWaiting],
+			it.StatusCount[state.StatusApproval]+it.StatusCount[state.StatusNeedsAttn],
+		)
+		lines = append(lines, pickerLine(line, i == cursor, styles))
+	}
+
+	return styles.HelpOverlay.Render(strings.Join(lines, "\n"))
+}
+
+// RenderDashboard renders the projects dashboard (tab): active projects with
+// per-status counts, plus a detail block for the highlighted project
+func RenderDashboard(items []state.ProjectItem, cursor int, input string, filters *state.FilterState, styles theme.Styles, height int) string {
+	lines := []string{
+		styles.HelpTitle.Render("Projects Dashboard"),
+		styles.Muted.Render("enter focus • space toggle • a clear • tab back"),
+		"",
+		input,
+		"",
+		styles.Muted.Render(fmt.Sprintf("  %-20s %4s  %3s %3s %3s %3s %3s %3s  %s",
+			"PROJECT", "CNT", "▶", "⏸", "⚠", "‼", "…", "✓", "LAST")),
+	}
+
+	if len(items) == 0 {
+		lines = append(lines, styles.Muted.Render("No active projects found."))
+		return styles.HelpOverlay.Render(strings.Join(lines, "\n"))
+	}
+
+	start, end := visibleRange(len(items), cursor, height-24)
+	for i := start; i < end; i++ {
+		it := items[i]
+		line := fmt.Sprintf("%s %-20s %4d  %3d %3d %3d %3d %3d %3d  %s",
+			check(filters.ProjectFilter[strings.ToLower(it.Name)]),
+			widgets.TruncateString(it.Name, 20),
+			it.Count,
+			it.StatusCount[state.StatusRunning],
+			it.StatusCount[state.StatusWaiting],
+			it.StatusCount[state.StatusApproval],
+			it.StatusCount[state.StatusNeedsAttn],
+			it.StatusCount[state.StatusStale],
+			it.StatusCount[state.StatusEnded],
+			lastSeen(it.LastSeen),
+		)
+		lines = append(lines, pickerLine(line, i == cursor, styles))
+	}
+
+	if cursor >= 0 && cursor < len(items) {
+		lines = append(lines, "", renderProjectDetail(items[cursor], styles))
+	}
+
+	return styles.HelpOverlay.Render(strings.Join(lines, "\n"))
+}
+
+func renderProjectDetail(it state.ProjectItem, styles theme.Styles) string {
+	var providers []string
+	for p, c := range it.Providers {
+		providers = append(providers, fmt.Sprintf("%s:%d", p, c))
+	}
+	sort.Strings(providers)
+
+	rows := [][2]string{
+		{"Project", it.Name},
+		{"Total", widgets.FormatInt(it.Count)},
+		{"Running", widgets.FormatInt(it.StatusCount[state.StatusRunning])},
+		{"Waiting", widgets.FormatInt(it.StatusCount[state.StatusWaiting])},
+		{"Approval", widgets.FormatInt(it.StatusCount[state.StatusApproval])},
+		{"Attention", widgets.FormatInt(it.StatusCount[state.StatusNeedsAttn])},
+	}
+	if len(providers) > 0 {
+		rows = append(rows, [2]string{"Providers", strings.Join(providers, ", ")})
+	}
+	if !it.LastSeen.IsZero() {
+		rows = append(rows, [2]string{"Last seen", it.LastSeen.In(time.Local).Format("2006-01-02 15:04")})
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, r := range rows {
+		lines = append(lines, styles.Label.Render(r[0])+styles.Value.Render(r[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// visibleRange returns the window of rows to draw so the cursor stays visible
+func visibleRange(total, cursor, maxRows int) (int, int) {
+	maxRows = widgets.MaxInt(6, maxRows)
+	if maxRows > total {
+		maxRows = total
+	}
+	start := 0
+	if cursor >= maxRows {
+		start = cursor - maxRows + 1
+	}
+	return start, widgets.MinInt(total, start+maxRows)
+}
+
+func check(active bool) string {
+	if active {
+		return "●"
+	}
+	return " "
+}
+
+func lastSeen(ts time.Time) string {
+	if ts.IsZero() {
+		return ""
+	}
+	return ts.In(time.Local).Format("01-02 15:04")
+}
+
+func pickerLine(line string, selected bool, styles theme.Styles) string {
+	if selected {
+		return styles.Selected.Render(line)
+	}
+	return line
+}
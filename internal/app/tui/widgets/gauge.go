@@ -0,0 +1,84 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
+)
+
+// Gauge renders a horizontal bar filled to ratio (0..1), colored green,
+// yellow then red as it fills
+func Gauge(ratio float64, width int, styles theme.Styles) string {
+	if width <= 0 {
+		return ""
+	}
+	ratio = ClampFloat(ratio, 0, 1)
+	filled := int(ratio*float64(width) + 0.5)
+	bar := strings.Repeat("█", filled)
+	rest := strings.Repeat("░", width-filled)
+
+	style := styles.DotActive
+	switch {
+	case ratio >= 0.9:
+		style = styles.ErrorText
+	case ratio >= 0.7:
+		style = styles.StatusWaiting
+	}
+	return style.Render(bar) + styles.Muted.Render(rest)
+}
+
+// FormatTokens formats a token count compactly (950, 12.3k, 1.2M)
+func FormatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// ClampFloat restricts val to [min, max]
+func ClampFloat(val, min, max float64) float64 {
+	if val < min {
+		return min
+	}
+	if val > max {
+		return max
+	}
+	return val
+}
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders counts as one bar per value, scaled to the largest
+func Sparkline(values []int, styles theme.Styles) string {
+	peak := 0
+	for _, v := range values {
+		peak = MaxInt(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || peak == 0 {
+			b.WriteString(styles.Muted.Render(string(sparkLevels[0])))
+			continue
+		}
+		// Any activity shows at least the second level
+		level := (v*(len(sparkLevels)-1) + peak - 1) / peak
+		b.WriteString(styles.DotActive.Render(string(sparkLevels[level])))
+	}
+	return b.String()
+}
+
+// FormatBurnRate formats a cost rate as $/hour ("" when unknown)
+func FormatBurnRate(perHour float64) string {
+	if perHour <= 0 {
+		return ""
+	}
+	if perHour < 10 {
+		return fmt.Sprintf("$%.2f/h", perHour)
+	}
+	return fmt.Sprintf("$%.0f/h", perHour)
+}
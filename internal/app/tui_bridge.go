@@ -1,15 +1,22 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/vburojevic/aistat/internal/app/tui"
 	"github.com/vburojevic/aistat/internal/app/tui/state"
+	"github.com/vburojevic/aistat/internal/app/tui/theme"
 )
 
-// runTUINew runs the new redesigned TUI
-func runTUINew(cfg Config) (err error) {
+// runTUINew runs the new redesigned TUI. resetUI discards the saved UI state.
+func runTUINew(cfg Config, resetUI bool) (err error) {
 	// Ensure terminal state is restored on panic/crash
 	defer func() {
 		if r := recover(); r != nil {
@@ -20,23 +27,247 @@ func runTUINew(cfg Config) (err error) {
 			panic(r)                             // Re-panic after cleanup
 		}
 	}()
+	// The fetcher runs off the UI goroutine; the last-message toggle is shared.
+	var includeLast atomic.Bool
+	includeLast.Store(cfg.IncludeLastMsg)
+
 	tuiCfg := tui.Config{
-		RefreshEvery: cfg.RefreshEvery,
-		MaxSessions:  cfg.MaxSessions,
-		ShowEnded:    cfg.IncludeEnded,
+		RefreshEvery:      cfg.RefreshEvery,
+		MaxSessions:       cfg.MaxSessions,
+		ShowEnded:         cfg.IncludeEnded,
+		IncludeLastMsg:    cfg.IncludeLastMsg,
+		NoColor:           cfg.NoColor,
+		Accessible:        cfg.TUIAccessible,
+		AnswerPermission:  answerPermission,
+		SetIncludeLastMsg: includeLast.Store,
+		OpenSession: func(key string) error {
+			return openSessionByKey(cfg.redaction(), key)
+		},
+		SaveUIState: saveUIState,
+	}
+	records := &recordCache{}
+	tuiCfg.LoadDetail = func(key string) (state.SessionDetail, error) {
+		rec, ok := records.get(key)
+		if !ok {
+			return state.SessionDetail{}, fmt.Errorf("session no longer listed")
+		}
+		return loadSessionDetail(rec, cfg)
+	}
+	tuiCfg.FollowSession = func(key string, offset int64) ([]state.Turn, int64, error) {
+		rec, ok := records.get(key)
+		if !ok {
+			return nil, offset, fmt.Errorf("session no longer listed")
+		}
+		turns, next, err := followTranscript(rec, offset, cfg)
+		return tuiTurns(turns, cfg), next, err
+	}
+	tuiCfg.EndSessions = func(keys []string) error {
+		return markSessionsEnded(keys, time.Now().UTC())
+	}
+	tuiCfg.ResumeCommand = func(key string) (string, error) {
+		rec, ok := records.get(key)
+		if !ok {
+			return "", fmt.Errorf("session no longer listed")
+		}
+		return resumeCommand(rec), nil
+	}
+	tuiCfg.IngestStatus = func() (string, bool) {
+		return ingestStatus(time.Now().UTC())
+	}
+	if dir, err := themesDir(); err == nil {
+		t, err := theme.Load(cfg.TUITheme, dir)
+		if err != nil {
+			tuiCfg.Notice = err.Error()
+			t = theme.Detect()
+		}
+		tuiCfg.Theme = t
+	}
+	tuiCfg.Keys = cfg.TUIKeys
+	if resetUI {
+		if p, err := uiStatePath(); err == nil {
+			_ = os.Remove(p)
+		}
+	} else {
+		tuiCfg.UIState = loadUIState()
 	}
 
 	fetcher := func() ([]state.SessionView, error) {
-		views, err := gatherSessions(cfg)
+		fetchCfg := cfg
+		fetchCfg.IncludeLastMsg = includeLast.Load()
+		now := time.Now().UTC()
+		recs, err := gatherRecords(fetchCfg, now)
 		if err != nil {
 			return nil, err
 		}
-		return convertSessionViews(views), nil
+		records.set(recs)
+		views := viewsFromRecords(recs, fetchCfg, now)
+		out := convertSessionViews(views)
+		attachPendingPermissions(views, out, now)
+		return out, nil
+	}
+	if cfg.PermissionHold > 0 {
+		// Advertise that permission prompts can be answered here.
+		defer keepPermissionHeartbeat()()
 	}
 
 	return tui.Run(tuiCfg, fetcher)
 }
 
+// recordCache keeps the records behind the last fetch so the detail tabs can
+// load one session without another scan.
+type recordCache struct {
+	mu    sync.Mutex
+	byKey map[string]SessionRecord
+}
+
+func (c *recordCache) set(recs []SessionRecord) {
+	m := make(map[string]SessionRecord, len(recs))
+	for _, r := range recs {
+		m[keyFor(r.Provider, r.ID)] = r
+	}
+	c.mu.Lock()
+	c.byKey = m
+	c.mu.Unlock()
+}
+
+func (c *recordCache) get(key string) (SessionRecord, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.byKey[key]
+	return r, ok
+}
+
+// loadSessionDetail parses the transcript/rollout tail of one session for the
+// Conversation, Usage and Timeline tabs.
+func loadSessionDetail(r SessionRecord, cfg Config) (state.SessionDetail, error) {
+	turns, tokens, err := readConversation(r, cfg)
+	if err != nil {
+		return state.SessionDetail{}, err
+	}
+
+	d := state.SessionDetail{
+		Conversation: tuiTurns(turns, cfg),
+		Redacted:     cfg.Redact && len(turns) > 0,
+	}
+
+	d.Usage = state.Usage{
+		InputTokens:       r.TotalInputTokens,
+		OutputTokens:      r.TotalOutputTokens,
+		CacheReadTokens:   r.CurrentCacheReadTokens,
+		CacheCreateTokens: r.CurrentCacheCreateTokens,
+		ContextTokens:     r.CurrentInputTokens + r.CurrentCacheCreateTokens + r.CurrentCacheReadTokens,
+		ContextWindow:     r.ContextWindowSize,
+		Cost:              r.CostUSD,
+		Duration:          time.Duration(r.DurationMS) * time.Millisecond,
+		APIDuration:       time.Duration(r.APIDurationMS) * time.Millisecond,
+		LinesAdded:        r.LinesAdded,
+		LinesRemoved:      r.LinesRemoved,
+	}
+	if r.Provider == ProviderCodex && tokens.InputTokens+tokens.OutputTokens > 0 {
+		d.Usage.InputTokens = tokens.InputTokens
+		d.Usage.OutputTokens = tokens.OutputTokens
+		d.Usage.CacheReadTokens = tokens.CachedInputTokens
+		d.Usage.ContextTokens = tokens.ContextTokens
+		d.Usage.ContextWindow = tokens.ContextWindow
+	}
+
+	for _, t := range r.Transitions {
+		d.Timeline = append(d.Timeline, state.Transition{At: t.At, Status: state.Status(t.Status), Reason: t.Reason})
+	}
+	return d, nil
+}
+
+// tuiTurns converts parsed turns for the TUI, redacting message text when
+// the config asks.
+func tuiTurns(turns []ConversationTurn, cfg Config) []state.Turn {
+	out := make([]state.Turn, 0, len(turns))
+	for _, t := range turns {
+		text := t.Text
+		if cfg.Redact {
+			// Tool calls keep their name; arguments may hold paths or secrets.
+			if t.Role == "tool" {
+				text, _, _ = strings.Cut(text, ":")
+			} else {
+				text = cfg.redaction().message(text)
+			}
+		}
+		out = append(out, state.Turn{Role: t.Role, Text: text, At: t.At})
+	}
+	return out
+}
+
+// uiStatePath is where the TUI keeps pins, filters and layouts between runs.
+func uiStatePath() (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tui_state.json"), nil
+}
+
+// loadUIState returns the saved UI state, or nil when missing, unreadable or
+// written by an incompatible version.
+func loadUIState() *state.UIState {
+	p, err := uiStatePath()
+	if err != nil {
+		return nil
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	var s state.UIState
+	if err := json.Unmarshal(b, &s); err != nil || s.Version != state.UIStateVersion {
+		return nil
+	}
+	return &s
+}
+
+func saveUIState(s state.UIState) error {
+	p, err := uiStatePath()
+	if err != nil {
+		return err
+	}
+	return writeJSONAtomic(p, s)
+}
+
+// openSessionByKey opens a session's transcript/log given its provider:id key.
+func openSessionByKey(rd *redactor, key string) error {
+	provider, id, ok := strings.Cut(key, ":")
+	if !ok {
+		return fmt.Errorf("invalid session key %q", key)
+	}
+	return openSourceForSession(rd, Provider(provider), id)
+}
+
+// attachPendingPermissions links held permission requests to their sessions
+// (oldest request first when a session has several).
+func attachPendingPermissions(views []SessionView, out []state.SessionView, now time.Time) {
+	reqs, err := listPendingPermissions(now)
+	if err != nil || len(reqs) == 0 {
+		return
+	}
+	byKey := map[string]PermissionRequest{}
+	for _, r := range reqs {
+		k := keyFor(ProviderClaude, r.SessionID)
+		if _, ok := byKey[k]; !ok {
+			byKey[k] = r
+		}
+	}
+	for i, v := range views {
+		r, ok := byKey[v.Key]
+		if !ok {
+			continue
+		}
+		out[i].Pending = &state.PendingPermission{
+			ID:        r.ID,
+			Tool:      r.Tool,
+			Summary:   r.Summary,
+			ExpiresAt: r.ExpiresAt,
+		}
+	}
+}
+
 // convertSessionViews converts app.SessionView slice to state.SessionView slice
 func convertSessionViews(views []SessionView) []state.SessionView {
 	result := make([]state.SessionView, len(views))
@@ -44,6 +275,7 @@ func convertSessionViews(views []SessionView) []state.SessionView {
 		result[i] = state.SessionView{
 			Provider:   state.Provider(v.Provider),
 			ID:         v.ID,
+			Key:        v.Key,
 			Status:     state.Status(v.Status),
 			Reason:     v.Reason,
 			Project:    v.Project,
@@ -54,9 +286,22 @@ func convertSessionViews(views []SessionView) []state.SessionView {
 			Age:        v.Age,
 			LastSeen:   v.LastSeen,
 			SourcePath: v.SourcePath,
-			Detail:     v.Detail,
-			LastUser:   v.LastUser,
-			LastAssist: v.LastAssist,
+
+			StatusSince: v.StatusSince,
+			Detail:      v.Detail,
+			LastUser:    v.LastUser,
+			LastAssist:  v.LastAssist,
+
+			TurnCount:   v.TurnCount,
+			TurnElapsed: v.TurnElapsed,
+			LastTurn:    v.LastTurn,
+			MeanTurn:    v.MeanTurn,
+			P95Turn:     v.P95Turn,
+
+			ContextTokens: v.ContextTokens,
+			ContextWindow: v.ContextWindow,
+			BurnRate:      v.BurnRate,
+			Activity:      v.Activity,
 		}
 	}
 	return result
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vburojevic/aistat/internal/app/tui/state"
+)
+
+func TestUIStateRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	if got := loadUIState(); got != nil {
+		t.Fatalf("expected nil state before save, got %+v", got)
+	}
+
+	want := state.UIState{
+		Version:   state.UIStateVersion,
+		Pinned:    []string{"claude:abc"},
+		Statuses:  []state.Status{state.StatusWaiting},
+		Query:     "api",
+		SortBy:    "cost",
+		GroupBy:   "",
+		ShowEnded: true,
+		Inbox:     true,
+		Snoozed:   map[string]time.Time{"codex:def": time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Seen:      map[string]state.SeenMark{"claude:abc": {Status: state.StatusWaiting, Since: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		Hidden:    []string{"codex:old"},
+		Tags:      map[string][]string{"claude:abc": {"review"}},
+		Layouts: map[string]state.Layout{
+			"narrow": {ShowDetail: false, ViewMode: "ultra"},
+		},
+	}
+	if err := saveUIState(want); err != nil {
+		t.Fatalf("saveUIState error: %v", err)
+	}
+	got := loadUIState()
+	if got == nil {
+		t.Fatalf("expected saved state")
+	}
+	if len(got.Pinned) != 1 || got.Pinned[0] != "claude:abc" || got.Query != "api" || got.SortBy != "cost" || got.GroupBy != "" || !got.ShowEnded {
+		t.Fatalf("unexpected state: %+v", got)
+	}
+	if !got.Inbox || !got.Snoozed["codex:def"].Equal(want.Snoozed["codex:def"]) || got.Seen["claude:abc"].Status != state.StatusWaiting || !got.Seen["claude:abc"].Since.Equal(want.Seen["claude:abc"].Since) {
+		t.Fatalf("unexpected inbox state: %+v", got)
+	}
+	if len(got.Hidden) != 1 || got.Hidden[0] != "codex:old" || len(got.Tags["claude:abc"]) != 1 || got.Tags["claude:abc"][0] != "review" {
+		t.Fatalf("unexpected hidden/tags: %+v %+v", got.Hidden, got.Tags)
+	}
+	if l := got.Layouts["narrow"]; l.ShowDetail || l.ViewMode != "ultra" {
+		t.Fatalf("unexpected layout: %+v", l)
+	}
+
+	// A state file from another version is ignored rather than half-applied.
+	p, err := uiStatePath()
+	if err != nil {
+		t.Fatalf("uiStatePath error: %v", err)
+	}
+	if err := os.WriteFile(p, []byte(`{"version":99,"query":"x"}`), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	if got := loadUIState(); got != nil {
+		t.Fatalf("expected nil for mismatched version, got %+v", got)
+	}
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"sort"
+	"time"
+)
+
+// -------------------------
+// Turn tracking
+// -------------------------
+
+// beginTurn marks a turn as in flight. A prompt submitted while a previous
+// turn never reported completion (e.g. the user interrupted it) restarts the
+// clock without recording a duration.
+func beginTurn(rec *SessionRecord, at time.Time) {
+	rec.TurnStartedAt = ptrTime(at)
+}
+
+// completeTurn closes the in-flight turn, counts it and records its duration.
+func completeTurn(rec *SessionRecord, at time.Time) {
+	if rec.TurnStartedAt != nil && !at.Before(*rec.TurnStartedAt) {
+		d := at.Sub(*rec.TurnStartedAt)
+		rec.TurnDurationsMS = append(rec.TurnDurationsMS, d.Milliseconds())
+		if len(rec.TurnDurationsMS) > maxTurnSamples {
+			rec.TurnDurationsMS = rec.TurnDurationsMS[len(rec.TurnDurationsMS)-maxTurnSamples:]
+		}
+	}
+	rec.TurnCount++
+	rec.TurnStartedAt = nil
+	rec.TurnEndedAt = ptrTime(at)
+}
+
+// closeTurn clears the in-flight turn without counting it (session ended,
+// turn aborted, or a completion signal that is counted elsewhere).
+func closeTurn(rec *SessionRecord, at time.Time) {
+	if rec.TurnStartedAt == nil {
+		return
+	}
+	rec.TurnStartedAt = nil
+	rec.TurnEndedAt = ptrTime(at)
+}
+
+func turnInFlight(r SessionRecord) bool {
+	if r.TurnStartedAt == nil || r.TurnStartedAt.IsZero() {
+		return false
+	}
+	if r.TurnEndedAt != nil && r.TurnEndedAt.After(*r.TurnStartedAt) {
+		return false
+	}
+	return true
+}
+
+// turnBoundary returns the most recent turn start/end observed for a record.
+func turnBoundary(r SessionRecord) time.Time {
+	var t time.Time
+	if r.TurnStartedAt != nil {
+		t = maxTime(t, *r.TurnStartedAt)
+	}
+	if r.TurnEndedAt != nil {
+		t = maxTime(t, *r.TurnEndedAt)
+	}
+	return t
+}
+
+// mergeTurns folds turn data from src into cur: counts/durations come from
+// whichever side saw more turns, in-flight state from the newest boundary.
+func mergeTurns(cur *SessionRecord, src SessionRecord) {
+	if src.TurnCount > cur.TurnCount {
+		cur.TurnCount = src.TurnCount
+		cur.TurnDurationsMS = src.TurnDurationsMS
+	}
+	if turnBoundary(src).After(turnBoundary(*cur)) {
+		cur.TurnStartedAt = src.TurnStartedAt
+		cur.TurnEndedAt = src.TurnEndedAt
+	}
+}
+
+// turnStats returns the last, mean and p95 completed turn durations.
+func turnStats(r SessionRecord) (last, mean, p95 time.Duration) {
+	n := len(r.TurnDurationsMS)
+	if n == 0 {
+		return 0, 0, 0
+	}
+	last = time.Duration(r.TurnDurationsMS[n-1]) * time.Millisecond
+
+	sorted := append([]int64(nil), r.TurnDurationsMS...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total int64
+	for _, ms := range sorted {
+		total += ms
+	}
+	mean = time.Duration(total/int64(n)) * time.Millisecond
+	p95 = time.Duration(percentileMS(sorted, 0.95)) * time.Millisecond
+	return last, mean, p95
+}
+
+// percentileMS returns the nearest-rank percentile of an ascending slice.
+func percentileMS(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p+0.999999) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTurnLifecycle(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	var rec SessionRecord
+
+	beginTurn(&rec, start)
+	if !turnInFlight(rec) {
+		t.Fatalf("expected turn in flight")
+	}
+	completeTurn(&rec, start.Add(2*time.Minute))
+	if turnInFlight(rec) {
+		t.Fatalf("expected turn complete")
+	}
+
+	beginTurn(&rec, start.Add(3*time.Minute))
+	completeTurn(&rec, start.Add(4*time.Minute))
+
+	// Aborted turns are not counted.
+	beginTurn(&rec, start.Add(5*time.Minute))
+	closeTurn(&rec, start.Add(6*time.Minute))
+
+	if rec.TurnCount != 2 {
+		t.Fatalf("expected 2 turns, got %d", rec.TurnCount)
+	}
+	last, mean, p95 := turnStats(rec)
+	if last != time.Minute {
+		t.Fatalf("unexpected last turn: %v", last)
+	}
+	if mean != 90*time.Second {
+		t.Fatalf("unexpected mean turn: %v", mean)
+	}
+	if p95 != 2*time.Minute {
+		t.Fatalf("unexpected p95 turn: %v", p95)
+	}
+}
+
+func TestTurnSamplesBounded(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	var rec SessionRecord
+	for i := 0; i < maxTurnSamples+10; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+		beginTurn(&rec, at)
+		completeTurn(&rec, at.Add(time.Second))
+	}
+	if rec.TurnCount != maxTurnSamples+10 {
+		t.Fatalf("unexpected turn count: %d", rec.TurnCount)
+	}
+	if len(rec.TurnDurationsMS) != maxTurnSamples {
+		t.Fatalf("expected %d samples, got %d", maxTurnSamples, len(rec.TurnDurationsMS))
+	}
+}
+
+func TestDeriveStatusTurnInFlight(t *testing.T) {
+	cfg := defaultConfig()
+	now := time.Date(2024, 1, 2, 3, 10, 0, 0, time.UTC)
+	rec := SessionRecord{
+		Provider:      ProviderClaude,
+		ID:            "sess-1",
+		Status:        StatusRunning,
+		LastSeen:      now.Add(-2 * time.Minute),
+		TurnStartedAt: ptrTime(now.Add(-2*time.Minute - 14*time.Second)),
+	}
+	status, reason := deriveStatus(rec, now, cfg)
+	if status != StatusRunning {
+		t.Fatalf("expected running, got %s", status)
+	}
+	if reason != "running for 2m14s" {
+		t.Fatalf("unexpected reason: %q", reason)
+	}
+
+	completeTurn(&rec, now.Add(-time.Minute))
+	rec.Status = StatusWaiting
+	if status, _ := deriveStatus(rec, now, cfg); status != StatusWaiting {
+		t.Fatalf("expected waiting after turn completes, got %s", status)
+	}
+}
+
+func TestClaudeHookTurns(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	send := func(event string) {
+		b, _ := json.Marshal(map[string]any{
+			"hook_event_name": event,
+			"session_id":      "sess-1",
+			"cwd":             "/tmp/proj",
+		})
+		if err := ingestClaudeHook(bytes.NewReader(b), io.Discard); err != nil {
+			t.Fatalf("ingestClaudeHook(%s): %v", event, err)
+		}
+	}
+	// Both boundaries land in the spool before a single drain.
+	send("UserPromptSubmit")
+	send("PreToolUse")
+	send("Stop")
+
+	if err := drainClaudeSpool(); err != nil {
+		t.Fatalf("drainClaudeSpool error: %v", err)
+	}
+	p, err := recordPath(ProviderClaude, "sess-1")
+	if err != nil {
+		t.Fatalf("recordPath error: %v", err)
+	}
+	rec, err := loadRecord(p)
+	if err != nil {
+		t.Fatalf("loadRecord error: %v", err)
+	}
+	if rec.TurnCount != 1 || len(rec.TurnDurationsMS) != 1 {
+		t.Fatalf("expected one completed turn, got count=%d samples=%d", rec.TurnCount, len(rec.TurnDurationsMS))
+	}
+	if turnInFlight(rec) {
+		t.Fatalf("expected no turn in flight")
+	}
+}
+
+func TestScanCodexTurns(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "rollout-turns.jsonl")
+	lines := []string{
+		`{"timestamp":"2024-01-02T03:00:00Z","type":"session_meta","payload":{"id":"session-1"}}`,
+		`{"timestamp":"2024-01-02T03:00:01Z","type":"event_msg","payload":{"type":"task_started"}}`,
+		`{"timestamp":"2024-01-02T03:00:31Z","type":"event_msg","payload":{"type":"task_complete"}}`,
+		`{"timestamp":"2024-01-02T03:01:00Z","type":"event_msg","payload":{"type":"task_started"}}`,
+	}
+	if err := os.WriteFile(fp, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("write rollout: %v", err)
+	}
+
+	var rec SessionRecord
+	if _, err := scanCodexTurns(fp, 0, &rec); err != nil {
+		t.Fatalf("scanCodexTurns error: %v", err)
+	}
+	if rec.TurnCount != 1 {
+		t.Fatalf("expected 1 completed turn, got %d", rec.TurnCount)
+	}
+	if len(rec.TurnDurationsMS) != 1 || rec.TurnDurationsMS[0] != 30000 {
+		t.Fatalf("unexpected durations: %v", rec.TurnDurationsMS)
+	}
+	if !turnInFlight(rec) {
+		t.Fatalf("expected second turn in flight")
+	}
+}
+
+func TestCachedCodexTurnsParsesOnlyAppendedLines(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "rollout-cache.jsonl")
+	first := `{"timestamp":"2024-01-02T03:00:01Z","type":"event_msg","payload":{"type":"task_started"}}` + "\n" +
+		`{"timestamp":"2024-01-02T03:00:31Z","type":"event_msg","payload":{"type":"task_complete"}}` + "\n"
+	partial := `{"timestamp":"2024-01-02T03:01:00Z","type":"event_msg",`
+	if err := os.WriteFile(fp, []byte(first+partial), 0o600); err != nil {
+		t.Fatalf("write rollout: %v", err)
+	}
+
+	cache := codexTurnCache{}
+	var rec SessionRecord
+	if !cachedCodexTurns(cache, fp, &rec) {
+		t.Fatalf("expected the first scan to fill the cache")
+	}
+	if cache[fp].Offset != int64(len(first)) || rec.TurnCount != 1 || turnInFlight(rec) {
+		t.Fatalf("unexpected first scan: offset=%d rec=%+v", cache[fp].Offset, rec)
+	}
+
+	// Unchanged file: served from the cache.
+	rec = SessionRecord{}
+	if cachedCodexTurns(cache, fp, &rec) || rec.TurnCount != 1 {
+		t.Fatalf("expected a cache hit, got %+v", rec)
+	}
+
+	// Completing the partial line starts the next turn without recounting.
+	f, err := os.OpenFile(fp, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open rollout: %v", err)
+	}
+	_, _ = f.WriteString(`"payload":{"type":"task_started"}}` + "\n")
+	_ = f.Close()
+	later := time.Now().Add(time.Second)
+	_ = os.Chtimes(fp, later, later)
+
+	rec = SessionRecord{}
+	if !cachedCodexTurns(cache, fp, &rec) {
+		t.Fatalf("expected the appended line to be parsed")
+	}
+	if rec.TurnCount != 1 || !turnInFlight(rec) || len(rec.Transitions) != 3 {
+		t.Fatalf("unexpected incremental scan: %+v", rec)
+	}
+	if st, _ := os.Stat(fp); cache[fp].Offset != st.Size() {
+		t.Fatalf("expected offset at end of file, got %d", cache[fp].Offset)
+	}
+}
@@ -19,6 +19,11 @@ const (
 
 	// Claude Code status line can update very frequently; this throttles disk writes.
 	defaultStatuslineMinWrite = 800 * time.Millisecond
+
+	// Number of completed turn durations kept per session for mean/p95.
+	maxTurnSamples = 50
+	// Number of status transitions kept per session for latency reporting.
+	maxTransitions = 500
 )
 
 type Provider string
@@ -92,9 +97,29 @@ type SessionRecord struct {
 	StatusReason         string     `json:"status_reason,omitempty"` // human readable
 	EndedAt              *time.Time `json:"ended_at,omitempty"`
 
+	// Turn tracking (Claude: UserPromptSubmit → Stop; Codex: task_started → task_complete)
+	TurnStartedAt   *time.Time `json:"turn_started_at,omitempty"` // set while a turn is in flight
+	TurnEndedAt     *time.Time `json:"turn_ended_at,omitempty"`
+	TurnCount       int        `json:"turn_count,omitempty"`
+	TurnDurationsMS []int64    `json:"turn_durations_ms,omitempty"` // most recent completed turns
+
+	// Event times seen in the scanned transcript/rollout tail (recent only;
+	// not persisted)
+	Activity []time.Time `json:"-"`
+
+	// Status history (bounded; oldest dropped first)
+	Transitions []StatusTransition `json:"transitions,omitempty"`
+
 	UpdatedAt time.Time `json:"updated_at,omitempty"` // when we last wrote this record
 }
 
+// StatusTransition records when a session entered a status.
+type StatusTransition struct {
+	At     time.Time `json:"at"`
+	Status Status    `json:"status"`
+	Reason string    `json:"reason,omitempty"`
+}
+
 type Config struct {
 	Redact         bool
 	ActiveWindow   time.Duration
@@ -118,6 +143,27 @@ type Config struct {
 	TailBytesClaude    int
 	HeaderScanLines    int
 	StatuslineMinWrite time.Duration
+	// Append aistat's segment to a chained statusLine's output
+	StatuslineSegment bool
+
+	// Claude PreToolUse policy gate
+	PolicyRules []PolicyRule
+	PolicyAudit bool
+
+	// How long a PermissionRequest hook waits for a TUI decision (0 disables)
+	PermissionHold time.Duration
+
+	// TUI theme ("auto", a built-in, or a file in the themes dir) and key
+	// rebindings (action -> keys)
+	TUITheme string
+	TUIKeys  map[string][]string
+	// Plain-text TUI for screen readers and dumb terminals
+	TUIAccessible bool
+
+	// What redaction does to projects, IDs, paths and messages
+	Redaction RedactionConfigFile
+	// redactor applies Redaction; built once when the config is loaded
+	redactor *redactor
 }
 
 type ConfigFile struct {
@@ -128,6 +174,44 @@ type ConfigFile struct {
 	MaxSessions        *int   `json:"max_sessions,omitempty"`
 	AllScanWindow      string `json:"all_scan_window,omitempty"`
 	StatuslineMinWrite string `json:"statusline_min_write,omitempty"`
+	StatuslineSegment  *bool  `json:"statusline_segment,omitempty"`
+
+	Policy         *PolicyConfigFile `json:"policy,omitempty"`
+	PermissionHold string            `json:"permission_hold,omitempty"`
+	TUI            *TUIConfigFile    `json:"tui,omitempty"`
+
+	Redaction *RedactionConfigFile `json:"redaction,omitempty"`
+}
+
+type TUIConfigFile struct {
+	Theme string              `json:"theme,omitempty"` // auto|dark|light|high-contrast|<file name>
+	Keys  map[string][]string `json:"keys,omitempty"`  // action -> keys
+
+	Accessible *bool `json:"accessible,omitempty"` // plain-text TUI (also --accessible, TERM=dumb)
+}
+
+type RedactionConfigFile struct {
+	Projects string `json:"projects,omitempty"` // keep (default) | hash
+	IDs      string `json:"ids,omitempty"`      // short (default) | hash
+	Paths    string `json:"paths,omitempty"`    // short (default) | base | hash
+	Messages string `json:"messages,omitempty"` // scrub (default) | hide
+
+	Aliases      map[string]string   `json:"aliases,omitempty"`       // project name -> alias
+	PathRules    []RedactionPathRule `json:"path_rules,omitempty"`    // first matching prefix wins
+	Patterns     []string            `json:"patterns,omitempty"`      // extra secret regexes for messages
+	PrivateHosts []string            `json:"private_hosts,omitempty"` // host suffixes whose URLs are masked
+	Salt         string              `json:"salt,omitempty"`          // default: generated into <app dir>/redact_salt
+}
+
+// RedactionPathRule replaces a path prefix ("~" expands) when redacting.
+type RedactionPathRule struct {
+	Prefix  string `json:"prefix"`
+	Replace string `json:"replace"`
+}
+
+type PolicyConfigFile struct {
+	Rules []PolicyRule `json:"rules,omitempty"`
+	Audit *bool        `json:"audit,omitempty"` // default true
 }
 
 var (
@@ -0,0 +1,656 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	prettytable "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// -------------------------
+// Uninstall
+// -------------------------
+
+func newUninstallCmd() *cobra.Command {
+	var (
+		dryRun      bool
+		yes         bool
+		skipClaude  bool
+		skipCodex   bool
+		listBackups bool
+		jsonOut     bool
+		restore     string
+		prune       bool
+		keep        int
+		targetFlags installTargetFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove aistat's Claude hooks/statusline + Codex notify and its wrappers",
+		Long: `This will:
+- Remove aistat's hooks from ~/.claude/settings.json and put back the
+  statusLine it chained (or remove aistat's statusLine)
+- Point notify (and any profile's notify) in ~/.codex/config.toml back at the
+  program aistat chained, or remove it
+- Delete the wrapper scripts under the app directory once nothing uses them
+
+Without target flags this covers every config aistat was installed into; the
+install target flags (--scope, --claude-dir, --codex-home, --codex-profile)
+pick just some.
+
+Configs are backed up with a timestamp suffix first, as install does. Use
+--backups to list those backups, --restore to put one back and --prune to
+delete old ones. --dry-run shows a diff of every change instead.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if keep < 0 {
+				return errors.New("--keep must be >= 0")
+			}
+			claudeTargets, codexTargets, err := recordedTargets()
+			if targetFlags.explicit(cmd) {
+				if claudeTargets, err = targetFlags.claudeTargets(); err != nil {
+					return err
+				}
+				codexTargets, err = targetFlags.codexTargets()
+			}
+			if err != nil {
+				return err
+			}
+			if skipClaude {
+				claudeTargets = nil
+			}
+			if skipCodex {
+				codexTargets = nil
+			}
+			targets := backupTargets(claudeTargets, codexTargets)
+			switch {
+			case listBackups:
+				return printBackups(cmd.OutOrStdout(), targets, jsonOut)
+			case strings.TrimSpace(restore) != "":
+				return restoreBackups(targets, strings.TrimSpace(restore), dryRun)
+			case prune:
+				return pruneBackups(targets, keep, dryRun)
+			}
+
+			if skipClaude && skipCodex {
+				fmt.Println("Nothing to uninstall (both providers skipped).")
+				return nil
+			}
+			interactive := term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+			if interactive && !yes && !dryRun {
+				if !confirm("Remove aistat from Claude/Codex configs? [y/N]: ") {
+					fmt.Println("Aborted.")
+					return nil
+				}
+			}
+
+			var errs []string
+			for _, t := range claudeTargets {
+				if err := uninstallClaude(t, dryRun); err != nil {
+					errs = append(errs, "Claude: "+err.Error())
+				}
+			}
+			for _, t := range codexTargets {
+				if err := uninstallCodex(t, dryRun); err != nil {
+					errs = append(errs, "Codex: "+err.Error())
+				}
+			}
+			if len(errs) > 0 {
+				return errors.New(strings.Join(errs, "\n"))
+			}
+			if dryRun {
+				fmt.Println("Dry run complete.")
+			} else {
+				fmt.Println("Uninstall complete. Session data is kept; `aistat clean` removes it.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show a diff of the changes without writing files")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Don't ask for confirmation")
+	cmd.Flags().BoolVar(&skipClaude, "skip-claude", false, "Leave Claude Code alone")
+	cmd.Flags().BoolVar(&skipCodex, "skip-codex", false, "Leave Codex alone")
+	cmd.Flags().BoolVar(&listBackups, "backups", false, "List config backups made by install/uninstall")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output JSON (with --backups)")
+	cmd.Flags().StringVar(&restore, "restore", "", "Restore a backup: its path, or 'latest' for the newest of each config")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete old config backups")
+	cmd.Flags().IntVar(&keep, "keep", 1, "Backups to keep per config (with --prune)")
+	targetFlags.register(cmd)
+	return cmd
+}
+
+// isAistatHookCommand reports whether a Claude hook command is exactly one
+// aistat writes: the wrapper, in either mode, or a legacy direct
+// `aistat ingest claude-hook`. User hooks that merely mention aistat are left
+// alone.
+func isAistatHookCommand(command string) bool {
+	for _, c := range aistatHookCommands() {
+		if command == c {
+			return true
+		}
+	}
+	return false
+}
+
+func aistatHookCommands() []string {
+	cmds := []string{"aistat ingest claude-hook"}
+	if exe, err := os.Executable(); err == nil {
+		if exe, err = filepath.Abs(exe); err == nil {
+			cmds = append(cmds, shellEscape(exe)+" ingest claude-hook")
+		}
+	}
+	if ad, err := appDir(); err == nil {
+		hookCmd := shellEscape(filepath.Join(ad, "bin", "aistat-claude-hook"))
+		cmds = append(cmds, hookCmd, hookCmd+" --sync")
+	}
+	return cmds
+}
+
+// removeAistatHooks drops aistat's commands from Claude's hooks, then any
+// matcher entry and event left empty. It returns how many were removed.
+func removeAistatHooks(hooks map[string]any) int {
+	removed := 0
+	for event, v := range hooks {
+		arr, ok := v.([]any)
+		if !ok {
+			continue
+		}
+		var keptItems []any
+		for _, item := range arr {
+			mm, _ := item.(map[string]any)
+			hs, ok := mm["hooks"].([]any)
+			if !ok {
+				keptItems = append(keptItems, item)
+				continue
+			}
+			var kept []any
+			for _, h := range hs {
+				hm, _ := h.(map[string]any)
+				if isAistatHookCommand(asString(hm["command"])) {
+					removed++
+					continue
+				}
+				kept = append(kept, h)
+			}
+			if len(kept) == 0 {
+				continue
+			}
+			mm["hooks"] = kept
+			keptItems = append(keptItems, mm)
+		}
+		if len(keptItems) == 0 {
+			delete(hooks, event)
+		} else {
+			hooks[event] = keptItems
+		}
+	}
+	return removed
+}
+
+// uninstallClaude removes aistat's hooks from a settings file and restores
+// the statusLine it chained (or removes its own), then deletes the wrappers
+// unless another settings file still uses them.
+func uninstallClaude(t claudeTarget, dryRun bool) error {
+	ad, err := appDir()
+	if err != nil {
+		return err
+	}
+	settingsPath := t.Path
+	chain, err := loadClaudeStatuslineChain(settingsPath)
+	if err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(settingsPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		fmt.Printf("Claude settings not found: %s\n", settingsPath)
+	case err != nil:
+		return err
+	default:
+		var settings map[string]any
+		if err := json.Unmarshal(b, &settings); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", settingsPath, err)
+		}
+		if settings == nil {
+			settings = map[string]any{}
+		}
+
+		changed := false
+		if hooks, ok := settings["hooks"].(map[string]any); ok {
+			if n := removeAistatHooks(hooks); n > 0 {
+				fmt.Printf("Claude hooks: removing %d aistat command(s)\n", n)
+				changed = true
+			}
+			if len(hooks) == 0 {
+				delete(settings, "hooks")
+			}
+		}
+
+		sl, _ := settings["statusLine"].(map[string]any)
+		if isAistatStatusline(asString(sl["command"])) {
+			var prev map[string]any
+			if len(chain.StatusLine) > 0 {
+				_ = json.Unmarshal(chain.StatusLine, &prev)
+			}
+			if prev == nil && chain.Command != "" {
+				prev = map[string]any{"type": "command", "command": chain.Command}
+			}
+			if prev != nil {
+				settings["statusLine"] = prev
+				fmt.Printf("Claude statusLine: restoring %s\n", chain.Command)
+			} else {
+				delete(settings, "statusLine")
+				fmt.Printf("Claude statusLine: removing aistat's\n")
+			}
+			changed = true
+		}
+
+		if !changed {
+			fmt.Printf("No aistat entries in %s\n", settingsPath)
+		} else if err := writeConfigChange(settingsPath, b, settings, dryRun); err != nil {
+			return err
+		}
+	}
+
+	left := otherInstallTargets(ProviderClaude, settingsPath)
+	if !dryRun {
+		if err := saveClaudeStatuslineChain(settingsPath, claudeStatuslineChain{}); err != nil {
+			return err
+		}
+		if err := forgetInstallTarget(ProviderClaude, settingsPath); err != nil {
+			return err
+		}
+	}
+	if left > 0 {
+		return nil
+	}
+	removeFiles(dryRun,
+		filepath.Join(ad, "bin", "aistat-claude-hook"),
+		filepath.Join(ad, "bin", "aistat-claude-statusline"),
+	)
+	return nil
+}
+
+// uninstallCodex points notify of a config.toml (top level and profiles, or
+// just the profiles asked for) back at the program aistat chained, or removes
+// it, then deletes those wrappers.
+func uninstallCodex(t codexTarget, dryRun bool) error {
+	ad, err := appDir()
+	if err != nil {
+		return err
+	}
+	cfgPath := t.Path
+	chain, err := loadCodexNotifyChain(cfgPath)
+	if err != nil {
+		return err
+	}
+	exe, _ := os.Executable()
+	exe, _ = filepath.Abs(exe)
+
+	wrappers := codexNotifyWrappers(ad, cfgPath, t.Profiles)
+	b, err := os.ReadFile(cfgPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		fmt.Printf("Codex config not found: %s\n", cfgPath)
+	case err != nil:
+		return err
+	default:
+		content := string(b)
+		current, err := parseCodexNotify(content)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", cfgPath, err)
+		}
+		profiles := current.targets()
+		if len(t.Profiles) > 0 {
+			profiles = t.Profiles
+		}
+		updated := content
+		for _, profile := range profiles {
+			wrapper := filepath.Join(ad, "bin", codexNotifyWrapperName(cfgPath, profile))
+			switch classifyNotifyArgv(current.notify(profile), exe, wrapper) {
+			case codexNotifyWrapper, codexNotifyUnsafe:
+			default:
+				continue
+			}
+			label := "Codex notify"
+			if profile != "" {
+				label += " (profile " + profile + ")"
+			}
+			if prev := chain[profile]; len(prev) > 0 {
+				fmt.Printf("%s: restoring %s\n", label, strings.Join(prev, " "))
+				updated, err = setCodexNotify(updated, profile, prev)
+			} else {
+				fmt.Printf("%s: removing aistat's\n", label)
+				updated, err = unsetCodexNotify(updated, profile)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", cfgPath, err)
+			}
+			delete(chain, profile)
+		}
+
+		if updated == content {
+			fmt.Printf("No aistat notify in %s\n", cfgPath)
+		} else if dryRun {
+			fmt.Printf("Would update %s:\n%s", cfgPath, lineDiff(content, updated))
+		} else {
+			if err := backupConfig(cfgPath); err != nil {
+				return err
+			}
+			if err := os.WriteFile(cfgPath, []byte(updated), 0o600); err != nil {
+				return err
+			}
+			fmt.Printf("Updated %s\n", cfgPath)
+		}
+	}
+
+	if !dryRun {
+		if len(t.Profiles) == 0 {
+			chain = nil
+		}
+		if err := saveCodexNotifyChain(cfgPath, chain); err != nil {
+			return err
+		}
+		if len(t.Profiles) > 0 {
+			err = forgetCodexProfiles(cfgPath, t.Profiles)
+		} else {
+			err = forgetInstallTarget(ProviderCodex, cfgPath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	removeFiles(dryRun, wrappers...)
+	return nil
+}
+
+// codexNotifyWrappers lists the notify wrappers written for a config.toml (or
+// just for the profiles given). It looks at bin/ rather than the config, so
+// wrappers are found even after the config was deleted or edited by hand.
+func codexNotifyWrappers(ad, cfgPath string, profiles []string) []string {
+	bin := filepath.Join(ad, "bin")
+	if len(profiles) > 0 {
+		var out []string
+		for _, profile := range profiles {
+			out = append(out, filepath.Join(bin, codexNotifyWrapperName(cfgPath, profile)))
+		}
+		return out
+	}
+	base := codexNotifyWrapperName(cfgPath, "")
+	out := []string{filepath.Join(bin, base)}
+	more, _ := filepath.Glob(filepath.Join(bin, base+"-*"))
+	return append(out, more...)
+}
+
+// otherInstallTargets counts the recorded targets of a provider other than
+// path.
+func otherInstallTargets(provider Provider, path string) int {
+	targets, _ := loadInstallTargets()
+	n := 0
+	for _, t := range targets {
+		if t.Provider == provider && t.Path != path {
+			n++
+		}
+	}
+	return n
+}
+
+// writeConfigChange writes an edited JSON config (backing up the original),
+// or shows the diff on a dry run.
+func writeConfigChange(path string, before []byte, v any, dryRun bool) error {
+	after, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("Would update %s:\n%s", path, lineDiff(string(before), string(after)))
+		return nil
+	}
+	if err := backupConfig(path); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, after, 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s\n", path)
+	return nil
+}
+
+// removeFiles deletes wrapper scripts that exist, and the bin directory once
+// it is empty.
+func removeFiles(dryRun bool, paths ...string) {
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("Would remove %s\n", p)
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			fmt.Printf("Could not remove %s: %v\n", p, err)
+			continue
+		}
+		fmt.Printf("Removed %s\n", p)
+		_ = os.Remove(filepath.Dir(p)) // only succeeds when empty
+	}
+}
+
+// -------------------------
+// Config backups
+// -------------------------
+
+type configBackup struct {
+	Config string    `json:"config"`
+	Path   string    `json:"path"`
+	Taken  time.Time `json:"taken"`
+}
+
+// backupTargets lists the configs of install targets, which install and
+// uninstall back up.
+func backupTargets(claude []claudeTarget, codex []codexTarget) []string {
+	var out []string
+	for _, t := range claude {
+		out = append(out, t.Path)
+	}
+	for _, t := range codex {
+		out = append(out, t.Path)
+	}
+	return out
+}
+
+// configBackups returns the backups of a config, oldest first.
+func configBackups(config string) []configBackup {
+	matches, _ := filepath.Glob(config + ".bak.*")
+	var out []configBackup
+	for _, m := range matches {
+		ts := strings.TrimPrefix(m, config+".bak.")
+		taken, err := time.Parse(backupTimeLayout, ts)
+		if err != nil {
+			continue
+		}
+		out = append(out, configBackup{Config: config, Path: m, Taken: taken})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Taken.Before(out[j].Taken) })
+	return out
+}
+
+func printBackups(w io.Writer, targets []string, jsonOut bool) error {
+	all := []configBackup{}
+	for _, t := range targets {
+		all = append(all, configBackups(t)...)
+	}
+	if jsonOut {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(all)
+	}
+	if len(all) == 0 {
+		fmt.Fprintln(w, "No config backups.")
+		return nil
+	}
+	tw := prettytable.NewWriter()
+	tw.SetOutputMirror(w)
+	tw.SetStyle(prettytable.StyleLight)
+	tw.AppendHeader(prettytable.Row{"CONFIG", "BACKUP", "TAKEN"})
+	for _, b := range all {
+		tw.AppendRow(prettytable.Row{b.Config, filepath.Base(b.Path), b.Taken.Local().Format("2006-01-02 15:04:05")})
+	}
+	tw.Render()
+	return nil
+}
+
+// restoreBackups puts a backup back over its config (which is backed up
+// first). "latest" restores the newest backup of every config.
+func restoreBackups(targets []string, which string, dryRun bool) error {
+	var picks []configBackup
+	for _, t := range targets {
+		backups := configBackups(t)
+		if which == "latest" {
+			if len(backups) > 0 {
+				picks = append(picks, backups[len(backups)-1])
+			}
+			continue
+		}
+		for _, b := range backups {
+			if b.Path == which || filepath.Base(b.Path) == which {
+				picks = append(picks, b)
+			}
+		}
+	}
+	if len(picks) == 0 {
+		return fmt.Errorf("no backup matches %q; see `aistat uninstall --backups`", which)
+	}
+	for _, b := range picks {
+		want, err := os.ReadFile(b.Path)
+		if err != nil {
+			return err
+		}
+		have, _ := os.ReadFile(b.Config)
+		if dryRun {
+			fmt.Printf("Would restore %s from %s:\n%s", b.Config, b.Path, lineDiff(string(have), string(want)))
+			continue
+		}
+		if err := backupConfig(b.Config); err != nil {
+			return err
+		}
+		if err := os.WriteFile(b.Config, want, 0o600); err != nil {
+			return err
+		}
+		fmt.Printf("Restored %s from %s\n", b.Config, b.Path)
+	}
+	return nil
+}
+
+// pruneBackups deletes all but the newest keep backups of each config.
+func pruneBackups(targets []string, keep int, dryRun bool) error {
+	removed := 0
+	for _, t := range targets {
+		backups := configBackups(t)
+		if len(backups) <= keep {
+			continue
+		}
+		for _, b := range backups[:len(backups)-keep] {
+			if dryRun {
+				fmt.Printf("Would remove %s\n", b.Path)
+				removed++
+				continue
+			}
+			if err := os.Remove(b.Path); err != nil {
+				return err
+			}
+			removed++
+		}
+	}
+	if dryRun {
+		fmt.Printf("Would remove %d backup(s).\n", removed)
+	} else {
+		fmt.Printf("Removed %d backup(s).\n", removed)
+	}
+	return nil
+}
+
+// -------------------------
+// Diff
+// -------------------------
+
+const diffContext = 3
+
+// lineDiff renders a unified-style line diff (-/+ lines with a little
+// context) for dry runs.
+func lineDiff(before, after string) string {
+	a := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	c := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+
+	// Longest common subsequence table
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(c)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(c) - 1; j >= 0; j-- {
+			if a[i] == c[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type op struct {
+		kind byte // ' ', '-', '+'
+		text string
+	}
+	var ops []op
+	i, j := 0, 0
+	for i < len(a) || j < len(c) {
+		switch {
+		case i < len(a) && j < len(c) && a[i] == c[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(c) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', c[j]})
+			j++
+		}
+	}
+
+	// Show changes with diffContext unchanged lines around them
+	show := make([]bool, len(ops))
+	for k, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+		for d := max(0, k-diffContext); d <= min(len(ops)-1, k+diffContext); d++ {
+			show[d] = true
+		}
+	}
+	var b strings.Builder
+	gap := false
+	for k, o := range ops {
+		if !show[k] {
+			gap = true
+			continue
+		}
+		if gap && b.Len() > 0 {
+			b.WriteString("  ...\n")
+		}
+		gap = false
+		fmt.Fprintf(&b, "%c %s\n", o.kind, o.text)
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUninstallRestoresChainedOriginals(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+	t.Setenv("CODEX_HOME", filepath.Join(root, "codex"))
+
+	settingsPath := filepath.Join(root, ".claude", "settings.json")
+	settings := `{
+  "model": "opus",
+  "hooks": {
+    "Stop": [{"matcher": "*", "hooks": [{"type": "command", "command": "say done"}]}]
+  },
+  "statusLine": {"type": "command", "command": "~/bin/my-status", "padding": 2}
+}`
+	cfgPath := codexConfigPath()
+	claude := claudeTarget{Scope: claudeScopeUser, Path: settingsPath}
+	codex := codexTarget{Path: cfgPath}
+	cfg := "model = \"o3\"\nnotify = [\"/usr/local/bin/ding\", \"top\"]\n\n[profiles.work]\nmodel = \"gpt-5\"\n"
+	for path, content := range map[string]string{settingsPath: settings, cfgPath: cfg} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	if err := installClaude(claude, "/bin/true", false, false); err != nil {
+		t.Fatalf("installClaude error: %v", err)
+	}
+	if err := installCodex(codex, "/bin/true", false); err != nil {
+		t.Fatalf("installCodex error: %v", err)
+	}
+
+	// A dry run changes nothing
+	installed, _ := os.ReadFile(settingsPath)
+	if err := uninstallClaude(claude, true); err != nil {
+		t.Fatalf("uninstallClaude dry run error: %v", err)
+	}
+	if b, _ := os.ReadFile(settingsPath); string(b) != string(installed) {
+		t.Fatalf("dry run wrote settings")
+	}
+
+	if err := uninstallClaude(claude, false); err != nil {
+		t.Fatalf("uninstallClaude error: %v", err)
+	}
+	if err := uninstallCodex(codex, false); err != nil {
+		t.Fatalf("uninstallCodex error: %v", err)
+	}
+
+	b, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("parse settings: %v", err)
+	}
+	var want map[string]any
+	_ = json.Unmarshal([]byte(settings), &want)
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Fatalf("settings not restored:\n got %s\nwant %s", gotJSON, wantJSON)
+	}
+
+	b, err = os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	current, err := parseCodexNotify(string(b))
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if !slices.Equal(current.Top, []string{"/usr/local/bin/ding", "top"}) || len(current.Profiles) != 0 {
+		t.Fatalf("notify not restored: %+v\n%s", current, b)
+	}
+
+	bin := filepath.Join(root, "aistat", "bin")
+	if _, err := os.Stat(bin); !os.IsNotExist(err) {
+		entries, _ := os.ReadDir(bin)
+		t.Fatalf("wrappers left behind: %v", entries)
+	}
+	for _, name := range []string{"claude_statusline_chain.json", "codex_notify_chain.json"} {
+		if _, err := os.Stat(filepath.Join(root, "aistat", name)); !os.IsNotExist(err) {
+			t.Fatalf("%s left behind", name)
+		}
+	}
+
+	// Install and uninstall both backed up the configs
+	backups := configBackups(settingsPath)
+	if len(backups) == 0 {
+		t.Fatalf("no settings backups")
+	}
+	if err := pruneBackups([]string{settingsPath, cfgPath}, 0, false); err != nil {
+		t.Fatalf("prune error: %v", err)
+	}
+	if n := len(configBackups(settingsPath)) + len(configBackups(cfgPath)); n != 0 {
+		t.Fatalf("prune left %d backups", n)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\n"
+	after := "a\nb\nc\nd\nE\nf\ng\nh\ni\n"
+	got := lineDiff(before, after)
+	want := "  b\n  c\n  d\n- e\n+ E\n  f\n  g\n  h\n"
+	if got != want {
+		t.Fatalf("lineDiff =\n%s\nwant\n%s", got, want)
+	}
+	if strings.Contains(lineDiff(before, before), "-") {
+		t.Fatalf("identical input should have no changes")
+	}
+}
+
+func TestIsAistatHookCommandMatchesExactly(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+
+	wrapper := shellEscape(filepath.Join(root, "aistat", "bin", "aistat-claude-hook"))
+	for _, c := range []string{wrapper, wrapper + " --sync", "aistat ingest claude-hook"} {
+		if !isAistatHookCommand(c) {
+			t.Fatalf("expected %q to be aistat's", c)
+		}
+	}
+	for _, c := range []string{
+		"~/bin/log-aistat-claude-hook-events",
+		"echo aistat && ingest claude-hook-audit",
+		wrapper + " --verbose",
+		"",
+	} {
+		if isAistatHookCommand(c) {
+			t.Fatalf("expected %q to be left alone", c)
+		}
+	}
+}
+
+func TestUninstallCodexRemovesWrappersWithoutConfig(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("AISTAT_HOME", filepath.Join(root, "aistat"))
+	t.Setenv("CODEX_HOME", filepath.Join(root, "codex"))
+
+	cfgPath := codexConfigPath()
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	cfg := "notify = [\"/usr/local/bin/ding\"]\n\n[profiles.work]\nnotify = [\"/usr/local/bin/ding\", \"work\"]\n"
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	target := codexTarget{Path: cfgPath}
+	if err := installCodex(target, "/bin/true", false); err != nil {
+		t.Fatalf("installCodex error: %v", err)
+	}
+	bin := filepath.Join(root, "aistat", "bin")
+	if entries, _ := os.ReadDir(bin); len(entries) < 2 {
+		t.Fatalf("expected top-level and profile wrappers, got %v", entries)
+	}
+
+	if err := os.Remove(cfgPath); err != nil {
+		t.Fatalf("remove config: %v", err)
+	}
+	if err := uninstallCodex(target, false); err != nil {
+		t.Fatalf("uninstallCodex error: %v", err)
+	}
+	if _, err := os.Stat(bin); !os.IsNotExist(err) {
+		entries, _ := os.ReadDir(bin)
+		t.Fatalf("wrappers left behind: %v", entries)
+	}
+}
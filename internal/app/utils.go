@@ -45,6 +45,16 @@ func asString(v any) string {
 	}
 }
 
+func asInt(v any) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+	return 0
+}
+
 func normalizePlaceholder(s string) string {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -171,13 +181,16 @@ func redirectStderrToDevNull() {
 	_ = unix.Dup2(int(devNull.Fd()), int(os.Stderr.Fd()))
 }
 
-func spoolSummary() (int64, int) {
+// spoolSummary returns the size, count and oldest modification time of the
+// spool files.
+func spoolSummary() (int64, int, time.Time) {
 	sd, err := spoolDir()
 	if err != nil {
-		return 0, 0
+		return 0, 0, time.Time{}
 	}
 	var total int64
 	var count int
+	var oldest time.Time
 	var walk func(string)
 	walk = func(dir string) {
 		items, err := os.ReadDir(dir)
@@ -196,10 +209,13 @@ func spoolSummary() (int64, int) {
 			}
 			total += info.Size()
 			count++
+			if oldest.IsZero() || info.ModTime().Before(oldest) {
+				oldest = info.ModTime()
+			}
 		}
 	}
 	walk(sd)
-	return total, count
+	return total, count, oldest
 }
 
 func humanBytes(n int64) string {
@@ -279,45 +295,6 @@ func shortenPath(p string, keep int) string {
 	return "…/" + strings.Join(parts[len(parts)-keep:], "/")
 }
 
-func redactPath(p string) string {
-	if p == "" {
-		return ""
-	}
-	return shortenPath(p, 2)
-}
-
-func maybeRedactPath(p string, redact bool) string {
-	if !redact {
-		return p
-	}
-	return redactPath(p)
-}
-
-func redactProject(p string) string {
-	return p
-}
-
-func redactIDIfNeeded(id string, redact bool) string {
-	if !redact {
-		return id
-	}
-	id = strings.TrimSpace(id)
-	if len(id) <= 10 {
-		return id
-	}
-	return id[:6] + "…" + id[len(id)-3:]
-}
-
-func redactMessageIfNeeded(s string, redact bool) string {
-	if !redact {
-		return s
-	}
-	if s == "" {
-		return ""
-	}
-	return "<redacted>"
-}
-
 func fmtAgo(d time.Duration) string {
 	if d < 0 {
 		d = -d
@@ -336,6 +313,24 @@ func fmtAgo(d time.Duration) string {
 	}
 }
 
+// fmtElapsed formats a duration with two units, e.g. "45s", "2m14s", "1h05m".
+func fmtElapsed(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%02dh", int(d.Hours()/24), int(d.Hours())%24)
+	}
+}
+
 func minInt(a, b int) int {
 	if a < b {
 		return a
@@ -385,6 +380,10 @@ func parseFields(vals []string, defaults []string) ([]string, error) {
 		"cost":           true,
 		"last_user":      true,
 		"last_assistant": true,
+		"turns":          true,
+		"last_turn":      true,
+		"mean_turn":      true,
+		"p95_turn":       true,
 	}
 	seen := map[string]bool{}
 	var out []string
@@ -44,7 +44,7 @@ func TestShortenPath(t *testing.T) {
 
 func TestRedactIDIfNeeded(t *testing.T) {
 	id := "1234567890abcdef"
-	redacted := redactIDIfNeeded(id, true)
+	redacted := redactIDIfNeeded(defaultConfig().redaction(), id, true)
 	if redacted == id {
 		t.Fatalf("expected redaction")
 	}
@@ -52,7 +52,7 @@ func TestRedactIDIfNeeded(t *testing.T) {
 		t.Fatalf("expected ellipsis in redacted id")
 	}
 	short := "shortid"
-	if redactIDIfNeeded(short, true) != short {
+	if redactIDIfNeeded(defaultConfig().redaction(), short, true) != short {
 		t.Fatalf("short id should not be redacted")
 	}
 }
@@ -0,0 +1,245 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// -------------------------
+// Wait
+// -------------------------
+
+// exitTimeout is returned by `aistat wait` when the condition did not hold in time.
+const exitTimeout = 3
+
+// exitError carries a specific process exit code out of a command.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// waitConditions maps --for values to the statuses that satisfy them. Codex
+// never reports an explicit end, so a stale session counts as ended.
+var waitConditions = map[string][]Status{
+	"waiting":  {StatusWaiting},
+	"approval": {StatusApproval},
+	"ended":    {StatusEnded, StatusStale},
+	"idle":     {StatusWaiting, StatusApproval, StatusNeedsAttn, StatusEnded, StatusStale},
+}
+
+const waitSpoolPoll = 250 * time.Millisecond
+
+func newWaitCmd() *cobra.Command {
+	var (
+		flagFor      string
+		flagTimeout  string
+		flagInterval string
+		flagProvider string
+		flagProjects []string
+		flagAllIdle  bool
+		flagQuiet    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait [id]",
+		Short: "Block until a session (or every session in a project) reaches a state",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := loadConfig()
+			cfg.ProviderFilter = strings.TrimSpace(strings.ToLower(flagProvider))
+			cfg.ProjectFilters = normalizeList(flagProjects)
+			cfg.Redact = false
+			cfg.IncludeEnded = false
+
+			var timeout time.Duration
+			if strings.TrimSpace(flagTimeout) != "" {
+				d, err := time.ParseDuration(flagTimeout)
+				if err != nil || d < 0 {
+					return fmt.Errorf("invalid --timeout: %q", flagTimeout)
+				}
+				timeout = d
+			}
+			interval, err := time.ParseDuration(flagInterval)
+			if err != nil || interval <= 0 {
+				return fmt.Errorf("invalid --interval: %q", flagInterval)
+			}
+
+			var check func(time.Time) (bool, []SessionView, error)
+			switch {
+			case flagAllIdle:
+				if len(args) > 0 {
+					return errors.New("--all-idle does not take a session id")
+				}
+				if len(cfg.ProjectFilters) == 0 {
+					return errors.New("--all-idle requires --project")
+				}
+				check = func(now time.Time) (bool, []SessionView, error) {
+					return checkAllIdle(cfg, now)
+				}
+			case len(args) == 1:
+				want, ok := waitConditions[strings.TrimSpace(strings.ToLower(flagFor))]
+				if !ok {
+					return fmt.Errorf("invalid --for: %s (use waiting|approval|ended|idle)", flagFor)
+				}
+				provider, id, err := resolveWaitTarget(cfg, strings.TrimSpace(args[0]))
+				if err != nil {
+					return err
+				}
+				cfg.ProviderFilter = string(provider)
+				cfg.ProjectFilters = nil
+				check = func(now time.Time) (bool, []SessionView, error) {
+					return checkSession(cfg, provider, id, want, now)
+				}
+			default:
+				return errors.New("missing session id (or use --project <name> --all-idle)")
+			}
+
+			views, err := waitFor(check, timeout, interval)
+			if err != nil {
+				var ee *exitError
+				if errors.As(err, &ee) {
+					fmt.Fprintln(cmd.ErrOrStderr(), ee.Error())
+				}
+				return err
+			}
+			if !flagQuiet {
+				for _, v := range views {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\t%s\n", v.Provider, v.ID, v.Status, v.Reason)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flagFor, "for", "idle", "State to wait for: waiting|approval|ended|idle")
+	cmd.Flags().StringVar(&flagTimeout, "timeout", "", fmt.Sprintf("Give up after this duration (exit code %d); empty waits forever", exitTimeout))
+	cmd.Flags().StringVar(&flagInterval, "interval", "2s", "Full re-check interval when no spool changes are seen")
+	cmd.Flags().StringVar(&flagProvider, "provider", "", "Filter by provider: claude|codex")
+	cmd.Flags().StringSliceVar(&flagProjects, "project", nil, "Project name(s) for --all-idle (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&flagAllIdle, "all-idle", false, "Wait until every active session in --project is not running")
+	cmd.Flags().BoolVarP(&flagQuiet, "quiet", "q", false, "Do not print the matching sessions on success")
+	return cmd
+}
+
+// resolveWaitTarget finds the full provider/ID for a (possibly partial or
+// redacted) session ID, falling back to scanned sessions that have no record yet.
+func resolveWaitTarget(cfg Config, id string) (Provider, string, error) {
+	if rec, err := resolveRecord(cfg.redaction(), cfg.ProviderFilter, id); err == nil {
+		return rec.Provider, rec.ID, nil
+	}
+	records, err := gatherRecords(cfg, time.Now().UTC())
+	if err != nil {
+		return "", "", err
+	}
+	for _, r := range records {
+		if matchID(cfg.redaction(), r.ID, id) {
+			return r.Provider, r.ID, nil
+		}
+	}
+	return "", "", errors.New("session not found")
+}
+
+func checkSession(cfg Config, provider Provider, id string, want []Status, now time.Time) (bool, []SessionView, error) {
+	records, err := gatherRecords(cfg, now)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, r := range records {
+		if r.Provider != provider || r.ID != id {
+			continue
+		}
+		v := makeView(r, now, cfg)
+		return matchesStatus(v.Status, want), []SessionView{v}, nil
+	}
+	// Scanned-only sessions fall out of the scan once inactive: treat as stale.
+	v := SessionView{Provider: provider, ID: id, Status: StatusStale, Reason: "no longer active"}
+	return matchesStatus(v.Status, want), []SessionView{v}, nil
+}
+
+func checkAllIdle(cfg Config, now time.Time) (bool, []SessionView, error) {
+	records, err := gatherRecords(cfg, now)
+	if err != nil {
+		return false, nil, err
+	}
+	idle := true
+	var views []SessionView
+	for _, r := range records {
+		v := makeView(r, now, cfg)
+		if v.Status == StatusEnded || v.Status == StatusStale {
+			continue
+		}
+		if v.Status == StatusRunning {
+			idle = false
+		}
+		views = append(views, v)
+	}
+	sortSessions(views, "project")
+	return idle, views, nil
+}
+
+// waitFor re-runs check whenever the spool changes (hooks/notify just wrote)
+// and at least every interval, until it holds or the timeout expires.
+func waitFor(check func(time.Time) (bool, []SessionView, error), timeout, interval time.Duration) ([]SessionView, error) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	for {
+		ok, views, err := check(time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return views, nil
+		}
+
+		sig := spoolSignature()
+		next := time.Now().Add(interval)
+		for {
+			now := time.Now()
+			if !deadline.IsZero() && !now.Before(deadline) {
+				return views, &exitError{code: exitTimeout, err: fmt.Errorf("timed out after %s", timeout)}
+			}
+			if !now.Before(next) || spoolSignature() != sig {
+				break
+			}
+			time.Sleep(waitSpoolPoll)
+		}
+	}
+}
+
+// spoolSignature hashes spool file names and mod times, so a change in any
+// provider's spool can be detected without decoding anything.
+func spoolSignature() uint64 {
+	h := fnv.New64a()
+	sd, err := spoolDir()
+	if err != nil {
+		return 0
+	}
+	_ = filepath.WalkDir(sd, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		fmt.Fprintf(h, "%s|%d\n", path, info.ModTime().UnixNano())
+		return nil
+	})
+	if st, err := os.Stat(sd); err == nil {
+		fmt.Fprintf(h, "%d", st.ModTime().UnixNano())
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWaitForTimeout(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+
+	never := func(time.Time) (bool, []SessionView, error) { return false, nil, nil }
+	_, err := waitFor(never, 50*time.Millisecond, 10*time.Millisecond)
+	var ee *exitError
+	if !errors.As(err, &ee) || ee.code != exitTimeout {
+		t.Fatalf("expected timeout exit error, got %v", err)
+	}
+}
+
+func TestWaitForSession(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("AISTAT_HOME", root)
+	t.Setenv("CODEX_HOME", t.TempDir())
+
+	send := func(event string) {
+		b, _ := json.Marshal(map[string]any{
+			"hook_event_name": event,
+			"session_id":      "sess-wait",
+			"cwd":             "/tmp/alpha",
+		})
+		if err := ingestClaudeHook(bytes.NewReader(b), io.Discard); err != nil {
+			t.Fatalf("ingestClaudeHook(%s): %v", event, err)
+		}
+	}
+	send("UserPromptSubmit")
+
+	cfg := defaultConfig()
+	cfg.Redact = false
+	// Only explicit statuses matter here, not the recent-activity heuristic.
+	cfg.RunningWindow = time.Nanosecond
+	provider, id, err := resolveWaitTarget(cfg, "sess-w")
+	if err != nil || provider != ProviderClaude || id != "sess-wait" {
+		t.Fatalf("resolveWaitTarget: %s %s %v", provider, id, err)
+	}
+	want := waitConditions["waiting"]
+	if ok, _, err := checkSession(cfg, provider, id, want, time.Now().UTC()); err != nil || ok {
+		t.Fatalf("expected running session not to match waiting (ok=%v err=%v)", ok, err)
+	}
+	cfg.ProjectFilters = []string{"alpha"}
+	if ok, _, _ := checkAllIdle(cfg, time.Now().UTC()); ok {
+		t.Fatalf("expected project with a running turn not to be idle")
+	}
+
+	send("Stop")
+	cfg.ProjectFilters = nil
+	ok, views, err := checkSession(cfg, provider, id, want, time.Now().UTC())
+	if err != nil || !ok || len(views) != 1 {
+		t.Fatalf("expected waiting session to match (ok=%v err=%v)", ok, err)
+	}
+	cfg.ProjectFilters = []string{"alpha"}
+	if ok, _, _ := checkAllIdle(cfg, time.Now().UTC()); !ok {
+		t.Fatalf("expected project to be idle after Stop")
+	}
+}